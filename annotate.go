@@ -0,0 +1,52 @@
+package goerr
+
+import "fmt"
+
+// Annotate returns err with detail appended to the message of its outermost
+// layer, separated by "; ", without adding a layer. It suits functions
+// adding several notes as they progress, which would otherwise inflate the
+// stack with a layer per note. The layer keeps its location, and public
+// renderers show the annotated message unless the layer is masked. If err
+// is not a goerr, it is wrapped in a new goerr first.
+//
+//	err = goerr.Annotate(err, "retried 3 times")
+//	err = goerr.Annotate(err, "fallback cache empty")
+//	// "fetch rates failed; retried 3 times; fallback cache empty"
+func Annotate(err error, detail string) error {
+	if err == nil {
+		return nil
+	}
+
+	e := layer(err)
+	e.message += "; " + detail
+	return e
+}
+
+// Annotatef is like Annotate with a detail formatted like fmt.Sprintf.
+func Annotatef(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+
+	e := layer(err)
+	e.message += "; " + fmt.Sprintf(format, args...)
+	return e
+}
+
+// Redefine returns err with the message of its outermost layer replaced by
+// message, without adding a layer. The layer keeps its location, code and
+// fields. It suits callers of generic helpers wrapping errors with a
+// placeholder message, which can specialize it instead of wrapping it again.
+// If err is not a goerr, it is wrapped in a new goerr first.
+//
+//	err := fetch(ctx, url) // "fetch failed"
+//	return goerr.Redefine(err, "fetch exchange rates failed")
+func Redefine(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+
+	e := layer(err)
+	e.message = message
+	return e
+}
@@ -0,0 +1,64 @@
+package goerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestAnnotate(t *testing.T) {
+	base := goerr.New(errors.New("timeout"), 503, "fetch rates failed")
+	err := goerr.Annotate(base, "retried 3 times")
+	err = goerr.Annotatef(err, "fallback %s empty", "cache")
+
+	if want, got := "fetch rates failed; retried 3 times; fallback cache empty", err.Error(); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+	if want, got := "fetch rates failed", base.Error(); got != want {
+		t.Errorf("expected the original error to be unchanged. Want: %s, Got: %s", want, got)
+	}
+	if want, got := 2, strings.Count(goerr.Stack(err), "\n"); got != want {
+		t.Errorf("expected no new layer. Want: %d lines, Got: %d in %s", want+1, got+1, goerr.Stack(err))
+	}
+	if a, b := err.(*goerr.Error), base.(*goerr.Error); a.Line() != b.Line() || goerr.Code(err) != 503 {
+		t.Errorf("expected the layer to keep its location and code")
+	}
+}
+
+func TestAnnotateNonGoErr(t *testing.T) {
+	err := goerr.Annotate(errors.New("timeout"), "after 5s")
+
+	if want, got := "timeout; after 5s", err.Error(); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+	if goerr.Annotate(nil, "note") != nil || goerr.Annotatef(nil, "note") != nil {
+		t.Errorf("expected nil for a nil error")
+	}
+}
+
+func TestRedefine(t *testing.T) {
+	base := goerr.WithField(goerr.New(errors.New("timeout"), 503, "fetch failed"), "url", "https://rates")
+	err := goerr.Redefine(base, "fetch exchange rates failed")
+
+	if want, got := "fetch exchange rates failed", err.Error(); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+	if want, got := "fetch failed", base.Error(); got != want {
+		t.Errorf("expected the original error to be unchanged. Want: %s, Got: %s", want, got)
+	}
+	if want, got := 2, len(goerr.ListErrors(err)); got != want {
+		t.Errorf("expected no new layer. Want: %d layers, Got: %d", want, got)
+	}
+	if a, b := err.(*goerr.Error), base.(*goerr.Error); a.Line() != b.Line() || goerr.Code(err) != 503 || goerr.Fields(err)["url"] != "https://rates" {
+		t.Errorf("expected the layer to keep its location, code and fields")
+	}
+
+	if want, got := "rates unavailable", goerr.Redefine(errors.New("timeout"), "rates unavailable").Error(); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+	if goerr.Redefine(nil, "message") != nil {
+		t.Errorf("expected nil for a nil error")
+	}
+}
@@ -0,0 +1,150 @@
+package goerr
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// Fields set by FromAPIResponse from the error envelope of the body.
+const (
+	UpstreamCodeField    = "upstream_code"
+	UpstreamMessageField = "upstream_message"
+)
+
+// MaxDecodedBody is the number of bytes of a response body passed to a
+// BodyDecoder. Longer bodies are not decoded.
+var MaxDecodedBody = 64 << 10
+
+// APIError is the error envelope of the body of a failed API response.
+type APIError struct {
+	// Code is the error code of the upstream API, set as the string code of
+	// the error (see WithCodeString) and as the UpstreamCodeField field.
+	Code string
+	// Message is the error message of the upstream API, appended to the
+	// message of the error and set as the UpstreamMessageField field.
+	Message string
+	// Fields are added to the fields of the error.
+	Fields map[string]any
+}
+
+// A BodyDecoder decodes the error envelope of the body of a failed
+// response. It returns false if body is not an envelope it knows.
+type BodyDecoder func(body []byte) (APIError, bool)
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[string]BodyDecoder{}
+)
+
+// RegisterBodyDecoder sets the decoder used by FromAPIResponse for the
+// responses of host, like "api.stripe.com", instead of DecodeJSONEnvelope.
+// Passing a nil decoder removes it.
+//
+//	goerr.RegisterBodyDecoder("api.stripe.com", func(body []byte) (goerr.APIError, bool) {
+//		var env struct {
+//			Error struct{ Code, Message, Param string }
+//		}
+//		if json.Unmarshal(body, &env) != nil || env.Error.Code == "" {
+//			return goerr.APIError{}, false
+//		}
+//		return goerr.APIError{
+//			Code:    env.Error.Code,
+//			Message: env.Error.Message,
+//			Fields:  map[string]any{"param": env.Error.Param},
+//		}, true
+//	})
+func RegisterBodyDecoder(host string, d BodyDecoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	if d == nil {
+		delete(decoders, host)
+		return
+	}
+	decoders[host] = d
+}
+
+// DecodeBody sets the decoder of the error envelope of the response body,
+// overriding the one FromAPIResponse would use.
+func DecodeBody(d BodyDecoder) ResponseOption {
+	return func(o *responseOptions) {
+		o.decoder = d
+	}
+}
+
+// FromAPIResponse is FromHTTPResponse for JSON APIs: it also decodes the
+// error envelope of the body, with the decoder registered for the host of
+// the request (see RegisterBodyDecoder) or else DecodeJSONEnvelope, so that
+// the code and message of the upstream API end up in the error. A body the
+// decoder does not know is only kept as with FromHTTPResponse.
+//
+//	if err := goerr.FromAPIResponse(resp); err != nil {
+//		// POST https://api.example.com/payments: 422 Unprocessable Entity: amount must be positive
+//		return err
+//	}
+func FromAPIResponse(resp *http.Response, opts ...ResponseOption) error {
+	if resp == nil || resp.StatusCode < 400 {
+		return nil
+	}
+
+	return fromHTTPResponse(nil, resp, decoderOf(resp), opts)
+}
+
+// decoderOf returns the BodyDecoder registered for the host of the request
+// of resp, or DecodeJSONEnvelope.
+func decoderOf(resp *http.Response) BodyDecoder {
+	decoder := BodyDecoder(DecodeJSONEnvelope)
+	if resp.Request != nil && resp.Request.URL != nil {
+		decodersMu.RLock()
+		if d, ok := decoders[resp.Request.URL.Hostname()]; ok {
+			decoder = d
+		}
+		decodersMu.RUnlock()
+	}
+	return decoder
+}
+
+// DecodeJSONEnvelope decodes the common JSON error envelopes
+// {"code": ..., "message": ...} and {"error": {"code": ..., "message": ...}}.
+// A numeric code is kept as its decimal string.
+func DecodeJSONEnvelope(body []byte) (APIError, bool) {
+	type envelope struct {
+		Code    json.RawMessage `json:"code"`
+		Message string          `json:"message"`
+	}
+	var env struct {
+		envelope
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &env); err != nil {
+		return APIError{}, false
+	}
+
+	e := env.envelope
+	if len(env.Error) > 0 && env.Error[0] == '{' {
+		var nested envelope
+		if err := json.Unmarshal(env.Error, &nested); err != nil {
+			return APIError{}, false
+		}
+		e = nested
+	}
+
+	code := envelopeCode(e.Code)
+	if code == "" && e.Message == "" {
+		return APIError{}, false
+	}
+	return APIError{Code: code, Message: e.Message}, true
+}
+
+// envelopeCode returns the code of an envelope, a string or a number.
+func envelopeCode(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n.String()
+	}
+	return ""
+}
@@ -0,0 +1,156 @@
+package goerr_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestFromAPIResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		io.WriteString(w, `{"code":"invalid_amount","message":"amount must be positive"}`)
+	}))
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/payments", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	err = goerr.FromAPIResponse(resp)
+
+	want := "POST " + srv.URL + "/payments: 422 Unprocessable Entity: amount must be positive"
+	if got := err.Error(); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+	if got := goerr.Code(err); got != http.StatusUnprocessableEntity {
+		t.Errorf("Want: %d, Got: %d", http.StatusUnprocessableEntity, got)
+	}
+	if got := goerr.CodeString(err); got != "invalid_amount" {
+		t.Errorf("Want: invalid_amount, Got: %s", got)
+	}
+
+	fields := goerr.Fields(err)
+	wants := map[string]any{
+		goerr.UpstreamCodeField:    "invalid_amount",
+		goerr.UpstreamMessageField: "amount must be positive",
+		goerr.HTTPStatusField:      http.StatusUnprocessableEntity,
+	}
+	for k, v := range wants {
+		if fields[k] != v {
+			t.Errorf("%s. Want: %v, Got: %v", k, v, fields[k])
+		}
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if !json.Valid(body) {
+		t.Errorf("expected the whole body to be readable, Got: %q", body)
+	}
+	if !strings.Contains(goerr.Stack(err), "apiresponse_test.go") {
+		t.Errorf("expected the layer to point to the caller, Got: %s", goerr.Stack(err))
+	}
+}
+
+func TestFromAPIResponseUnknownBody(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusBadGateway,
+		Status:     "502 Bad Gateway",
+		Body:       io.NopCloser(strings.NewReader("<html>bad gateway</html>")),
+	}
+
+	err := goerr.FromAPIResponse(resp)
+
+	if got := err.Error(); got != "502 Bad Gateway" {
+		t.Errorf("Want: 502 Bad Gateway, Got: %s", got)
+	}
+	if got := goerr.CodeString(err); got != "" {
+		t.Errorf("expected no string code, Got: %s", got)
+	}
+	if got := goerr.Fields(err)[goerr.HTTPBodyField]; got != "<html>bad gateway</html>" {
+		t.Errorf("Want: <html>bad gateway</html>, Got: %v", got)
+	}
+}
+
+func TestFromAPIResponseSuccess(t *testing.T) {
+	if goerr.FromAPIResponse(&http.Response{StatusCode: http.StatusOK}) != nil {
+		t.Errorf("expected nil for a successful response")
+	}
+}
+
+func TestFromAPIResponseDecoder(t *testing.T) {
+	decoder := func(body []byte) (goerr.APIError, bool) {
+		var env struct {
+			Errors []struct{ Reason, Detail string }
+		}
+		if json.Unmarshal(body, &env) != nil || len(env.Errors) == 0 {
+			return goerr.APIError{}, false
+		}
+		return goerr.APIError{
+			Code:    env.Errors[0].Reason,
+			Message: env.Errors[0].Detail,
+			Fields:  map[string]any{"errors": len(env.Errors)},
+		}, true
+	}
+	goerr.RegisterBodyDecoder("partner.example.com", decoder)
+	defer goerr.RegisterBodyDecoder("partner.example.com", nil)
+
+	newResp := func(host string) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusConflict,
+			Status:     "409 Conflict",
+			Request:    &http.Request{Method: http.MethodPut, URL: &url.URL{Scheme: "https", Host: host, Path: "/v1/orders"}},
+			Body:       io.NopCloser(strings.NewReader(`{"errors":[{"reason":"duplicate","detail":"order exists"}]}`)),
+		}
+	}
+
+	err := goerr.FromAPIResponse(newResp("partner.example.com"))
+	if got := goerr.CodeString(err); got != "duplicate" {
+		t.Errorf("Want: duplicate, Got: %s", got)
+	}
+	if got := goerr.Fields(err)["errors"]; got != 1 {
+		t.Errorf("Want: 1, Got: %v", got)
+	}
+
+	// other hosts use the default envelope, which this body is not
+	err = goerr.FromAPIResponse(newResp("other.example.com"))
+	if got := goerr.CodeString(err); got != "" {
+		t.Errorf("expected no string code, Got: %s", got)
+	}
+
+	// an option overrides the registered decoder, also for FromHTTPResponse
+	err = goerr.FromHTTPResponse(newResp("other.example.com"), goerr.DecodeBody(decoder))
+	want := "PUT https://other.example.com/v1/orders: 409 Conflict: order exists"
+	if got := err.Error(); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+}
+
+func TestDecodeJSONEnvelope(t *testing.T) {
+	tests := []struct {
+		body string
+		want goerr.APIError
+		ok   bool
+	}{
+		{`{"code":"not_found","message":"no such order"}`, goerr.APIError{Code: "not_found", Message: "no such order"}, true},
+		{`{"error":{"code":404,"message":"no such order"}}`, goerr.APIError{Code: "404", Message: "no such order"}, true},
+		{`{"message":"no such order"}`, goerr.APIError{Message: "no such order"}, true},
+		{`{"error":"not_found"}`, goerr.APIError{}, false},
+		{`{"status":"failed"}`, goerr.APIError{}, false},
+		{`not json`, goerr.APIError{}, false},
+	}
+	for _, test := range tests {
+		got, ok := goerr.DecodeJSONEnvelope([]byte(test.body))
+		if ok != test.ok || got.Code != test.want.Code || got.Message != test.want.Message {
+			t.Errorf("%s. Want: %+v %v, Got: %+v %v", test.body, test.want, test.ok, got, ok)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package goerr
+
+// Arg returns an option recording the argument name of a failing call, like
+// a query or its parameters, as a field of the layer, see NewWithArgs. The
+// value is kept as is and only formatted when the layer is rendered, so
+// wrapping costs no formatting on hot paths where most errors are handled
+// without being logged. Redactors apply to it like to any field, by name.
+// Since the value is formatted late, slices and maps must not be modified
+// after they are passed.
+func Arg(name string, value any) Option {
+	return F(name, value)
+}
+
+// NewWithArgs is like New with the message used as is, without formatting,
+// and the arguments of the failing call recorded with Arg.
+//
+//	rows, err := db.QueryContext(ctx, q, params...)
+//	if err != nil {
+//		return goerr.NewWithArgs(err, "query failed", goerr.Arg("sql", q), goerr.Arg("params", params))
+//	}
+func NewWithArgs(nested error, message string, args ...Option) error {
+	e := newWithArgs(nested, message, args, 1)
+	notify(e)
+	return e
+}
+
+// newWithArgs creates the layer of NewWithArgs. skip is the same as for
+// newError. Hooks are not notified.
+func newWithArgs(nested error, message string, args []Option, skip int) *Error {
+	opts := make([]any, 0, len(args)+1)
+	opts = append(opts, message)
+	for _, a := range args {
+		opts = append(opts, a)
+	}
+	return newError(nested, opts, skip+1)
+}
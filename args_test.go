@@ -0,0 +1,52 @@
+package goerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+// counter counts how often it is formatted.
+type counter struct{ n *int }
+
+func (c counter) String() string {
+	*c.n++
+	return "counted"
+}
+
+func TestNewWithArgs(t *testing.T) {
+	formatted := 0
+	err := goerr.NewWithArgs(errors.New("syntax error"), "query 100% failed",
+		goerr.Arg("sql", "select * from orders where id = $1"),
+		goerr.Arg("params", []any{42}),
+		goerr.Arg("lazy", counter{&formatted}))
+
+	if formatted != 0 {
+		t.Errorf("expected the arguments not to be formatted before rendering")
+	}
+	if want, got := "query 100% failed", err.Error(); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+
+	stack := goerr.Stack(err)
+	for _, want := range []string{"sql=select * from orders where id = $1", "params=[42]", "lazy=counted"} {
+		if !strings.Contains(stack, want) {
+			t.Errorf("Want: %s in %s", want, stack)
+		}
+	}
+	if formatted != 1 {
+		t.Errorf("formatted. Want: 1, Got: %d", formatted)
+	}
+}
+
+func TestNewWithArgsRedacted(t *testing.T) {
+	goerr.Configure(goerr.Options{Redactors: []goerr.Redactor{goerr.KeyRedactor("params")}})
+	defer goerr.Configure(goerr.Options{})
+
+	err := goerr.NewWithArgs(nil, "query failed", goerr.Arg("params", []string{"secret"}))
+	if stack := goerr.Stack(err); strings.Contains(stack, "secret") || !strings.Contains(stack, "params="+goerr.Redacted) {
+		t.Errorf("expected the argument to be redacted, Got: %s", stack)
+	}
+}
@@ -0,0 +1,42 @@
+package goerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestVerifyChain(t *testing.T) {
+	goerr.SetAuditMode(true)
+	defer goerr.SetAuditMode(false)
+
+	inner := goerr.WithField(goerr.New(errors.New("db down"), 503, "query failed"), "table", "orders")
+	err := goerr.New(goerr.New(inner, "service failed"), "handler failed")
+	if verr := goerr.VerifyChain(err); verr != nil {
+		t.Fatalf("Want: nil, Got: %v", verr)
+	}
+	if verr := goerr.VerifyChain(goerr.WithField(err, "attempt", 2)); verr != nil {
+		t.Errorf("expected enrichment of the outermost layer to be allowed, Got: %v", verr)
+	}
+
+	// overwrite a layer in place, as a buggy or malicious middleware could
+	forged := goerr.WithField(inner, "table", "users")
+	*inner.(*goerr.Error) = *forged.(*goerr.Error)
+
+	verr := goerr.VerifyChain(err)
+	if verr == nil || !strings.Contains(verr.Error(), `layer 1 "service failed"`) {
+		t.Errorf("Want: chain modified below service failed, Got: %v", verr)
+	}
+}
+
+func TestVerifyChainWithoutAudit(t *testing.T) {
+	inner := goerr.New(nil, "query failed")
+	err := goerr.New(inner, "service failed")
+	*inner.(*goerr.Error) = *goerr.New(nil, "forged").(*goerr.Error)
+
+	if verr := goerr.VerifyChain(err); verr != nil {
+		t.Errorf("expected layers created outside audit mode not to be verified, Got: %v", verr)
+	}
+}
@@ -0,0 +1,189 @@
+package goerr
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// binaryVersion is the first byte of the binary encoding, to tell it from
+// other data and to allow changing it later.
+const binaryVersion = 1
+
+var errBinaryFormat = errors.New("invalid binary error encoding")
+
+// MarshalBinary encodes the chain of err in a compact binary format, for
+// places where JSON is too large, like gRPC metadata or message headers.
+// Every layer keeps its message, code, severity, kind, fields (as strings)
+// and the file, line and function it was created in. Redactors apply as they
+// do for Stack. A nil error is encoded as nil.
+func MarshalBinary(err error) ([]byte, error) {
+	if err == nil {
+		return nil, nil
+	}
+
+	var layers [][]byte
+	r := walk(err, func(_ int, err error) bool {
+		layers = append(layers, appendLayer(nil, err))
+		return true
+	})
+	if r != walkDone {
+		layers = append(layers, appendLayer(nil, errors.New(r.marker())))
+	}
+
+	b := []byte{binaryVersion}
+	b = binary.AppendUvarint(b, uint64(len(layers)))
+	for _, l := range layers {
+		b = append(b, l...)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary decodes a chain encoded by MarshalBinary. The goerr layers
+// are decoded as *Error without a call stack beyond their own frame, and the
+// error wrapped by the innermost goerr layer as a plain error with its
+// message.
+func UnmarshalBinary(b []byte) (error, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	if b[0] != binaryVersion {
+		return nil, errBinaryFormat
+	}
+
+	d := decoder{b: b[1:]}
+	n := d.uvarint()
+	if d.bad || n == 0 || n > uint64(MaxChainDepth)+1 {
+		return nil, errBinaryFormat
+	}
+
+	layers := make([]error, n)
+	for i := range layers {
+		layers[i] = d.layer()
+	}
+	if d.bad || len(d.b) != 0 {
+		return nil, errBinaryFormat
+	}
+
+	for i := len(layers) - 2; i >= 0; i-- {
+		e, ok := layers[i].(*Error)
+		if !ok {
+			return nil, errBinaryFormat
+		}
+		e.err = layers[i+1]
+	}
+	return layers[0], nil
+}
+
+// appendLayer appends the encoding of err, which is a single layer, to b.
+func appendLayer(b []byte, err error) []byte {
+	e, ok := err.(*Error)
+	if !ok {
+		b = append(b, 0)
+		return appendString(b, redactMessage(nil, err.Error()))
+	}
+
+	b = append(b, 1)
+	b = appendString(b, redactMessage(e.factory, e.message))
+	b = binary.AppendVarint(b, int64(e.code))
+	b = append(b, byte(e.severity))
+	b = appendString(b, string(e.kind))
+
+	keys := sortedKeys(e.fields)
+	b = binary.AppendUvarint(b, uint64(len(keys)))
+	for _, k := range keys {
+		b = appendString(b, k)
+		b = appendString(b, fmt.Sprint(redactField(e.factory, k, e.fields[k])))
+	}
+
+	b = appendString(b, e.frames[0].File)
+	b = binary.AppendUvarint(b, uint64(e.frames[0].LineNumber))
+	return appendString(b, e.Func())
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// decoder reads the binary encoding, setting bad instead of failing on the
+// first error so that reads can be chained.
+type decoder struct {
+	b   []byte
+	bad bool
+}
+
+func (d *decoder) layer() error {
+	if d.byte() == 0 {
+		return errors.New(d.string())
+	}
+
+	e := &Error{
+		message:  d.string(),
+		code:     int(d.varint()),
+		severity: SeverityLevel(d.byte()),
+		kind:     Kind(d.string()),
+	}
+
+	n := d.uvarint()
+	if n > uint64(len(d.b)) {
+		d.bad = true
+		return e
+	}
+	if n > 0 {
+		e.fields = make(map[string]any, n)
+		for i := uint64(0); i < n; i++ {
+			k := d.string()
+			e.fields[k] = d.string()
+		}
+	}
+
+	frame := StackFrame{File: d.string(), LineNumber: int(d.uvarint())}
+	if fn := d.string(); fn != "" {
+		frame.Package, frame.Name = splitFuncName(fn)
+	}
+	e.frames = []StackFrame{frame}
+	e.stack = make([]uintptr, 1)
+	return e
+}
+
+func (d *decoder) byte() byte {
+	if len(d.b) == 0 {
+		d.bad = true
+		return 0
+	}
+	c := d.b[0]
+	d.b = d.b[1:]
+	return c
+}
+
+func (d *decoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.bad = true
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	v, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.bad = true
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) string() string {
+	n := d.uvarint()
+	if n > uint64(len(d.b)) {
+		d.bad = true
+		return ""
+	}
+	s := string(d.b[:n])
+	d.b = d.b[n:]
+	return s
+}
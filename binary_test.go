@@ -0,0 +1,75 @@
+package goerr_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	err := goerr.WithSeverity(goerr.WithField(goerr.New(samplesrc.Service(), http.StatusConflict, "controller failed"), "order_id", 42), goerr.SeverityCritical)
+	err = goerr.WithKind(err, goerr.KindConflict)
+
+	b, e := goerr.MarshalBinary(err)
+	if e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+	decoded, e := goerr.UnmarshalBinary(b)
+	if e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+
+	if want, got := goerr.Stack(err), goerr.Stack(decoded); want != got {
+		t.Errorf("Want: %s\nGot: %s", want, got)
+	}
+	if want, got := goerr.ListErrors(err), goerr.ListErrors(decoded); len(want) != len(got) {
+		t.Errorf("Want: %q, Got: %q", want, got)
+	}
+	if got := goerr.Code(decoded); got != http.StatusConflict {
+		t.Errorf("Want: %d, Got: %d", http.StatusConflict, got)
+	}
+	if got := goerr.Severity(decoded); got != goerr.SeverityCritical {
+		t.Errorf("Want: %v, Got: %v", goerr.SeverityCritical, got)
+	}
+	if got := goerr.Fields(decoded)["order_id"]; got != "42" {
+		t.Errorf("Want: 42, Got: %v", got)
+	}
+
+	var e1, e2 *goerr.Error
+	if !errors.As(err, &e1) || !errors.As(decoded, &e2) || e1.Func() != e2.Func() || e1.File() != e2.File() || e1.Line() != e2.Line() {
+		t.Errorf("expected the frame to survive")
+	}
+
+	// the public JSON leaves out the locations, so compare with the dump
+	j, _ := json.Marshal(goerr.Dump(err))
+	if len(b) >= len(j) {
+		t.Errorf("expected the binary encoding to be smaller than the JSON dump, Got: %d >= %d", len(b), len(j))
+	}
+}
+
+func TestBinaryPlainError(t *testing.T) {
+	if b, err := goerr.MarshalBinary(nil); b != nil || err != nil {
+		t.Errorf("expected nil for a nil error")
+	}
+
+	b, _ := goerr.MarshalBinary(bytes.ErrTooLarge)
+	decoded, err := goerr.UnmarshalBinary(b)
+	if err != nil || decoded.Error() != bytes.ErrTooLarge.Error() {
+		t.Errorf("Want: %v, Got: %v, %v", bytes.ErrTooLarge, decoded, err)
+	}
+}
+
+func TestBinaryInvalid(t *testing.T) {
+	b, _ := goerr.MarshalBinary(samplesrc.Service())
+
+	for _, invalid := range [][]byte{{0}, {1}, {1, 0}, b[:len(b)-1], append(b, 0)} {
+		if _, err := goerr.UnmarshalBinary(invalid); err == nil {
+			t.Errorf("expected an error for %v", invalid)
+		}
+	}
+}
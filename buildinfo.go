@@ -0,0 +1,80 @@
+package goerr
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// buildInfo is the build metadata set with SetBuildInfo.
+type buildInfo struct {
+	version, commit string
+	// line is the last line of stacks
+	line string
+}
+
+var buildMeta atomic.Pointer[buildInfo]
+
+// SetBuildInfo sets the version and commit of the running binary, which
+// Stack renders on a last line like "build: version=v1.4.2 commit=9f2c1e7",
+// and the JSON marshaler under the "build" key, so errors reported by
+// several deployed versions tell which binary produced them. It is meant to
+// be called once at startup, e.g. with values set by the linker. Passing
+// empty strings removes the metadata.
+//
+//	var version, commit string // set with -ldflags "-X main.version=..."
+//
+//	func main() {
+//		goerr.SetBuildInfo(version, commit)
+//		...
+//	}
+func SetBuildInfo(version, commit string) {
+	if version == "" && commit == "" {
+		buildMeta.Store(nil)
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("build:")
+	if version != "" {
+		b.WriteString(" version=")
+		b.WriteString(version)
+	}
+	if commit != "" {
+		b.WriteString(" commit=")
+		b.WriteString(commit)
+	}
+	buildMeta.Store(&buildInfo{version: version, commit: commit, line: b.String()})
+}
+
+// BuildInfo returns the version and commit set with SetBuildInfo.
+func BuildInfo() (version, commit string) {
+	if bi := buildMeta.Load(); bi != nil {
+		return bi.version, bi.commit
+	}
+	return "", ""
+}
+
+// jsonBuild is the build metadata in the JSON document of an error.
+type jsonBuild struct {
+	Version string `json:"version,omitempty"`
+	Commit  string `json:"commit,omitempty"`
+}
+
+// newJSONBuild returns the build metadata for the JSON document of an
+// error, or nil if none is set.
+func newJSONBuild() *jsonBuild {
+	bi := buildMeta.Load()
+	if bi == nil {
+		return nil
+	}
+	return &jsonBuild{Version: bi.version, Commit: bi.commit}
+}
+
+// writeBuild writes the build line of stacks on a new line, if build
+// metadata is set.
+func writeBuild(b *strings.Builder) {
+	if bi := buildMeta.Load(); bi != nil {
+		b.WriteByte('\n')
+		b.WriteString(bi.line)
+	}
+}
@@ -0,0 +1,42 @@
+package goerr_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestSetBuildInfo(t *testing.T) {
+	goerr.SetBuildInfo("v1.4.2", "9f2c1e7")
+	defer goerr.SetBuildInfo("", "")
+
+	if v, c := goerr.BuildInfo(); v != "v1.4.2" || c != "9f2c1e7" {
+		t.Errorf("Want: v1.4.2 9f2c1e7, Got: %s %s", v, c)
+	}
+
+	err := goerr.New(goerr.New(nil, "query failed"), "load failed")
+	stack := goerr.Stack(err)
+	if !strings.HasSuffix(stack, "\nbuild: version=v1.4.2 commit=9f2c1e7") {
+		t.Errorf("expected the stack to end with the build line, Got: %s", stack)
+	}
+	if frames, _ := goerr.ParseStack(stack); len(frames) != 2 {
+		t.Errorf("expected ParseStack to skip the build line, Got: %+v", frames)
+	}
+
+	b, _ := json.Marshal(err)
+	if !strings.Contains(string(b), `"build":{"version":"v1.4.2","commit":"9f2c1e7"}`) {
+		t.Errorf("expected the json to contain the build, Got: %s", b)
+	}
+
+	goerr.SetBuildInfo("", "abc")
+	if stack := goerr.Stack(goerr.New(nil, "failed")); !strings.HasSuffix(stack, "]\nbuild: commit=abc") {
+		t.Errorf("Got: %s", stack)
+	}
+
+	goerr.SetBuildInfo("", "")
+	if stack := goerr.Stack(err); strings.Contains(stack, "build:") {
+		t.Errorf("expected no build line, Got: %s", stack)
+	}
+}
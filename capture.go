@@ -0,0 +1,34 @@
+package goerr
+
+import (
+	"os"
+	"sync/atomic"
+)
+
+var stacksDisabled atomic.Bool
+
+func init() {
+	stacksDisabled.Store(os.Getenv("GOERR_STACKS") == "0")
+}
+
+// SetStackCapture turns the capture of call stacks on or off. While it is
+// off, New and the other functions creating layers skip the capture, which
+// is most of their cost, and the layers have no file, line and function:
+// messages, codes and fields still work. It is on unless the environment
+// variable GOERR_STACKS is set to 0 when the program starts, and can be
+// switched at run time, e.g. from an admin endpoint during a debugging
+// window. Layers created while it was off stay without a stack.
+func SetStackCapture(on bool) {
+	stacksDisabled.Store(!on)
+}
+
+// DisableStacks turns the capture of call stacks off, see SetStackCapture.
+func DisableStacks() {
+	SetStackCapture(false)
+}
+
+// StackCapture reports whether call stacks are captured, see
+// SetStackCapture.
+func StackCapture() bool {
+	return !stacksDisabled.Load()
+}
@@ -0,0 +1,51 @@
+package goerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestDisableStacks(t *testing.T) {
+	if !goerr.StackCapture() {
+		t.Fatalf("expected stacks to be captured by default")
+	}
+	goerr.DisableStacks()
+	defer goerr.SetStackCapture(true)
+
+	err := goerr.New(goerr.New(errors.New("db down"), "query failed"), http.StatusServiceUnavailable, "service failed")
+	e := err.(*goerr.Error)
+	if e.File() != "" || e.Line() != 0 || e.Func() != "" || len(e.StackFrames()) != 0 {
+		t.Errorf("Want: no stack, Got: %s:%d (%s)", e.File(), e.Line(), e.Func())
+	}
+	if goerr.Code(err) != http.StatusServiceUnavailable || err.Error() != "service failed" {
+		t.Errorf("expected the code and message to be kept, Got: %s (%d)", err.Error(), goerr.Code(err))
+	}
+
+	want := "\nservice failed (503)\n\tquery failed\n\t\tdb down"
+	if got := goerr.Stack(err); got != want {
+		t.Errorf("Want: %q, Got: %q", want, got)
+	}
+	b, _ := json.Marshal(err)
+	if strings.Contains(string(b), `"file"`) {
+		t.Errorf("expected no files in the JSON output, Got: %s", b)
+	}
+
+	goerr.SetStackCapture(true)
+	if e := goerr.New(nil, "failed").(*goerr.Error); !strings.HasSuffix(e.Func(), "TestDisableStacks") {
+		t.Errorf("expected stacks to be captured again, Got: %s", e.Func())
+	}
+}
+
+func BenchmarkNewWithoutStack(b *testing.B) {
+	goerr.DisableStacks()
+	defer goerr.SetStackCapture(true)
+
+	for i := 0; i < b.N; i++ {
+		_ = goerr.New(nil, "failed")
+	}
+}
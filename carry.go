@@ -0,0 +1,111 @@
+package goerr
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CarryAcross returns err wrapped in a layer which marks the boundary of the
+// goroutine that produced it, before err is sent over a channel. The layer
+// records the ID of the producing goroutine and its whole call stack, which
+// Stack renders below the line of the layer. It returns nil if err is nil.
+//
+//	go func() {
+//		if err := produce(); err != nil {
+//			errs <- goerr.CarryAcross(err)
+//		}
+//	}()
+func CarryAcross(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	e := newCarried(err, 1)
+	notify(e)
+	return e
+}
+
+// newCarried creates the layer of CarryAcross. skip is the same as for
+// newError. Hooks are not notified.
+func newCarried(err error, skip int) *Error {
+	e := newError(err, nil, skip+1)
+	e.carried = true
+	e.goroutine = currentGoroutine()
+	return e
+}
+
+// ResumeFrom returns the chain of err continued by the chain of carried, as
+// received from another goroutine, so that the stack shows the frames of both
+// the consuming and the producing goroutine. The layers of err are copied,
+// with the innermost one wrapping carried and recording the ID of the
+// consuming goroutine. err is meant to be created with New(nil, ...); if its
+// chain ends in another error, only the message of err is kept. If err is
+// nil, carried is wrapped in a new layer attributed to the caller.
+//
+//	err := goerr.ResumeFrom(<-errs, goerr.New(nil, "consume failed"))
+func ResumeFrom(carried error, err error) error {
+	return resumeFrom(nil, carried, err)
+}
+
+// resumeFrom implements ResumeFrom, giving the layer it creates, if any, the
+// settings of f, or the global ones if f is nil.
+func resumeFrom(f *Factory, carried error, err error) error {
+	if carried == nil {
+		return err
+	}
+
+	if err == nil {
+		e := newError(carried, nil, 2)
+		e.goroutine = currentGoroutine()
+		return f.finish(e)
+	}
+
+	var layers []*Error
+	for next := err; next != nil; {
+		e, ok := next.(*Error)
+		if !ok || len(layers) == MaxChainDepth {
+			layers = nil
+			break
+		}
+		c := *e
+		layers = append(layers, &c)
+		next = e.err
+	}
+	if len(layers) == 0 {
+		e := newError(carried, []any{err.Error()}, 2)
+		e.goroutine = currentGoroutine()
+		return f.finish(e)
+	}
+
+	for i := 0; i < len(layers)-1; i++ {
+		layers[i].err = layers[i+1]
+	}
+	inner := layers[len(layers)-1]
+	inner.err = carried
+	if inner.goroutine == 0 {
+		inner.goroutine = currentGoroutine()
+	}
+	return layers[0]
+}
+
+// writeCarried writes the call stack of e, except its first frame which is
+// already rendered in the line of e and the exit of the goroutine, indented
+// by depth tabs.
+func writeCarried(b *strings.Builder, e *Error, depth int) {
+	var num [20]byte
+	for _, frame := range e.StackFrames()[1:] {
+		if frame.Package == "runtime" && frame.Name == "goexit" {
+			continue
+		}
+		writeIndent(b, depth)
+		b.WriteString("at ")
+		b.WriteString(formatPath(e.factory, &frame))
+		b.WriteByte(':')
+		b.Write(strconv.AppendInt(num[:0], int64(frame.LineNumber), 10))
+		b.WriteString(" (")
+		if fn := frame.Func(); fn != nil {
+			b.WriteString(formatFuncName(e.factory, fn.Name()))
+		}
+		b.WriteByte(')')
+	}
+}
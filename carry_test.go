@@ -0,0 +1,75 @@
+package goerr_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func produce(errs chan<- error) {
+	errs <- goerr.CarryAcross(goerr.New(nil, "produce failed"))
+}
+
+func TestCarryAcross(t *testing.T) {
+	if goerr.CarryAcross(nil) != nil {
+		t.Errorf("expected nil for a nil error")
+	}
+
+	errs := make(chan error)
+	go func() { produce(errs) }()
+	carried := <-errs
+
+	consumed := goerr.New(nil, "consume failed")
+	err := goerr.ResumeFrom(carried, goerr.New(consumed, "handler failed"))
+	stack := goerr.Stack(err)
+	t.Log(stack)
+
+	lines := strings.Split(stack, "\n")[1:]
+	wants := []string{
+		`^handler failed \[.*carry_test.go:\d+ \(goerr_test.TestCarryAcross\)\]$`,
+		`^\tconsume failed <goroutine \d+> \[.*carry_test.go:\d+ \(goerr_test.TestCarryAcross\)\]$`,
+		`^\t\tproduce failed <goroutine \d+> \[.*carry_test.go:\d+ \(goerr_test.produce\)\]$`,
+		`^\t\t\tat .*carry_test.go:\d+ \(goerr_test.TestCarryAcross.func1\)$`,
+		`^\t\t\tproduce failed \[.*carry_test.go:\d+ \(goerr_test.produce\)\]$`,
+	}
+	if len(lines) != len(wants) {
+		t.Fatalf("unexpected stack: %s", stack)
+	}
+	for i, want := range wants {
+		if !regexp.MustCompile(want).MatchString(lines[i]) {
+			t.Errorf("line %d. Want: %s, Got: %s", i, want, lines[i])
+		}
+	}
+
+	if !errors.Is(err, carried) {
+		t.Errorf("expected err to wrap the carried error")
+	}
+	if consumed.(*goerr.Error).Unwrap() != nil {
+		t.Errorf("expected the consumer error to stay unchanged")
+	}
+	var consumer uint64
+	goerr.Walk(err, func(l goerr.Layer) bool {
+		if l.Message == "consume failed" {
+			consumer = l.Goroutine
+		}
+		return true
+	})
+	if producer := goerr.GoroutineID(err); producer == 0 || consumer == 0 || producer == consumer {
+		t.Errorf("expected the IDs of both goroutines, Got: %d and %d", producer, consumer)
+	}
+}
+
+func TestResumeFromPlainError(t *testing.T) {
+	carried := goerr.CarryAcross(errors.New("timeout"))
+
+	err := goerr.ResumeFrom(carried, errors.New("consume failed"))
+	if err.Error() != "consume failed" || !errors.Is(err, carried) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if goerr.ResumeFrom(nil, err) != err {
+		t.Errorf("expected err without a carried error")
+	}
+}
@@ -0,0 +1,123 @@
+package goerr
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// A Definition describes an application error, so that errors of the same
+// kind are created consistently and can be listed in API documentation, see
+// Register and Catalog.
+type Definition struct {
+	// Name is the unique application code of the error, e.g.
+	// "order_not_found".
+	Name string `json:"name" yaml:"name"`
+	// Code is the HTTP status of the error. If it is 0, it is the code of
+	// Kind.
+	Code int `json:"code,omitempty" yaml:"code,omitempty"`
+	// Kind is the kind of the error, if any.
+	Kind Kind `json:"kind,omitempty" yaml:"kind,omitempty"`
+	// Message is the format of the message of the error. If it is empty, the
+	// message is Name.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// Description explains the error to API users.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+var (
+	definitionsMu sync.RWMutex
+	definitions   = map[string]*Definition{}
+)
+
+// Register adds d to the catalog of application errors and returns it. It
+// panics if d has no name or a definition with the same name is already
+// registered, so definitions should be registered in package variables:
+//
+//	var ErrOrderNotFound = goerr.Register(goerr.Definition{
+//		Name:        "order_not_found",
+//		Kind:        goerr.KindNotFound,
+//		Message:     "order %d not found",
+//		Description: "The order does not exist or belongs to another account.",
+//	})
+func Register(d Definition) *Definition {
+	if d.Name == "" {
+		panic("goerr: definition without a name")
+	}
+	if d.Code == 0 {
+		d.Code = d.Kind.Code()
+	}
+
+	definitionsMu.Lock()
+	defer definitionsMu.Unlock()
+	if _, ok := definitions[d.Name]; ok {
+		panic(fmt.Sprintf("goerr: definition %q registered twice", d.Name))
+	}
+	definitions[d.Name] = &d
+	return &d
+}
+
+// New returns nested wrapped in a new layer with the code, the kind and the
+// message of d, formatted with args.
+//
+//	return ErrOrderNotFound.New(err, id)
+func (d *Definition) New(nested error, args ...any) error {
+	format := d.Message
+	if format == "" {
+		format = d.Name
+	}
+	e := newError(nested, append([]any{d.Code, format}, args...), 1)
+	e.kind = d.Kind
+	e.def = d
+	notify(e)
+	return e
+}
+
+var definitionMatching atomic.Bool
+
+// SetDefinitionMatching turns matching by definition on or off. When it is
+// on, errors.Is reports that an error created by Definition.New matches any
+// other error created from a definition of the same name, not only the same
+// instance. A module can then compare the errors of another module against
+// a sentinel of its own, created from an unregistered definition, instead of
+// importing the error variables of the other module:
+//
+//	goerr.SetDefinitionMatching(true)
+//	...
+//	var errOrderNotFound = (&goerr.Definition{Name: "order_not_found"}).New(nil)
+//	...
+//	if errors.Is(err, errOrderNotFound) {
+//
+// It is off by default, where errors.Is compares goerr errors by identity.
+func SetDefinitionMatching(on bool) {
+	definitionMatching.Store(on)
+}
+
+// DefinitionOf returns the definition of the outermost layer of err created
+// by Definition.New, or nil if there is none.
+func DefinitionOf(err error) *Definition {
+	e := find(err, func(e *Error) bool { return e.def != nil })
+	if e == nil {
+		return nil
+	}
+	return e.def
+}
+
+// Catalog returns the registered definitions sorted by name. It marshals to
+// a JSON (or YAML) document listing the errors of a service, e.g. for the
+// error responses of its OpenAPI specification.
+//
+//	b, _ := json.MarshalIndent(goerr.Catalog(), "", "  ")
+//	os.WriteFile("errors.json", b, 0o644)
+func Catalog() []Definition {
+	definitionsMu.RLock()
+	defer definitionsMu.RUnlock()
+
+	catalog := make([]Definition, 0, len(definitions))
+	for _, d := range definitions {
+		catalog = append(catalog, *d)
+	}
+	sort.Slice(catalog, func(i, j int) bool { return catalog[i].Name < catalog[j].Name })
+	return catalog
+}
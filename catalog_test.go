@@ -0,0 +1,105 @@
+package goerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+var (
+	errCatalogOrderNotFound = goerr.Register(goerr.Definition{
+		Name:        "catalog_test.order_not_found",
+		Kind:        goerr.KindNotFound,
+		Message:     "order %d not found",
+		Description: "The order does not exist.",
+	})
+	errCatalogLimit = goerr.Register(goerr.Definition{
+		Name: "catalog_test.limit_exceeded",
+		Code: http.StatusTooManyRequests,
+	})
+)
+
+func TestDefinitionNew(t *testing.T) {
+	err := errCatalogOrderNotFound.New(errors.New("no rows"), 42)
+
+	if err.Error() != "order 42 not found" {
+		t.Errorf("Want: order 42 not found, Got: %s", err.Error())
+	}
+	if goerr.Code(err) != http.StatusNotFound || goerr.KindOf(err) != goerr.KindNotFound {
+		t.Errorf("Want: not_found (404), Got: %s (%d)", goerr.KindOf(err), goerr.Code(err))
+	}
+	if !strings.HasSuffix(err.(*goerr.Error).Func(), "TestDefinitionNew") {
+		t.Errorf("Want: the caller of New, Got: %s", err.(*goerr.Error).Func())
+	}
+	if d := goerr.DefinitionOf(goerr.New(err, "lookup failed")); d != errCatalogOrderNotFound {
+		t.Errorf("Want: the definition, Got: %v", d)
+	}
+	if goerr.DefinitionOf(goerr.New(nil, "failed")) != nil {
+		t.Errorf("Want: no definition")
+	}
+
+	if got := errCatalogLimit.New(nil).Error(); got != "catalog_test.limit_exceeded" {
+		t.Errorf("Want: the name as message, Got: %s", got)
+	}
+}
+
+func TestDefinitionMatching(t *testing.T) {
+	err := goerr.New(errCatalogOrderNotFound.New(nil, 42), "lookup failed")
+	sentinel := (&goerr.Definition{Name: "catalog_test.order_not_found"}).New(nil)
+	other := errCatalogLimit.New(nil)
+
+	if errors.Is(err, sentinel) {
+		t.Errorf("expected no match by definition while it is off")
+	}
+
+	goerr.SetDefinitionMatching(true)
+	defer goerr.SetDefinitionMatching(false)
+
+	if !errors.Is(err, sentinel) {
+		t.Errorf("expected a match by definition name")
+	}
+	if !errors.Is(err, errCatalogOrderNotFound.New(nil, 7)) {
+		t.Errorf("expected a match with another instance of the definition")
+	}
+	if errors.Is(err, other) || errors.Is(err, goerr.New(nil, "order 42 not found")) {
+		t.Errorf("expected no match with another definition or no definition")
+	}
+	if !errors.Is(err, goerr.KindNotFound) {
+		t.Errorf("expected the kind to still match")
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	for _, d := range []goerr.Definition{{}, {Name: "catalog_test.limit_exceeded"}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%q: expected a panic", d.Name)
+				}
+			}()
+			goerr.Register(d)
+		}()
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	var names []string
+	for _, d := range goerr.Catalog() {
+		if strings.HasPrefix(d.Name, "catalog_test.") {
+			names = append(names, d.Name)
+		}
+	}
+	if got := strings.Join(names, ","); got != "catalog_test.limit_exceeded,catalog_test.order_not_found" {
+		t.Errorf("Want: the definitions sorted by name, Got: %s", got)
+	}
+
+	b, _ := json.Marshal(goerr.Catalog())
+	want := `{"name":"catalog_test.order_not_found","code":404,"kind":"not_found","message":"order %d not found","description":"The order does not exist."}`
+	if !strings.Contains(string(b), want) {
+		t.Errorf("Want: %s, Got: %s", want, b)
+	}
+}
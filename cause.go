@@ -0,0 +1,32 @@
+package goerr
+
+import "errors"
+
+// Root returns the innermost error in the chain of err that is not a goerr,
+// like the original database or library error, following errors.Unwrap. It
+// returns nil if err is nil or consists of goerr layers only.
+func Root(err error) error {
+	var root error
+	for depth := 0; err != nil && depth < MaxChainDepth; depth++ {
+		if _, ok := err.(*Error); !ok {
+			root = err
+		}
+		err = errors.Unwrap(err)
+	}
+	return root
+}
+
+// CauseMessage returns the message of Root(err). If there is no such error,
+// the message of the innermost goerr layer is returned.
+func CauseMessage(err error) string {
+	if root := Root(err); root != nil {
+		return root.Error()
+	}
+
+	msg := ""
+	walk(err, func(_ int, err error) bool {
+		msg = err.Error()
+		return true
+	})
+	return msg
+}
@@ -0,0 +1,50 @@
+package goerr_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+func TestRoot(t *testing.T) {
+	repository := func() error {
+		return goerr.New(fmt.Errorf("query orders: %w", sql.ErrNoRows), 404, "order not found")
+	}
+	err := goerr.New(repository(), "service error")
+
+	if got := goerr.Root(err); got != sql.ErrNoRows {
+		t.Errorf("Want: %v, Got: %v", sql.ErrNoRows, got)
+	}
+	if got := goerr.CauseMessage(err); got != sql.ErrNoRows.Error() {
+		t.Errorf("Want: %s, Got: %s", sql.ErrNoRows.Error(), got)
+	}
+}
+
+func TestRootWithoutForeignError(t *testing.T) {
+	err := samplesrc.Controller()
+
+	if got := goerr.Root(err); got != nil {
+		t.Errorf("Want: nil, Got: %v", got)
+	}
+	if got := goerr.CauseMessage(err); got != "error from database" {
+		t.Errorf("Want: %s, Got: %s", "error from database", got)
+	}
+}
+
+func TestRootNonGoErr(t *testing.T) {
+	plain := errors.New("plain")
+
+	if got := goerr.Root(plain); got != plain {
+		t.Errorf("Want: %v, Got: %v", plain, got)
+	}
+	if got := goerr.Root(nil); got != nil {
+		t.Errorf("Want: nil, Got: %v", got)
+	}
+	if got := goerr.CauseMessage(nil); got != "" {
+		t.Errorf("Want empty message, Got: %s", got)
+	}
+}
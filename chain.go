@@ -0,0 +1,196 @@
+package goerr
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+)
+
+// MaxChainDepth is the maximum number of layers visited when an error chain
+// is rendered or inspected. Longer chains are truncated with a marker.
+var MaxChainDepth = 100
+
+// walkResult tells why walk stopped visiting an error chain.
+type walkResult int
+
+const (
+	walkDone walkResult = iota
+	walkTruncated
+	walkCycle
+	// walkStopped is returned by walkBranch when its function returned
+	// false, walkTree returns walkDone instead.
+	walkStopped
+)
+
+// marker returns the line rendered in place of the layers that were not
+// visited, or an empty string if the whole chain was visited.
+func (r walkResult) marker() string {
+	switch r {
+	case walkTruncated:
+		return "... chain truncated after " + strconv.Itoa(MaxChainDepth) + " layers"
+	case walkCycle:
+		return "... chain truncated, cycle detected"
+	}
+	return ""
+}
+
+// walk calls fn for err and every goerr layer nested in it, outermost first,
+// along with the depth of the layer. It stops after visiting an error that is
+// neither a goerr nor carries a call stack of its own (see next), when fn
+// returns false, after MaxChainDepth layers or when a layer is visited a
+// second time.
+func walk(err error, fn func(depth int, err error) bool) walkResult {
+	var buf [16]*Error
+	seen := buf[:0]
+
+	for depth := 0; err != nil; depth++ {
+		if depth >= MaxChainDepth {
+			return walkTruncated
+		}
+
+		e, ok := err.(*Error)
+		if ok {
+			for _, s := range seen {
+				if s == e {
+					return walkCycle
+				}
+			}
+			seen = append(seen, e)
+		}
+
+		if !fn(depth, err) {
+			return walkDone
+		}
+		if ok {
+			err = e.err
+		} else {
+			err = next(err)
+		}
+	}
+	return walkDone
+}
+
+// walkTree is like walk, but also visits the branches of the chain, for
+// renderers writing one line per layer: the chains of the causes of a layer
+// created by NewMulti are visited in order at the depth of the causes, and
+// the chains of the items of a Batch one level below it, with the first layer
+// of every item labeled by its index, like "[2] ". Other layers have an empty
+// label. MaxChainDepth limits the number of layers visited over all branches.
+func walkTree(err error, fn func(depth int, label string, err error) bool) walkResult {
+	var buf [16]*Error
+	budget := MaxChainDepth
+	if r := walkBranch(err, 0, "", buf[:0], &budget, fn); r != walkStopped {
+		return r
+	}
+	return walkDone
+}
+
+// walkBranch visits the chain of err for walkTree, starting at the given
+// depth, where seen are the goerr layers above it and budget the number of
+// layers left to visit.
+func walkBranch(err error, depth int, label string, seen []*Error, budget *int, fn func(depth int, label string, err error) bool) walkResult {
+	for ; err != nil; depth++ {
+		if c, ok := err.(causes); ok {
+			for _, err := range c {
+				if r := walkBranch(err, depth, "", seen, budget, fn); r != walkDone {
+					return r
+				}
+			}
+			return walkDone
+		}
+		if *budget == 0 {
+			return walkTruncated
+		}
+		*budget--
+
+		e, ok := err.(*Error)
+		if ok {
+			for _, s := range seen {
+				if s == e {
+					return walkCycle
+				}
+			}
+			seen = append(seen, e)
+		}
+
+		if !fn(depth, label, err) {
+			return walkStopped
+		}
+		label = ""
+		if b, ok := err.(*Batch); ok {
+			for _, item := range b.items {
+				if r := walkBranch(item.Err, depth+1, itemLabel(item.Index), seen, budget, fn); r != walkDone {
+					return r
+				}
+			}
+			return walkDone
+		}
+		if ok {
+			err = e.err
+		} else {
+			err = next(err)
+		}
+	}
+	return walkDone
+}
+
+// find returns the outermost goerr layer of err for which match returns true,
+// or nil if there is none.
+func find(err error, match func(e *Error) bool) *Error {
+	var found *Error
+	walk(err, func(_ int, err error) bool {
+		if e, ok := err.(*Error); ok && match(e) {
+			found = e
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// is is like errors.Is, but visits at most MaxChainDepth errors of the chain,
+// so it terminates even for a chain with a cycle.
+func is(err, target error) bool {
+	if target == nil {
+		return err == target
+	}
+	budget := MaxChainDepth
+	return isIn(err, target, reflect.TypeOf(target).Comparable(), &budget)
+}
+
+// isIn implements is, visiting at most budget errors, including the errors
+// joined by an error with an Unwrap() []error method.
+func isIn(err, target error, comparable bool, budget *int) bool {
+	for ; err != nil && *budget > 0; *budget-- {
+		if comparable && err == target {
+			return true
+		}
+		if x, ok := err.(interface{ Is(error) bool }); ok && x.Is(target) {
+			return true
+		}
+		if j, ok := err.(interface{ Unwrap() []error }); ok {
+			*budget--
+			for _, branch := range j.Unwrap() {
+				if isIn(branch, target, comparable, budget) {
+					return true
+				}
+			}
+			return false
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
+
+// branches returns the errors joined by the first error in the chain of err
+// that has an Unwrap() []error method, as created by errors.Join, or nil if
+// there is none.
+func branches(err error) []error {
+	for depth := 0; err != nil && depth < MaxChainDepth; depth++ {
+		if j, ok := err.(interface{ Unwrap() []error }); ok {
+			return j.Unwrap()
+		}
+		err = errors.Unwrap(err)
+	}
+	return nil
+}
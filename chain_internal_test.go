@@ -0,0 +1,27 @@
+package goerr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWalkCycle(t *testing.T) {
+	inner := New(nil, "inner").(*Error)
+	outer := New(inner, "outer").(*Error)
+	inner.err = outer
+
+	stacks := ListStacks(outer)
+	if len(stacks) != 3 {
+		t.Fatalf("Nr. of stack entries. Want: %d; Got: %d", 3, len(stacks))
+	}
+	if stacks[2] != "... chain truncated, cycle detected" {
+		t.Errorf("missing cycle marker. Got: %s", stacks[2])
+	}
+
+	if got := Stack(outer); !strings.HasSuffix(got, "\n\t\t... chain truncated, cycle detected") {
+		t.Errorf("stack do not end with the cycle marker: %s", got)
+	}
+	if got := Code(outer); got != 0 {
+		t.Errorf("Want: 0, Got: %d", got)
+	}
+}
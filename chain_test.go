@@ -0,0 +1,33 @@
+package goerr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestMaxChainDepth(t *testing.T) {
+	defer func(depth int) { goerr.MaxChainDepth = depth }(goerr.MaxChainDepth)
+	goerr.MaxChainDepth = 3
+
+	err := goerr.New(nil, 409, "attempt 0")
+	for i := 1; i < 10; i++ {
+		err = goerr.New(err, "attempt %d", i)
+	}
+
+	errs := goerr.ListErrors(err)
+	want := []string{"attempt 9", "attempt 8", "attempt 7", "... chain truncated after 3 layers"}
+	if strings.Join(errs, "|") != strings.Join(want, "|") {
+		t.Errorf("Want: %v, Got: %v", want, errs)
+	}
+
+	stack := goerr.Stack(err)
+	if !strings.HasSuffix(stack, "\n\t\t\t... chain truncated after 3 layers") {
+		t.Errorf("stack do not end with the truncation marker: %s", stack)
+	}
+
+	if got := goerr.Code(err); got != 0 {
+		t.Errorf("code beyond the depth limit was found. Got: %d", got)
+	}
+}
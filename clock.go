@@ -0,0 +1,80 @@
+package goerr
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// A Clock tells the time at which layers are created.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts an ordinary function to the Clock interface.
+type ClockFunc func() time.Time
+
+// Now calls f().
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// A StackCapturer captures the call stack of a new layer. It lets tests of
+// code built on goerr inject fixed files, lines and functions, e.g. to
+// compare rendered stacks with golden files.
+type StackCapturer interface {
+	// Capture returns the call stack of the function creating a layer,
+	// innermost frame first. skip is the number of frames to skip when
+	// runtime.Callers is called directly by Capture.
+	Capture(skip int) []StackFrame
+}
+
+// StackCapturerFunc adapts an ordinary function to the StackCapturer
+// interface.
+type StackCapturerFunc func(skip int) []StackFrame
+
+// Capture calls f, with skip adjusted for runtime.Callers being called
+// directly by f.
+func (f StackCapturerFunc) Capture(skip int) []StackFrame {
+	return f(skip + 1)
+}
+
+// FixedStack returns a StackCapturer that attributes every layer to the
+// given frames. The function of a frame is taken from its Package and Name.
+func FixedStack(frames ...StackFrame) StackCapturer {
+	return StackCapturerFunc(func(int) []StackFrame {
+		return append([]StackFrame(nil), frames...)
+	})
+}
+
+var (
+	clock    atomic.Pointer[Clock]
+	capturer atomic.Pointer[StackCapturer]
+)
+
+// SetClock sets the clock telling the time at which layers are created.
+// Passing nil restores the system clock.
+func SetClock(c Clock) {
+	if c == nil {
+		clock.Store(nil)
+		return
+	}
+	clock.Store(&c)
+}
+
+// SetStackCapturer sets how the call stacks of new layers are captured.
+// Passing nil restores the capture of the real call stack.
+func SetStackCapturer(c StackCapturer) {
+	if c == nil {
+		capturer.Store(nil)
+		return
+	}
+	capturer.Store(&c)
+}
+
+// now returns the time of the configured clock.
+func now() time.Time {
+	if c := clock.Load(); c != nil {
+		return (*c).Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,76 @@
+package goerr_test
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestSetClock(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	ticks := 0
+	goerr.SetClock(goerr.ClockFunc(func() time.Time {
+		ticks++
+		return at.Add(time.Duration(ticks) * time.Second)
+	}))
+	defer goerr.SetClock(nil)
+
+	inner := goerr.New(nil, "timeout")
+	err := goerr.New(inner, "retry failed")
+
+	if got := goerr.FirstOccurred(err); !got.Equal(at.Add(time.Second)) {
+		t.Errorf("Want: %v, Got: %v", at.Add(time.Second), got)
+	}
+	if got := goerr.StackVerbose(err); !strings.Contains(got, "retry failed [") || !strings.Contains(got, "] +1s\n") {
+		t.Errorf("expected an elapsed time of 1s, Got: %s", got)
+	}
+
+	goerr.SetClock(nil)
+	if got := goerr.FirstOccurred(goerr.New(nil, "now")); time.Since(got) > time.Minute {
+		t.Errorf("expected the system clock to be restored, Got: %v", got)
+	}
+}
+
+func TestSetStackCapturer(t *testing.T) {
+	goerr.SetStackCapturer(goerr.FixedStack(
+		goerr.StackFrame{File: "/src/app/handler.go", LineNumber: 42, Package: "example.com/app", Name: "Handle"},
+		goerr.StackFrame{File: "/src/app/main.go", LineNumber: 7, Package: "main", Name: "main"},
+	))
+	defer goerr.SetStackCapturer(nil)
+
+	err := goerr.New(nil, "handler failed")
+	e := err.(*goerr.Error)
+	if e.File() != "/src/app/handler.go" || e.Line() != 42 {
+		t.Errorf("Want: /src/app/handler.go:42, Got: %s:%d", e.File(), e.Line())
+	}
+	if e.Func() != "example.com/app.Handle" {
+		t.Errorf("Want: example.com/app.Handle, Got: %s", e.Func())
+	}
+	if got := len(e.StackFrames()); got != 2 {
+		t.Errorf("nr. of stack frames. Want: 2, Got: %d", got)
+	}
+
+	want := "handler failed [/src/app/handler.go:42 (app.Handle)]"
+	if got := goerr.Stack(err); got != want {
+		t.Errorf("Want: %q, Got: %q", want, got)
+	}
+}
+
+func TestStackCapturerSkip(t *testing.T) {
+	var skipped []goerr.StackFrame
+	goerr.SetStackCapturer(goerr.StackCapturerFunc(func(skip int) []goerr.StackFrame {
+		pcs := make([]uintptr, 1)
+		runtime.Callers(skip, pcs)
+		skipped = append(skipped, goerr.NewStackFrame(pcs[0]))
+		return skipped[len(skipped)-1:]
+	}))
+	defer goerr.SetStackCapturer(nil)
+
+	err := goerr.New(nil, "failed")
+	if got := err.(*goerr.Error).Func(); got != "github.com/angel-one/goerr_test.TestStackCapturerSkip" {
+		t.Errorf("Want: the caller of New, Got: %s", got)
+	}
+}
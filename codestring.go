@@ -0,0 +1,28 @@
+package goerr
+
+// WithCodeString returns err with the string code set on its outermost
+// layer, for API contracts using string enumerations like
+// "INSUFFICIENT_FUNDS" rather than, or along with, numeric codes. The JSON
+// marshaler and ToProblem render it under the "code_string" key. If err is
+// not a goerr, it is wrapped in a new goerr first.
+//
+//	return goerr.WithCodeString(goerr.New(err, http.StatusConflict, "balance too low"), "INSUFFICIENT_FUNDS")
+func WithCodeString(err error, code string) error {
+	if err == nil {
+		return nil
+	}
+
+	e := layer(err)
+	e.codeStr = code
+	return e
+}
+
+// CodeString returns the string code last given in the call chain of err
+// with WithCodeString, or an empty string.
+func CodeString(err error) string {
+	e := find(err, func(e *Error) bool { return e.codeStr != "" })
+	if e == nil {
+		return ""
+	}
+	return e.codeStr
+}
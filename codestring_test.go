@@ -0,0 +1,34 @@
+package goerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestCodeString(t *testing.T) {
+	inner := goerr.WithCodeString(goerr.New(errors.New("balance 10 < 25"), http.StatusConflict, "balance too low"), "INSUFFICIENT_FUNDS")
+	err := goerr.New(inner, "transfer failed")
+
+	if want, got := "INSUFFICIENT_FUNDS", goerr.CodeString(err); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+	if want, got := http.StatusConflict, goerr.Code(err); got != want {
+		t.Errorf("Want: %d, Got: %d", want, got)
+	}
+	if goerr.CodeString(errors.New("plain")) != "" || goerr.WithCodeString(nil, "X") != nil {
+		t.Errorf("expected no string code")
+	}
+
+	b, _ := json.Marshal(err)
+	if !strings.HasPrefix(string(b), `{"message":"transfer failed","code":409,"code_string":"INSUFFICIENT_FUNDS",`) {
+		t.Errorf("unexpected json: %s", b)
+	}
+	if want, got := "INSUFFICIENT_FUNDS", goerr.ToProblem(err).Extensions["code_string"]; got != want {
+		t.Errorf("Want: %s, Got: %v", want, got)
+	}
+}
@@ -0,0 +1,186 @@
+package goerr
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A Collector collects the errors of the items of a batch or fan-out
+// operation. It is safe for concurrent use; the zero value is ready to use.
+//
+//	var c goerr.Collector
+//	var wg sync.WaitGroup
+//	for i, order := range orders {
+//		wg.Add(1)
+//		go func(i int, order Order) {
+//			defer wg.Done()
+//			c.AddAt(i, process(order))
+//		}(i, order)
+//	}
+//	wg.Wait()
+//	return c.Err()
+type Collector struct {
+	mu    sync.Mutex
+	next  int
+	items []Item
+}
+
+// An Item is the error of one item collected by a Collector.
+type Item struct {
+	// Index is the index of the item in the batch.
+	Index int
+	Err   error
+}
+
+// Add records the result of the next item, whose index is the number of
+// previous calls to Add. A nil err records a success.
+func (c *Collector) Add(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.add(c.next, err)
+	c.next++
+}
+
+// AddAt records the result of the item with the given index. A nil err
+// records a success.
+func (c *Collector) AddAt(index int, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.add(index, err)
+}
+
+// add records err for the item with the given index.
+func (c *Collector) add(index int, err error) {
+	if err != nil {
+		c.items = append(c.items, Item{Index: index, Err: err})
+	}
+}
+
+// Count returns the number of errors collected so far.
+func (c *Collector) Count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Err returns a *Batch of the errors collected so far, or nil if there are
+// none.
+func (c *Collector) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.items) == 0 {
+		return nil
+	}
+
+	items := make([]Item, len(c.items))
+	copy(items, c.items)
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Index < items[j].Index })
+	return &Batch{items: items}
+}
+
+// Batch is the error of the failed items of a batch, as returned by
+// Collector.Err. Stack renders the chain of every item below it, and Code
+// returns its aggregate code unless an outer layer has a code.
+type Batch struct {
+	items []Item
+}
+
+// Count returns the number of failed items.
+func (b *Batch) Count() int {
+	return len(b.items)
+}
+
+// Items returns the failed items, ordered by index.
+func (b *Batch) Items() []Item {
+	return append([]Item(nil), b.items...)
+}
+
+// Code returns the code shared by all failed items, or else 500 if any of
+// them has a server error code or no code, or else 400.
+func (b *Batch) Code() int {
+	shared := Code(b.items[0].Err)
+	server := false
+	for _, item := range b.items {
+		c := Code(item.Err)
+		if c != shared {
+			shared = 0
+		}
+		if c == 0 || c >= 500 {
+			server = true
+		}
+	}
+	switch {
+	case shared != 0:
+		return shared
+	case server:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// Unwrap returns the errors of the failed items, so that errors.Is and
+// errors.As match any of them.
+func (b *Batch) Unwrap() []error {
+	errs := make([]error, len(b.items))
+	for i, item := range b.items {
+		errs[i] = item.Err
+	}
+	return errs
+}
+
+// Error returns the number of failed items followed by their indices and
+// messages.
+func (b *Batch) Error() string {
+	var s strings.Builder
+	s.WriteString(b.summary())
+	for i, item := range b.items {
+		if i == 0 {
+			s.WriteString(": ")
+		} else {
+			s.WriteString("; ")
+		}
+		s.WriteString(itemLabel(item.Index))
+		s.WriteString(item.Err.Error())
+	}
+	return s.String()
+}
+
+// summary returns the number of failed items.
+func (b *Batch) summary() string {
+	if len(b.items) == 1 {
+		return "1 item failed"
+	}
+	return strconv.Itoa(len(b.items)) + " items failed"
+}
+
+// itemLabel returns the label of the first stack line of the item with the
+// given index, see walkTree.
+func itemLabel(index int) string {
+	return "[" + strconv.Itoa(index) + "] "
+}
+
+// stackMessage returns the message of the stack line of err, which is not a
+// goerr layer: the number of failed items for a Batch, as its items are
+// rendered on lines of their own below it, or else the message of err.
+func stackMessage(err error) string {
+	if b, ok := err.(*Batch); ok {
+		return b.summary()
+	}
+	return err.Error()
+}
+
+// batchOf returns the Batch in the chain of err, or nil if there is none.
+func batchOf(err error) *Batch {
+	for depth := 0; err != nil && depth < MaxChainDepth; depth++ {
+		if b, ok := err.(*Batch); ok {
+			return b
+		}
+		err = errors.Unwrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package goerr_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestCollector(t *testing.T) {
+	var c goerr.Collector
+	if c.Err() != nil {
+		t.Errorf("Want: nil")
+	}
+
+	c.Add(nil)
+	c.Add(goerr.New(nil, http.StatusNotFound, "order 1 not found"))
+	c.Add(nil)
+	c.Add(goerr.New(errors.New("timeout"), "order 3 failed"))
+
+	if c.Count() != 2 {
+		t.Errorf("count. Want: 2, Got: %d", c.Count())
+	}
+
+	err := c.Err()
+	var b *goerr.Batch
+	if !errors.As(err, &b) {
+		t.Fatalf("Want: *goerr.Batch, Got: %T", err)
+	}
+	items := b.Items()
+	if b.Count() != 2 || items[0].Index != 1 || items[1].Index != 3 {
+		t.Errorf("Want: items 1 and 3, Got: %v", items)
+	}
+
+	want := "2 items failed: [1] order 1 not found; [3] order 3 failed"
+	if err.Error() != want {
+		t.Errorf("Want: %s, Got: %s", want, err.Error())
+	}
+	if goerr.Code(err) != http.StatusInternalServerError {
+		t.Errorf("code. Want: 500, Got: %d", goerr.Code(err))
+	}
+	if !errors.Is(err, items[1].Err) {
+		t.Errorf("expected errors.Is to match an item")
+	}
+}
+
+func TestCollectorConcurrent(t *testing.T) {
+	var c goerr.Collector
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				c.AddAt(i, goerr.New(nil, http.StatusNotFound, "not found"))
+			} else {
+				c.AddAt(i, nil)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var b *goerr.Batch
+	errors.As(c.Err(), &b)
+	if b.Count() != 25 {
+		t.Fatalf("count. Want: 25, Got: %d", b.Count())
+	}
+	for i, item := range b.Items() {
+		if item.Index != 2*i {
+			t.Errorf("index. Want: %d, Got: %d", 2*i, item.Index)
+		}
+	}
+	if goerr.Code(b) != http.StatusNotFound {
+		t.Errorf("code. Want: 404, Got: %d", goerr.Code(b))
+	}
+}
+
+func TestBatchCode(t *testing.T) {
+	for _, tc := range []struct {
+		codes []int
+		want  int
+	}{
+		{[]int{404, 404}, 404},
+		{[]int{404, 409}, 400},
+		{[]int{404, 503}, 500},
+		{[]int{404, 0}, 500},
+	} {
+		var c goerr.Collector
+		for _, code := range tc.codes {
+			c.Add(goerr.New(nil, code, "failed"))
+		}
+		if got := goerr.Code(c.Err()); got != tc.want {
+			t.Errorf("%v. Want: %d, Got: %d", tc.codes, tc.want, got)
+		}
+		if got := goerr.Code(goerr.New(c.Err(), http.StatusConflict, "import failed")); got != http.StatusConflict {
+			t.Errorf("expected the code of the outer layer. Want: 409, Got: %d", got)
+		}
+	}
+}
+
+func TestBatchStack(t *testing.T) {
+	var c goerr.Collector
+	c.Add(goerr.New(goerr.New(nil, "timeout"), "order 0 failed"))
+	c.Add(errors.New("invalid order"))
+	err := goerr.New(c.Err(), "import failed")
+
+	lines := strings.Split(goerr.Stack(err), "\n")[1:]
+	want := []string{
+		"import failed [",
+		"\t2 items failed",
+		"\t\t[0] order 0 failed [",
+		"\t\t\ttimeout [",
+		"\t\t[1] invalid order",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("nr. of lines. Want: %d, Got: %d\n%s", len(want), len(lines), goerr.Stack(err))
+	}
+	for i := range want {
+		if !strings.HasPrefix(lines[i], want[i]) {
+			t.Errorf("line %d. Want prefix: %q, Got: %q", i, want[i], lines[i])
+		}
+	}
+}
+
+func TestBatchListStacks(t *testing.T) {
+	var c goerr.Collector
+	c.Add(goerr.New(goerr.New(nil, "timeout"), "order 0 failed"))
+	c.Add(errors.New("invalid order"))
+
+	stacks := goerr.ListStacks(goerr.New(c.Err(), "import failed"))
+	want := []string{"import failed [", "2 items failed", "[0] order 0 failed [", "timeout [", "[1] invalid order"}
+	if len(stacks) != len(want) {
+		t.Fatalf("Want: one entry per layer %v, Got: %q", want, stacks)
+	}
+	for i := range want {
+		if !strings.HasPrefix(stacks[i], want[i]) || strings.Contains(stacks[i], "\n") {
+			t.Errorf("entry %d. Want prefix: %q, Got: %q", i, want[i], stacks[i])
+		}
+	}
+}
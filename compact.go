@@ -0,0 +1,148 @@
+package goerr
+
+import "strconv"
+
+// Compact returns err with its goerr layers beyond the outermost keep and
+// the innermost keep replaced by a single summary layer, like "7
+// intermediate layers", for pipelines that wrap errors at every stage. The
+// layers kept are copies, err is not modified. The summary layer has no
+// location, and takes the outermost code, string code, kind, severity,
+// definition, details, retry delay, exit code and weight of the layers it
+// replaces as well as their fields, domain codes and warnings, so that Code,
+// Severity, Fields and the like return the same for the compacted chain.
+// errors.Is no longer finds the replaced layers themselves.
+//
+// Layers created by Mask or hidden with Hide are never replaced, so public
+// renderers show the same for the compacted chain: the layers between them
+// are replaced by a summary layer each.
+//
+// Only the goerr layers above the first error of the chain that is not a
+// goerr layer are compacted; that error and the ones it wraps are kept. err
+// is returned unchanged if it has no more than 2*keep such layers.
+//
+//	log.Print(goerr.Stack(goerr.Compact(err, 3)))
+func Compact(err error, keep int) error {
+	if keep < 0 {
+		keep = 0
+	}
+
+	var layers []*Error
+	var tail error
+	for next := err; next != nil && len(layers) < MaxChainDepth; {
+		e, ok := next.(*Error)
+		if !ok {
+			tail = next
+			break
+		}
+		layers = append(layers, e)
+		next = e.err
+	}
+	if len(layers) == MaxChainDepth {
+		tail = layers[len(layers)-1].err
+	}
+	if len(layers) <= 2*keep {
+		return err
+	}
+
+	chain := make([]*Error, 0, 2*keep+1)
+	for _, e := range layers[:keep] {
+		c := *e
+		chain = append(chain, &c)
+	}
+
+	var summary *Error
+	summarized := 0
+	flush := func() {
+		if summary == nil {
+			return
+		}
+		summary.message = strconv.Itoa(summarized) + " intermediate layers"
+		if summarized == 1 {
+			summary.message = "1 intermediate layer"
+		}
+		chain = append(chain, summary)
+		summary, summarized = nil, 0
+	}
+	for _, e := range layers[keep : len(layers)-keep] {
+		if e.masks || e.hidden {
+			flush()
+			c := *e
+			chain = append(chain, &c)
+			continue
+		}
+		if summary == nil {
+			summary = &Error{frames: make([]StackFrame, 1), created: e.created}
+		}
+		summary.absorb(e)
+		summarized++
+	}
+	flush()
+
+	for _, e := range layers[len(layers)-keep:] {
+		c := *e
+		chain = append(chain, &c)
+	}
+
+	for i := 0; i < len(chain)-1; i++ {
+		chain[i].err = chain[i+1]
+	}
+	chain[len(chain)-1].err = tail
+	return chain[0]
+}
+
+// absorb sets the attributes of o on e where e has none, and adds the
+// fields, domain codes and warnings of o that e does not have, as if e were
+// the layer wrapping o. A kind implying a code counts as a code. The fields
+// of o are redacted as o renders them.
+func (e *Error) absorb(o *Error) {
+	if e.code == 0 && e.kind.Code() == 0 {
+		e.code = o.code
+	}
+	if e.codeStr == "" {
+		e.codeStr = o.codeStr
+	}
+	if e.kind == "" {
+		e.kind = o.kind
+	}
+	if e.severity == 0 {
+		e.severity = o.severity
+	}
+	if e.def == nil {
+		e.def = o.def
+	}
+	if e.details == nil {
+		e.details = o.details
+	}
+	if e.retry == 0 {
+		e.retry = o.retry
+	}
+	if e.exitCode == 0 {
+		e.exitCode = o.exitCode
+	}
+	if !e.weighted {
+		e.weight, e.weighted = o.weight, o.weighted
+	}
+	if len(o.fields) > 0 {
+		fields := make(map[string]any, len(e.fields)+len(o.fields))
+		for k, v := range o.fields {
+			fields[k] = redactField(o.factory, k, v)
+		}
+		for k, v := range e.fields {
+			fields[k] = v
+		}
+		e.fields = fields
+	}
+	if len(o.codes) > 0 {
+		codes := make(map[Domain]int, len(e.codes)+len(o.codes))
+		for d, c := range o.codes {
+			codes[d] = c
+		}
+		for d, c := range e.codes {
+			codes[d] = c
+		}
+		e.codes = codes
+	}
+	if len(o.warnings) > 0 {
+		e.warnings = append(e.warnings[:len(e.warnings):len(e.warnings)], o.warnings...)
+	}
+}
@@ -0,0 +1,119 @@
+package goerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestCompact(t *testing.T) {
+	root := errors.New("connection reset")
+	err := goerr.New(root, "stage 0")
+	for i := 1; i < 10; i++ {
+		err = goerr.New(err, "stage %d", i)
+	}
+	err = goerr.WithField(goerr.New(err, http.StatusBadGateway, "stage 10"), "stage", 10)
+
+	compacted := goerr.Compact(err, 2)
+
+	want := []string{"stage 10", "stage 9", "7 intermediate layers", "stage 1", "stage 0", "connection reset"}
+	got := goerr.ListErrors(compacted)
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Want: %v, Got: %v", want, got)
+	}
+	if !errors.Is(compacted, root) {
+		t.Errorf("expected the root cause to be kept")
+	}
+	if goerr.Code(compacted) != http.StatusBadGateway || goerr.Fields(compacted)["stage"] != 10 {
+		t.Errorf("Want: 502 stage=10, Got: %d %v", goerr.Code(compacted), goerr.Fields(compacted))
+	}
+	if len(goerr.ListErrors(err)) != 12 {
+		t.Errorf("expected err to be unchanged, Got: %v", goerr.ListErrors(err))
+	}
+
+	lines := strings.Split(goerr.Stack(compacted), "\n")
+	if len(lines) < 4 || strings.TrimSpace(lines[3]) != "7 intermediate layers" {
+		t.Errorf("expected the summary without a location, Got: %q", lines)
+	}
+}
+
+func TestCompactKeepsSummarizedAttributes(t *testing.T) {
+	err := goerr.New(nil, "query failed")
+	err = goerr.WithSeverity(goerr.New(err, http.StatusNotFound, "order not found"), goerr.SeverityWarning)
+	err = goerr.WithField(err, "order", 42)
+	err = goerr.New(err, "handler")
+	err = goerr.New(err, "middleware")
+
+	compacted := goerr.Compact(err, 1)
+
+	want := []string{"middleware", "2 intermediate layers", "query failed"}
+	if got := goerr.ListErrors(compacted); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Want: %v, Got: %v", want, got)
+	}
+	if goerr.Code(compacted) != http.StatusNotFound {
+		t.Errorf("Want: 404, Got: %d", goerr.Code(compacted))
+	}
+	if goerr.Severity(compacted) != goerr.SeverityWarning {
+		t.Errorf("Want: warning, Got: %s", goerr.Severity(compacted))
+	}
+	if goerr.Fields(compacted)["order"] != 42 {
+		t.Errorf("Want: 42, Got: %v", goerr.Fields(compacted)["order"])
+	}
+
+	if got := goerr.ListErrors(goerr.Compact(err, 0)); len(got) != 1 || got[0] != "4 intermediate layers" {
+		t.Errorf("Want: [4 intermediate layers], Got: %v", got)
+	}
+	if got := goerr.ListErrors(goerr.Compact(goerr.New(goerr.New(goerr.New(nil, "a"), "b"), "c"), 1)); got[1] != "1 intermediate layer" {
+		t.Errorf("Want: 1 intermediate layer, Got: %v", got)
+	}
+}
+
+func TestCompactShortChain(t *testing.T) {
+	err := goerr.New(goerr.New(nil, "inner"), "outer")
+	if goerr.Compact(err, 1) != err {
+		t.Errorf("expected a short chain to be returned unchanged")
+	}
+	if goerr.Compact(nil, 1) != nil {
+		t.Errorf("expected nil for nil")
+	}
+}
+
+func TestCompactKeepsPublicOutput(t *testing.T) {
+	err := goerr.New(errors.New("pq: password auth failed for user admin"), "connect failed")
+	err = goerr.WithField(goerr.New(err, "query failed"), "query", "select")
+	err = goerr.Mask(goerr.New(err, "repository failed"), http.StatusServiceUnavailable, "try again later")
+	err = goerr.WithRetryAfter(goerr.New(err, "service failed"), time.Minute)
+	err = goerr.SetCode(goerr.WithDetails(goerr.New(err, "handler failed"), "details"), "billing", 7)
+	err = goerr.New(err, "middleware")
+
+	compacted := goerr.Compact(err, 1)
+
+	want := []string{"middleware", "2 intermediate layers", "try again later", "2 intermediate layers", "connect failed", "pq: password auth failed for user admin"}
+	if got := goerr.ListErrors(compacted); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Want: %v, Got: %v", want, got)
+	}
+	if got := goerr.PublicMessage(compacted); got != "middleware" {
+		t.Errorf("Want: middleware, Got: %s", got)
+	}
+	b, _ := json.Marshal(compacted)
+	if strings.Contains(string(b), "password") || strings.Contains(string(b), "select") {
+		t.Errorf("expected the masked layers to stay masked, Got: %s", b)
+	}
+	if d, ok := goerr.RetryAfter(compacted); !ok || d != time.Minute {
+		t.Errorf("Want: 1m0s, Got: %v", d)
+	}
+	if got := goerr.Details(compacted); got != "details" {
+		t.Errorf("Want: details, Got: %v", got)
+	}
+	if got := goerr.CodeIn(compacted, "billing"); got != 7 {
+		t.Errorf("Want: 7, Got: %d", got)
+	}
+	if got := goerr.Fields(compacted)["query"]; got != "select" {
+		t.Errorf("Want: select, Got: %v", got)
+	}
+}
@@ -0,0 +1,110 @@
+package goerr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The functions in this file mirror github.com/pkg/errors, so that code using
+// it can be migrated by rewriting the package name, e.g. with
+//
+//	gofmt -w -r 'errors.Wrapf(a, b) -> goerr.Wrapf(a, b)' .
+//
+// Note that errors.New of pkg/errors takes only a message, calls of it have
+// to be rewritten to goerr.New(nil, message).
+
+// Wrap returns err wrapped in a new layer with the message "message: " followed
+// by the message of err. It returns nil if err is nil.
+func Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+
+	e := newError(err, []any{message + ": " + err.Error()}, 1)
+	notify(e)
+	return e
+}
+
+// Wrapf returns err wrapped in a new layer with the formatted message
+// followed by ": " and the message of err. It returns nil if err is nil.
+func Wrapf(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+
+	e := newError(err, []any{fmt.Sprintf(format, args...) + ": " + err.Error()}, 1)
+	notify(e)
+	return e
+}
+
+// WithStack returns err wrapped in a new layer with the message of err,
+// recording the call stack. It returns nil if err is nil.
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	e := newError(err, nil, 1)
+	notify(e)
+	return e
+}
+
+// WithMessage returns err wrapped in a new layer with the message "message: "
+// followed by the message of err. Unlike with pkg/errors, the layer records
+// the call stack like every goerr layer. It returns nil if err is nil.
+func WithMessage(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+
+	e := newError(err, []any{message + ": " + err.Error()}, 1)
+	notify(e)
+	return e
+}
+
+// Errorf returns a new layer with the message formatted like fmt.Errorf,
+// recording the call stack, so that code using fmt.Errorf can be migrated by
+// changing the package name. Like with fmt.Errorf, the errors of %w verbs
+// are wrapped: errors.Is and errors.As match them, and Stack renders them
+// below the layer.
+//
+//	return goerr.Errorf("read config %s: %w", path, err)
+func Errorf(format string, args ...any) error {
+	e := newErrorf(format, args, 1)
+	notify(e)
+	return e
+}
+
+// newErrorf creates the layer of Errorf. skip is the same as for newError.
+// Hooks are not notified.
+func newErrorf(format string, args []any, skip int) *Error {
+	w := fmt.Errorf(format, args...)
+
+	var nested error
+	switch u := w.(type) {
+	case interface{ Unwrap() error }:
+		nested = u.Unwrap()
+	case interface{ Unwrap() []error }:
+		nested = causes(u.Unwrap())
+	}
+
+	return newError(nested, []any{w.Error()}, skip+1)
+}
+
+// Cause returns the innermost error of the chain of err, following both
+// errors.Unwrap and the Cause method of pkg/errors. Unlike Root, it returns
+// the innermost goerr layer if the chain has no other errors. It returns nil
+// if err is nil.
+func Cause(err error) error {
+	for depth := 0; err != nil && depth < MaxChainDepth; depth++ {
+		next := errors.Unwrap(err)
+		if c, ok := err.(interface{ Cause() error }); ok && next == nil {
+			next = c.Cause()
+		}
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+	return err
+}
@@ -0,0 +1,105 @@
+package goerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+// causer mimics the errors of github.com/pkg/errors.
+type causer struct {
+	msg   string
+	cause error
+}
+
+func (c *causer) Error() string { return c.msg + ": " + c.cause.Error() }
+func (c *causer) Cause() error  { return c.cause }
+
+func TestPkgErrorsCompat(t *testing.T) {
+	root := errors.New("connection refused")
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"Wrap", goerr.Wrap(root, "query failed"), "query failed: connection refused"},
+		{"Wrapf", goerr.Wrapf(root, "query %d failed", 7), "query 7 failed: connection refused"},
+		{"WithStack", goerr.WithStack(root), "connection refused"},
+		{"WithMessage", goerr.WithMessage(root, "query failed"), "query failed: connection refused"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("%s. Want: %s, Got: %s", tt.name, tt.want, got)
+		}
+		if !errors.Is(tt.err, root) {
+			t.Errorf("%s: expected err to wrap root", tt.name)
+		}
+		if got := goerr.Cause(tt.err); got != root {
+			t.Errorf("%s: Cause. Want: %v, Got: %v", tt.name, root, got)
+		}
+		if stack := goerr.Stack(tt.err); !strings.Contains(stack, "compat_test.go") {
+			t.Errorf("%s: expected the stack to point to the caller, Got: %s", tt.name, stack)
+		}
+	}
+}
+
+func TestPkgErrorsCompatNil(t *testing.T) {
+	if goerr.Wrap(nil, "x") != nil || goerr.Wrapf(nil, "x") != nil || goerr.WithStack(nil) != nil || goerr.WithMessage(nil, "x") != nil {
+		t.Errorf("expected nil for a nil error")
+	}
+	if goerr.Cause(nil) != nil {
+		t.Errorf("expected nil cause for a nil error")
+	}
+}
+
+func TestCause(t *testing.T) {
+	root := errors.New("connection refused")
+	err := goerr.New(&causer{"query failed", root}, "service failed")
+
+	if got := goerr.Cause(err); got != root {
+		t.Errorf("Want: %v, Got: %v", root, got)
+	}
+
+	inner := goerr.New(nil, "invalid id")
+	if got := goerr.Cause(goerr.Wrap(inner, "lookup failed")); got != inner {
+		t.Errorf("Want: %v, Got: %v", inner, got)
+	}
+}
+
+func TestErrorf(t *testing.T) {
+	cause := errors.New("file does not exist")
+	err := goerr.Errorf("read config %s: %w", "app.yaml", cause)
+
+	if want, got := "read config app.yaml: file does not exist", err.Error(); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("expected the error to wrap the %%w argument")
+	}
+	if errors.Unwrap(err) != cause {
+		t.Errorf("expected the cause to be the next layer")
+	}
+	if stack := goerr.Stack(err); !strings.Contains(stack, "compat_test.go") {
+		t.Errorf("stack does not contain the call site: %s", stack)
+	}
+
+	if errors.Unwrap(goerr.Errorf("plain %d", 1)) != nil {
+		t.Errorf("expected no cause without %%w")
+	}
+}
+
+func TestErrorfMultipleWraps(t *testing.T) {
+	a, b := errors.New("replica a down"), errors.New("replica b down")
+	err := goerr.Errorf("all replicas failed: %w, %w", a, b)
+
+	if !errors.Is(err, a) || !errors.Is(err, b) {
+		t.Errorf("expected the error to wrap both %%w arguments")
+	}
+	if want, got := 2, len(goerr.Causes(err)); got != want {
+		t.Errorf("Want: %d, Got: %d", want, got)
+	}
+}
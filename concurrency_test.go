@@ -0,0 +1,71 @@
+package goerr_test
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+// TestConcurrentEnrichment enriches a shared error from many goroutines.
+// Run with -race to verify that no enrichment mutates the shared error.
+func TestConcurrentEnrichment(t *testing.T) {
+	shared := goerr.WithField(samplesrc.Service(), "shard", 0)
+	want := goerr.Stack(shared)
+
+	enrich := []func(err error, i int) error{
+		func(err error, i int) error { return goerr.WithField(err, "shard", i) },
+		func(err error, i int) error { return goerr.WithFields(err, map[string]any{"attempt": i}) },
+		func(err error, i int) error { return goerr.WithKind(err, goerr.KindUnavailable) },
+		func(err error, i int) error { return goerr.WithSeverity(err, goerr.SeverityCritical) },
+		func(err error, i int) error { return goerr.WithWeight(err, float64(i)) },
+		func(err error, i int) error { return goerr.WithDetails(err, i) },
+		func(err error, i int) error { return goerr.WithMessageKey(err, "shard.failed", i) },
+		func(err error, i int) error { return goerr.Hide(err) },
+		func(err error, i int) error { return goerr.Mask(err, "internal error") },
+		func(err error, i int) error { return goerr.New(err, "shard "+strconv.Itoa(i)+" failed") },
+	}
+
+	var wg sync.WaitGroup
+	results := make([]error, 100)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := enrich[i%len(enrich)](shared, i)
+			goerr.Stack(err)
+			goerr.Fields(err)
+			results[i] = err
+		}(i)
+	}
+	wg.Wait()
+
+	if got := goerr.Stack(shared); got != want {
+		t.Errorf("shared error changed.\nWant: %s\nGot: %s", want, got)
+	}
+	if got := goerr.Fields(shared)["shard"]; got != 0 {
+		t.Errorf("Want: 0, Got: %v", got)
+	}
+	for i := 0; i < len(results); i += len(enrich) {
+		if got := goerr.Fields(results[i])["shard"]; got != i {
+			t.Errorf("Want: %d, Got: %v", i, got)
+		}
+	}
+}
+
+func TestMessageKeyArgsCopied(t *testing.T) {
+	args := []any{"first"}
+	err := goerr.WithMessageKey(goerr.New(nil, "failed"), "key", args...)
+	args[0] = "changed"
+
+	goerr.SetTranslator(goerr.TranslatorFunc(func(locale, key string, args ...any) (string, bool) {
+		return args[0].(string), true
+	}))
+	defer goerr.SetTranslator(nil)
+
+	if got := goerr.Localize(err, "en"); got != "first" {
+		t.Errorf("Want: first, Got: %s", got)
+	}
+}
@@ -0,0 +1,73 @@
+package goerr
+
+// The constructors in this file are like New with the code of a predefined
+// Kind, so that domain packages do not need to import net/http for the
+// codes.
+
+// BadRequest returns nested wrapped in a new layer with the code of
+// KindBadRequest and the formatted message.
+func BadRequest(nested error, format string, args ...any) error {
+	return newWithKind(nested, KindBadRequest, format, args)
+}
+
+// Unauthorized returns nested wrapped in a new layer with the code of
+// KindUnauthorized and the formatted message.
+func Unauthorized(nested error, format string, args ...any) error {
+	return newWithKind(nested, KindUnauthorized, format, args)
+}
+
+// Forbidden returns nested wrapped in a new layer with the code of
+// KindForbidden and the formatted message.
+func Forbidden(nested error, format string, args ...any) error {
+	return newWithKind(nested, KindForbidden, format, args)
+}
+
+// NotFound returns nested wrapped in a new layer with the code of
+// KindNotFound and the formatted message.
+func NotFound(nested error, format string, args ...any) error {
+	return newWithKind(nested, KindNotFound, format, args)
+}
+
+// Conflict returns nested wrapped in a new layer with the code of
+// KindConflict and the formatted message.
+func Conflict(nested error, format string, args ...any) error {
+	return newWithKind(nested, KindConflict, format, args)
+}
+
+// Unprocessable returns nested wrapped in a new layer with the code of
+// KindUnprocessable and the formatted message.
+func Unprocessable(nested error, format string, args ...any) error {
+	return newWithKind(nested, KindUnprocessable, format, args)
+}
+
+// TooManyRequests returns nested wrapped in a new layer with the code of
+// KindTooManyRequests and the formatted message.
+func TooManyRequests(nested error, format string, args ...any) error {
+	return newWithKind(nested, KindTooManyRequests, format, args)
+}
+
+// Internal returns nested wrapped in a new layer with the code of
+// KindInternal and the formatted message.
+func Internal(nested error, format string, args ...any) error {
+	return newWithKind(nested, KindInternal, format, args)
+}
+
+// Unavailable returns nested wrapped in a new layer with the code of
+// KindUnavailable and the formatted message.
+func Unavailable(nested error, format string, args ...any) error {
+	return newWithKind(nested, KindUnavailable, format, args)
+}
+
+// Timeout returns nested wrapped in a new layer with the code of KindTimeout
+// and the formatted message.
+func Timeout(nested error, format string, args ...any) error {
+	return newWithKind(nested, KindTimeout, format, args)
+}
+
+// newWithKind creates the layer of the constructors above, attributed to
+// their caller.
+func newWithKind(nested error, kind Kind, format string, args []any) error {
+	e := newError(nested, append([]any{kind.Code(), format}, args...), 2)
+	notify(e)
+	return e
+}
@@ -0,0 +1,54 @@
+package goerr_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestConstructors(t *testing.T) {
+	nested := errors.New("no rows")
+
+	tests := []struct {
+		err  error
+		code int
+		kind goerr.Kind
+	}{
+		{goerr.BadRequest(nested, "order %d failed", 42), http.StatusBadRequest, goerr.KindBadRequest},
+		{goerr.Unauthorized(nested, "order %d failed", 42), http.StatusUnauthorized, goerr.KindUnauthorized},
+		{goerr.Forbidden(nested, "order %d failed", 42), http.StatusForbidden, goerr.KindForbidden},
+		{goerr.NotFound(nested, "order %d failed", 42), http.StatusNotFound, goerr.KindNotFound},
+		{goerr.Conflict(nested, "order %d failed", 42), http.StatusConflict, goerr.KindConflict},
+		{goerr.Unprocessable(nested, "order %d failed", 42), http.StatusUnprocessableEntity, goerr.KindUnprocessable},
+		{goerr.TooManyRequests(nested, "order %d failed", 42), http.StatusTooManyRequests, goerr.KindTooManyRequests},
+		{goerr.Internal(nested, "order %d failed", 42), http.StatusInternalServerError, goerr.KindInternal},
+		{goerr.Unavailable(nested, "order %d failed", 42), http.StatusServiceUnavailable, goerr.KindUnavailable},
+		{goerr.Timeout(nested, "order %d failed", 42), http.StatusGatewayTimeout, goerr.KindTimeout},
+	}
+
+	for _, tt := range tests {
+		if got := goerr.Code(tt.err); got != tt.code {
+			t.Errorf("%s. Want: %d, Got: %d", tt.kind, tt.code, got)
+		}
+		if !errors.Is(tt.err, tt.kind) || !errors.Is(tt.err, nested) {
+			t.Errorf("%s: expected err to be %s and wrap nested", tt.kind, tt.kind)
+		}
+		if got := tt.err.Error(); got != "order 42 failed" {
+			t.Errorf("%s. Want: order 42 failed, Got: %s", tt.kind, got)
+		}
+		if stack := goerr.ListStacks(tt.err)[0]; !strings.Contains(stack, "constructors_test.go") {
+			t.Errorf("%s: expected the layer to point to the caller, Got: %s", tt.kind, stack)
+		}
+	}
+}
+
+func TestConstructorsNoArgs(t *testing.T) {
+	err := goerr.NotFound(nil, "order not found")
+
+	if got := err.Error(); got != "order not found" {
+		t.Errorf("Want: order not found, Got: %s", got)
+	}
+}
@@ -0,0 +1,50 @@
+package goerr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// StatusClientClosedRequest is the non standard status code used when the
+// client went away before the request was completed.
+const StatusClientClosedRequest = 499
+
+// PastDeadlineField is the field in which FromContextErr records how long
+// past the deadline of the context the error was created.
+const PastDeadlineField = "past_deadline"
+
+// FromContextErr returns a goerr wrapping ctx.Err() with the given message, or
+// nil if ctx is not done. The code is StatusClientClosedRequest if ctx was
+// canceled and 504 if its deadline was exceeded, in which case the time
+// elapsed since the deadline, by the clock set with SetClock, is recorded in
+// the PastDeadlineField field.
+func FromContextErr(ctx context.Context, msg string) error {
+	e := newContextErr(ctx, msg, 1)
+	if e == nil {
+		return nil
+	}
+	notify(e)
+	return e
+}
+
+// newContextErr creates the layer of FromContextErr, or returns nil if ctx
+// is not done. skip is the same as for newError. Hooks are not notified.
+func newContextErr(ctx context.Context, msg string, skip int) *Error {
+	cause := ctx.Err()
+	if cause == nil {
+		return nil
+	}
+
+	e := newError(cause, []any{msg}, skip+1)
+	switch {
+	case errors.Is(cause, context.Canceled):
+		e.code = StatusClientClosedRequest
+	case errors.Is(cause, context.DeadlineExceeded):
+		e.code = http.StatusGatewayTimeout
+		if deadline, ok := ctx.Deadline(); ok {
+			e.withFields(map[string]any{PastDeadlineField: now().Sub(deadline)})
+		}
+	}
+	return e
+}
@@ -0,0 +1,63 @@
+package goerr_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestFromContextErrCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if err := goerr.FromContextErr(ctx, "request aborted"); err != nil {
+		t.Errorf("expected no error for a context that is not done, Got: %v", err)
+	}
+
+	cancel()
+	err := goerr.FromContextErr(ctx, "request aborted")
+
+	if got := goerr.Code(err); got != goerr.StatusClientClosedRequest {
+		t.Errorf("Want: %d, Got: %d", goerr.StatusClientClosedRequest, got)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected err to wrap context.Canceled")
+	}
+	if err.Error() != "request aborted" {
+		t.Errorf("Want: %s, Got: %s", "request aborted", err.Error())
+	}
+}
+
+func TestFromContextErrDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	time.Sleep(5 * time.Millisecond)
+
+	err := goerr.FromContextErr(ctx, "query timed out")
+
+	if got := goerr.Code(err); got != http.StatusGatewayTimeout {
+		t.Errorf("Want: %d, Got: %d", http.StatusGatewayTimeout, got)
+	}
+	past, ok := goerr.Fields(err)[goerr.PastDeadlineField].(time.Duration)
+	if !ok || past < 5*time.Millisecond {
+		t.Errorf("unexpected time past deadline: %v", past)
+	}
+}
+
+func TestFromContextErrClock(t *testing.T) {
+	deadline := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	goerr.SetClock(goerr.ClockFunc(func() time.Time { return deadline.Add(1500 * time.Millisecond) }))
+	defer goerr.SetClock(nil)
+
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	err := goerr.FromContextErr(ctx, "query timed out")
+	if want, got := 1500*time.Millisecond, goerr.Fields(err)[goerr.PastDeadlineField]; got != want {
+		t.Errorf("Want: %v, Got: %v", want, got)
+	}
+}
@@ -0,0 +1,63 @@
+package goerr
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// deadlineLayout is the format of deadlines in stack lines.
+const deadlineLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// Deadline returns the deadline of the context given to NewCtx for the
+// outermost layer of err created with a context that had one, and whether
+// there is one.
+//
+//	if deadline, ok := goerr.Deadline(err); ok && errors.Is(err, context.DeadlineExceeded) {
+//		log.Printf("timed out %s after the deadline", goerr.FirstOccurred(err).Sub(deadline))
+//	}
+func Deadline(err error) (time.Time, bool) {
+	e := find(err, func(e *Error) bool { return !e.deadline.IsZero() })
+	if e == nil {
+		return time.Time{}, false
+	}
+	return e.deadline, true
+}
+
+// deadlineExceeded reports whether e has a deadline and the chain below it
+// has context.DeadlineExceeded, in which case Stack renders the deadline.
+func (e *Error) deadlineExceeded() bool {
+	return !e.deadline.IsZero() && e.err != nil && is(e.err, context.DeadlineExceeded)
+}
+
+// writeDeadline writes the deadline of e, and how long after it e was
+// created, like " <deadline 2026-10-16T09:30:00.250Z exceeded by 12ms>".
+func (e *Error) writeDeadline(b *strings.Builder) {
+	var buf [len(deadlineLayout) + 8]byte
+
+	b.WriteString(" <deadline ")
+	b.Write(e.deadline.UTC().AppendFormat(buf[:0], deadlineLayout))
+	if late := e.created.Sub(e.deadline); !e.created.IsZero() && late >= 0 {
+		b.WriteString(" exceeded by ")
+		b.WriteString(late.Round(time.Millisecond).String())
+	}
+	b.WriteByte('>')
+}
+
+// parseDeadline returns the deadline line ends in, like " <deadline
+// 2026-10-16T09:30:00.250Z exceeded by 12ms>", and the index it starts at.
+func parseDeadline(line string) (time.Time, int) {
+	if !strings.HasSuffix(line, ">") {
+		return time.Time{}, -1
+	}
+	i := strings.LastIndex(line, " <deadline ")
+	if i < 0 {
+		return time.Time{}, -1
+	}
+	s, _, _ := strings.Cut(line[i+len(" <deadline "):len(line)-1], " ")
+	t, err := time.Parse(deadlineLayout, s)
+	if err != nil {
+		return time.Time{}, -1
+	}
+	return t, i
+}
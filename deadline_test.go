@@ -0,0 +1,66 @@
+package goerr_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestNewCtxDeadline(t *testing.T) {
+	deadline := time.Date(2026, 10, 16, 9, 30, 0, 250e6, time.UTC)
+	goerr.SetClock(goerr.ClockFunc(func() time.Time { return deadline.Add(12 * time.Millisecond) }))
+	defer goerr.SetClock(nil)
+
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	<-ctx.Done()
+
+	err := goerr.NewCtx(ctx, goerr.New(ctx.Err(), "query failed"), "load order failed")
+
+	want := " <deadline 2026-10-16T09:30:00.250Z exceeded by 12ms> ["
+	if line := strings.Split(goerr.Stack(err), "\n")[1]; !strings.Contains(line, want) {
+		t.Errorf("Want: %q in the line, Got: %s", want, line)
+	}
+	if got, ok := goerr.Deadline(err); !ok || !got.Equal(deadline) {
+		t.Errorf("Want: %s, Got: %s %v", deadline, got, ok)
+	}
+
+	frames, perr := goerr.ParseStack(goerr.Stack(err))
+	if perr != nil {
+		t.Fatal(perr)
+	}
+	if frames[0].Message != "load order failed" || !frames[0].Deadline.Equal(deadline) || frames[0].File == "" {
+		t.Errorf("Want: the message, deadline and location, Got: %+v", frames[0])
+	}
+
+	var layer goerr.Layer
+	goerr.Walk(err, func(l goerr.Layer) bool {
+		layer = l
+		return false
+	})
+	if !layer.Frame.Deadline.Equal(deadline) {
+		t.Errorf("Want: %s, Got: %s", deadline, layer.Frame.Deadline)
+	}
+}
+
+func TestNewCtxDeadlineNotExceeded(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	err := goerr.NewCtx(ctx, errors.New("connection refused"), "load order failed")
+
+	if stack := goerr.Stack(err); strings.Contains(stack, "<deadline") {
+		t.Errorf("expected no deadline without a deadline exceeded, Got: %s", stack)
+	}
+	if _, ok := goerr.Deadline(err); !ok {
+		t.Errorf("expected the deadline to be recorded")
+	}
+
+	if _, ok := goerr.Deadline(goerr.NewCtx(context.Background(), nil, "failed")); ok {
+		t.Errorf("expected no deadline for a context without one")
+	}
+}
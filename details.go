@@ -0,0 +1,31 @@
+package goerr
+
+// WithDetails returns err with details set on its outermost layer. Details
+// are a structured payload for the client, like the list of fields that
+// failed validation, which the JSON marshaler renders under the "details"
+// key. If err is not a goerr, it is wrapped in a new goerr first.
+//
+//	type Violation struct {
+//		Field  string `json:"field"`
+//		Reason string `json:"reason"`
+//	}
+//
+//	err = goerr.WithDetails(err, []Violation{{"email", "invalid format"}})
+func WithDetails(err error, details any) error {
+	if err == nil {
+		return nil
+	}
+
+	e := layer(err)
+	e.details = details
+	return e
+}
+
+// Details returns the details last given in the call chain of err, or nil.
+func Details(err error) any {
+	e := find(err, func(e *Error) bool { return e.details != nil })
+	if e == nil {
+		return nil
+	}
+	return e.details
+}
@@ -0,0 +1,49 @@
+package goerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+type violation struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+func TestWithDetails(t *testing.T) {
+	if goerr.WithDetails(nil, "x") != nil {
+		t.Errorf("expected nil for a nil error")
+	}
+
+	violations := []violation{{"email", "invalid format"}}
+	inner := goerr.WithDetails(errors.New("validation failed"), violations)
+	err := goerr.New(inner, http.StatusBadRequest, "invalid request")
+
+	got, ok := goerr.Details(err).([]violation)
+	if !ok || len(got) != 1 || got[0] != violations[0] {
+		t.Errorf("Want: %v, Got: %v", violations, goerr.Details(err))
+	}
+	if goerr.Details(goerr.New(nil, "failed")) != nil {
+		t.Errorf("expected no details")
+	}
+
+	b, _ := json.Marshal(err)
+	want := `"details":[{"field":"email","reason":"invalid format"}]`
+	if !strings.Contains(string(b), want) {
+		t.Errorf("Want: %s, Got: %s", want, b)
+	}
+}
+
+func TestWithDetailsMasked(t *testing.T) {
+	err := goerr.Mask(goerr.WithDetails(goerr.New(nil, "query failed"), map[string]string{"query": "select"}), "internal error")
+
+	b, _ := json.Marshal(err)
+	if strings.Contains(string(b), `"details":`) {
+		t.Errorf("expected details of masked layers to be hidden, Got: %s", b)
+	}
+}
@@ -0,0 +1,37 @@
+package goerr
+
+import "strings"
+
+// Diff compares the chains of a and b layer by layer, outermost first, and
+// returns an empty string if they are equal. Otherwise it renders the shared
+// layers prefixed with two spaces, followed by the remaining layers of a
+// prefixed with "- " and those of b prefixed with "+ ", indented by depth
+// like Stack.
+func Diff(a, b error) string {
+	as, bs := ListStacks(a), ListStacks(b)
+
+	shared := 0
+	for shared < len(as) && shared < len(bs) && as[shared] == bs[shared] {
+		shared++
+	}
+	if shared == len(as) && shared == len(bs) {
+		return ""
+	}
+
+	var sb strings.Builder
+	writeDiff(&sb, "  ", as[:shared], 0)
+	writeDiff(&sb, "- ", as[shared:], shared)
+	writeDiff(&sb, "+ ", bs[shared:], shared)
+	return sb.String()
+}
+
+func writeDiff(b *strings.Builder, prefix string, lines []string, depth int) {
+	for i, line := range lines {
+		b.WriteByte('\n')
+		b.WriteString(prefix)
+		for j := 0; j < depth+i; j++ {
+			b.WriteByte('\t')
+		}
+		b.WriteString(line)
+	}
+}
@@ -0,0 +1,45 @@
+package goerr_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestDiff(t *testing.T) {
+	repository := func(cause error) error {
+		return goerr.New(cause, 500, "repository error")
+	}
+	service := func(cause error) error {
+		return goerr.New(repository(cause), "service error")
+	}
+
+	a := service(errors.New("connection refused"))
+	b := service(errors.New("connection reset"))
+
+	got := goerr.Diff(a, b)
+	t.Log(got)
+
+	pattern := `^
+  service error \[.*diff_test.go:\d+ \(goerr_test.TestDiff.func2\)\]
+  	repository error \(500\) \[.*diff_test.go:\d+ \(goerr_test.TestDiff.func1\)\]
+- 		connection refused
+\+ 		connection reset$`
+	if match, _ := regexp.MatchString(pattern, got); !match {
+		t.Errorf("diff is not matching the expectation")
+	}
+}
+
+func TestDiffEqual(t *testing.T) {
+	cause := errors.New("connection refused")
+	newErr := func() error { return goerr.New(cause, "service error") }
+
+	if got := goerr.Diff(newErr(), newErr()); got != "" {
+		t.Errorf("Want no diff, Got: %s", got)
+	}
+	if got := goerr.Diff(nil, nil); got != "" {
+		t.Errorf("Want no diff, Got: %s", got)
+	}
+}
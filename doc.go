@@ -0,0 +1,8 @@
+// Package goerr provides an error type that keeps the call chain of nested
+// errors and renders it as a human readable stack, see New and Stack.
+//
+// Errors are immutable once created. Functions that enrich an error, like
+// WithField, WithKind or Hide, return a copy of its outermost layer with the
+// change and never modify the error they are given, so a shared error can be
+// enriched concurrently, e.g. in fan-out code, without synchronization.
+package goerr
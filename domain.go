@@ -0,0 +1,78 @@
+package goerr
+
+// A Domain names a space of codes, so that an error can carry codes of
+// several domains at once, e.g. its HTTP status and a business code of the
+// billing system, without collisions. See SetCode.
+type Domain string
+
+// DomainHTTP is the domain of the codes given to New and returned by Code.
+const DomainHTTP Domain = "http"
+
+// SetCode returns err with code set on its outermost layer in domain. For
+// DomainHTTP, it sets the code of the layer, as if given to New. Codes of
+// other domains are returned by CodeIn and Codes, and rendered under the
+// "codes" key by the JSON marshaler. If err is not a goerr, it is wrapped in
+// a new goerr first.
+//
+//	var Billing = goerr.Domain("billing")
+//	...
+//	return goerr.SetCode(goerr.New(err, http.StatusPaymentRequired, "card declined"), Billing, 1042)
+func SetCode(err error, domain Domain, code int) error {
+	if err == nil {
+		return nil
+	}
+
+	if domain == DomainHTTP && strictMode.Load() {
+		checkCode(code)
+	}
+
+	e := layer(err)
+	if domain == DomainHTTP {
+		e.code = code
+		return e
+	}
+	codes := make(map[Domain]int, len(e.codes)+1)
+	for d, c := range e.codes {
+		codes[d] = c
+	}
+	codes[domain] = code
+	e.codes = codes
+	return e
+}
+
+// CodeIn returns the code last given in the call chain of err in domain, or
+// 0. For DomainHTTP, it is Code.
+func CodeIn(err error, domain Domain) int {
+	if domain == DomainHTTP {
+		return Code(err)
+	}
+
+	e := find(err, func(e *Error) bool { return e.codes[domain] != 0 })
+	if e == nil {
+		return 0
+	}
+	return e.codes[domain]
+}
+
+// Codes returns the codes of err in every domain other than DomainHTTP, the
+// one last given in the call chain for each, or nil if there are none.
+func Codes(err error) map[Domain]int {
+	var codes map[Domain]int
+	walk(err, func(_ int, err error) bool {
+		e, ok := err.(*Error)
+		if !ok {
+			return true
+		}
+		for d, c := range e.codes {
+			if _, ok := codes[d]; ok || c == 0 {
+				continue
+			}
+			if codes == nil {
+				codes = map[Domain]int{}
+			}
+			codes[d] = c
+		}
+		return true
+	})
+	return codes
+}
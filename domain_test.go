@@ -0,0 +1,66 @@
+package goerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+const (
+	billing = goerr.Domain("billing")
+	ledger  = goerr.Domain("ledger")
+)
+
+func TestSetCode(t *testing.T) {
+	inner := goerr.SetCode(goerr.New(errors.New("declined"), http.StatusPaymentRequired, "card declined"), billing, 1042)
+	inner = goerr.SetCode(inner, ledger, 7)
+	err := goerr.SetCode(goerr.New(inner, "checkout failed"), billing, 2001)
+
+	if want, got := 2001, goerr.CodeIn(err, billing); got != want {
+		t.Errorf("Want: %d, Got: %d", want, got)
+	}
+	if want, got := 1042, goerr.CodeIn(inner, billing); got != want {
+		t.Errorf("expected the inner layer to be unchanged. Want: %d, Got: %d", want, got)
+	}
+	if want, got := 7, goerr.CodeIn(err, ledger); got != want {
+		t.Errorf("Want: %d, Got: %d", want, got)
+	}
+	if want, got := http.StatusPaymentRequired, goerr.CodeIn(err, goerr.DomainHTTP); got != want {
+		t.Errorf("Want: %d, Got: %d", want, got)
+	}
+	if got := goerr.CodeIn(err, goerr.Domain("shipping")); got != 0 {
+		t.Errorf("Want: 0, Got: %d", got)
+	}
+
+	want := map[goerr.Domain]int{billing: 2001, ledger: 7}
+	if got := goerr.Codes(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Want: %v, Got: %v", want, got)
+	}
+	if goerr.Codes(errors.New("plain")) != nil || goerr.SetCode(nil, billing, 1) != nil {
+		t.Errorf("expected no codes")
+	}
+
+	b, _ := json.Marshal(err)
+	if !strings.HasPrefix(string(b), `{"message":"checkout failed","code":402,"codes":{"billing":2001,"ledger":7},`) {
+		t.Errorf("unexpected json: %s", b)
+	}
+	if got := goerr.ToProblem(err).Extensions["codes"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Want: %v, Got: %v", want, got)
+	}
+}
+
+func TestSetCodeHTTP(t *testing.T) {
+	err := goerr.SetCode(errors.New("no rows"), goerr.DomainHTTP, http.StatusNotFound)
+
+	if want, got := http.StatusNotFound, goerr.Code(err); got != want {
+		t.Errorf("Want: %d, Got: %d", want, got)
+	}
+	if goerr.Codes(err) != nil {
+		t.Errorf("expected no other codes, Got: %v", goerr.Codes(err))
+	}
+}
@@ -0,0 +1,121 @@
+package goerr
+
+import "reflect"
+
+// Dump returns a snapshot of err for post-mortem records, like incident
+// tickets or dumps to a bucket, independent of any logging framework. Unlike
+// the JSON marshaler, it covers every layer, including the ones masked or
+// hidden from public renderers, along with the resolved code, kind,
+// severity, fingerprint, root cause, fields, details, warnings, times and
+// build metadata. The registered redactors apply. The values are strings,
+// numbers, times, maps and slices, so the snapshot can be marshaled as
+// JSON. It returns nil if err is nil.
+//
+//	b, _ := json.MarshalIndent(goerr.Dump(err), "", "  ")
+//	bucket.Put(ctx, "incidents/"+id+".json", b)
+func Dump(err error) map[string]any {
+	if err == nil {
+		return nil
+	}
+
+	d := map[string]any{
+		"message":        newFrame(0, err).Message,
+		"public_message": PublicMessage(err),
+		"code":           Code(err),
+		"severity":       Severity(err).String(),
+		"fingerprint":    Fingerprint(err),
+		"fields":         Fields(err),
+		"stack":          Stack(err),
+		"dumped_at":      now(),
+	}
+	if code := CodeString(err); code != "" {
+		d["code_string"] = code
+	}
+	if codes := Codes(err); codes != nil {
+		d["codes"] = codes
+	}
+	if kind := KindOf(err); kind != "" {
+		d["kind"] = string(kind)
+	}
+	if root := Root(err); root != nil {
+		d["root_cause"] = redactMessage(nil, root.Error())
+		d["root_type"] = reflect.TypeOf(root).String()
+	}
+	if t := FirstOccurred(err); !t.IsZero() {
+		d["first_occurred"] = t
+	}
+	if details := Details(err); details != nil {
+		d["details"] = details
+	}
+	if warnings := ListWarnings(err); len(warnings) > 0 {
+		d["warnings"] = warningMessages(nil, warnings)
+	}
+	if b := newJSONBuild(); b != nil {
+		d["build"] = map[string]any{"version": b.Version, "commit": b.Commit}
+	}
+	if retry, ok := RetryAfter(err); ok {
+		d["retry_after"] = retry.String()
+	}
+
+	layers := []map[string]any{}
+	walk(err, func(depth int, err error) bool {
+		layers = append(layers, dumpLayer(depth, err))
+		return true
+	})
+	d["layers"] = layers
+	return d
+}
+
+// dumpLayer returns the snapshot of the layer err at the given depth.
+func dumpLayer(depth int, err error) map[string]any {
+	f := newFrame(depth, err)
+	l := map[string]any{
+		"depth":   depth,
+		"message": f.Message,
+	}
+	if f.Code != 0 {
+		l["code"] = f.Code
+	}
+	if f.Severity != 0 {
+		l["severity"] = f.Severity.String()
+	}
+	if f.Kind != "" {
+		l["kind"] = string(f.Kind)
+	}
+	if len(f.Fields) > 0 {
+		l["fields"] = f.Fields
+	}
+	if f.File != "" {
+		l["file"] = f.File
+		l["line"] = f.Line
+		l["function"] = f.Function
+	}
+	if f.Goroutine != 0 {
+		l["goroutine"] = f.Goroutine
+	}
+
+	e, ok := err.(*Error)
+	if !ok {
+		l["type"] = reflect.TypeOf(err).String()
+		return l
+	}
+	if !e.created.IsZero() {
+		l["created"] = e.created
+	}
+	if e.codeStr != "" {
+		l["code_string"] = e.codeStr
+	}
+	if len(e.codes) > 0 {
+		l["codes"] = e.codes
+	}
+	if e.public != "" {
+		l["public_message"] = redactMessage(e.factory, e.public)
+	}
+	if e.masks {
+		l["masks"] = true
+	}
+	if e.hidden {
+		l["hidden"] = true
+	}
+	return l
+}
@@ -0,0 +1,65 @@
+package goerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestDump(t *testing.T) {
+	goerr.SetClock(goerr.ClockFunc(func() time.Time { return time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC) }))
+	defer goerr.SetClock(nil)
+
+	inner := goerr.New(errors.New("pq: deadlock detected"), 409, "update order failed", goerr.F("order_id", "o-1"))
+	err := goerr.Mask(inner, 500, "internal error")
+
+	d := goerr.Dump(err)
+	if want, got := "internal error", d["message"]; got != want {
+		t.Errorf("message. Want: %s, Got: %v", want, got)
+	}
+	if want, got := 500, d["code"]; got != want {
+		t.Errorf("code. Want: %d, Got: %v", want, got)
+	}
+	if want, got := goerr.Fingerprint(err), d["fingerprint"]; got != want {
+		t.Errorf("fingerprint. Want: %s, Got: %v", want, got)
+	}
+	if want, got := "pq: deadlock detected", d["root_cause"]; got != want {
+		t.Errorf("root cause. Want: %s, Got: %v", want, got)
+	}
+	if want, got := "*errors.errorString", d["root_type"]; got != want {
+		t.Errorf("root type. Want: %s, Got: %v", want, got)
+	}
+	if want, got := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), d["first_occurred"]; got != want {
+		t.Errorf("first occurred. Want: %v, Got: %v", want, got)
+	}
+
+	layers := d["layers"].([]map[string]any)
+	if len(layers) != 3 {
+		t.Fatalf("layers. Want: 3, Got: %d", len(layers))
+	}
+	if layers[0]["masks"] != true {
+		t.Errorf("expected the outer layer to be marked as masking, Got: %v", layers[0])
+	}
+	if want, got := "update order failed", layers[1]["message"]; got != want {
+		t.Errorf("expected the masked layer to be dumped. Want: %s, Got: %v", want, got)
+	}
+	if want, got := 409, layers[1]["code"]; got != want {
+		t.Errorf("layer code. Want: %d, Got: %v", want, got)
+	}
+	if layers[1]["line"] == nil {
+		t.Errorf("expected the layer location, Got: %v", layers[1])
+	}
+	if want, got := "*errors.errorString", layers[2]["type"]; got != want {
+		t.Errorf("layer type. Want: %s, Got: %v", want, got)
+	}
+
+	if _, err := json.Marshal(d); err != nil {
+		t.Errorf("expected the dump to marshal, Got: %v", err)
+	}
+	if goerr.Dump(nil) != nil {
+		t.Errorf("Want: nil")
+	}
+}
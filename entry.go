@@ -0,0 +1,26 @@
+package goerr
+
+// Entry is one layer of an error chain as returned by ListEntries. It is the
+// same as the Frame passed to a FrameFormatter.
+type Entry = Frame
+
+// ListEntries is like ListStacks, but returns the layers of err as
+// structured entries instead of rendered lines, for programmatic consumption
+// like sending every layer to a trace event. If the chain is truncated, the
+// last entry only has the marker as its message, like the last line of
+// ListStacks.
+func ListEntries(err error) []Entry {
+	var result []Entry
+	last := 0
+	r := walkTree(err, func(depth int, label string, err error) bool {
+		f := newFrame(depth, err)
+		f.Message = label + f.Message
+		result = append(result, f)
+		last = depth
+		return true
+	})
+	if r != walkDone {
+		result = append(result, Entry{Message: r.marker(), Depth: last + 1})
+	}
+	return result
+}
@@ -0,0 +1,49 @@
+package goerr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+func TestListEntries(t *testing.T) {
+	err := goerr.New(samplesrc.Service(), 409, "controller failed")
+
+	entries := goerr.ListEntries(err)
+	if len(entries) != len(goerr.ListStacks(err)) {
+		t.Fatalf("Nr. of entries. Want: %d, Got: %d", len(goerr.ListStacks(err)), len(entries))
+	}
+
+	wants := []struct {
+		message  string
+		code     int
+		file     string
+		line     int
+		function string
+	}{
+		{"controller failed", 409, "entry_test.go", 0, "goerr_test.TestListEntries"},
+		{"service failed", 0, "samplesrc/samples.go", 20, "samplesrc.Service"},
+		{"error from database", 0, "samplesrc/samples.go", 27, "samplesrc.Repository"},
+	}
+	for i, want := range wants {
+		got := entries[i]
+		if got.Message != want.message || got.Code != want.code || !strings.HasSuffix(got.File, want.file) || got.Function != want.function || got.Depth != i {
+			t.Errorf("entry %d. Want: %+v, Got: %+v", i, want, got)
+		}
+		if want.line != 0 && got.Line != want.line {
+			t.Errorf("entry %d. Want line: %d, Got: %d", i, want.line, got.Line)
+		}
+	}
+}
+
+func TestListEntriesTruncated(t *testing.T) {
+	defer func(depth int) { goerr.MaxChainDepth = depth }(goerr.MaxChainDepth)
+	goerr.MaxChainDepth = 2
+
+	entries := goerr.ListEntries(samplesrc.Controller())
+	if len(entries) != 3 || entries[2].Message != "... chain truncated after 2 layers" || entries[2].Depth != 2 {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+}
@@ -0,0 +1,57 @@
+package goerr
+
+import "encoding/json"
+
+// MaxEnvelopeFrames is the maximum number of layers rendered in an envelope,
+// to keep it small enough for message headers.
+var MaxEnvelopeFrames = 10
+
+// An Envelope is a compact description of an error which can be passed along
+// with a failed message, e.g. in a header of a dead letter queue, so that a
+// consumer retrying the message later knows why it failed.
+type Envelope struct {
+	Message     string   `json:"message"`
+	Code        int      `json:"code,omitempty"`
+	Fingerprint string   `json:"fingerprint"`
+	Frames      []string `json:"frames,omitempty"`
+	// Truncated is set if the chain had more than MaxEnvelopeFrames layers.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Error returns the message of the enveloped error, so an envelope can be
+// handled as an error.
+func (env *Envelope) Error() string {
+	return env.Message
+}
+
+// ToEnvelope returns the JSON envelope of err, with the message, code and
+// fingerprint of err and the stack lines of its outermost layers as rendered
+// by ListStacks. It returns nil for a nil error.
+func ToEnvelope(err error) []byte {
+	if err == nil {
+		return nil
+	}
+
+	env := Envelope{
+		Message:     newFrame(0, err).Message,
+		Code:        Code(err),
+		Fingerprint: Fingerprint(err),
+		Frames:      ListStacks(err),
+	}
+	if len(env.Frames) > MaxEnvelopeFrames {
+		env.Frames = env.Frames[:MaxEnvelopeFrames]
+		env.Truncated = true
+	}
+
+	b, _ := json.Marshal(env)
+	return b
+}
+
+// FromEnvelope decodes an envelope created by ToEnvelope.
+func FromEnvelope(b []byte) (*Envelope, error) {
+	env := &Envelope{}
+	if err := json.Unmarshal(b, env); err != nil {
+		return nil, New(err, "invalid error envelope")
+	}
+	return env, nil
+}
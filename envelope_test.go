@@ -0,0 +1,54 @@
+package goerr_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+func TestEnvelope(t *testing.T) {
+	err := goerr.New(samplesrc.Service(), http.StatusConflict, "consume failed")
+
+	env, e := goerr.FromEnvelope(goerr.ToEnvelope(err))
+	if e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+
+	if env.Message != "consume failed" || env.Error() != "consume failed" {
+		t.Errorf("Want: consume failed, Got: %s", env.Message)
+	}
+	if env.Code != http.StatusConflict {
+		t.Errorf("Want: %d, Got: %d", http.StatusConflict, env.Code)
+	}
+	if env.Fingerprint != goerr.Fingerprint(err) {
+		t.Errorf("Want: %s, Got: %s", goerr.Fingerprint(err), env.Fingerprint)
+	}
+	if len(env.Frames) != 3 || !strings.Contains(env.Frames[2], "error from database") {
+		t.Errorf("unexpected frames: %q", env.Frames)
+	}
+	if env.Truncated {
+		t.Errorf("unexpected truncation")
+	}
+}
+
+func TestEnvelopeTruncated(t *testing.T) {
+	defer func(n int) { goerr.MaxEnvelopeFrames = n }(goerr.MaxEnvelopeFrames)
+	goerr.MaxEnvelopeFrames = 1
+
+	env, _ := goerr.FromEnvelope(goerr.ToEnvelope(samplesrc.Service()))
+	if len(env.Frames) != 1 || !env.Truncated {
+		t.Errorf("Want: 1 truncated frame, Got: %d %v", len(env.Frames), env.Truncated)
+	}
+}
+
+func TestEnvelopeInvalid(t *testing.T) {
+	if goerr.ToEnvelope(nil) != nil {
+		t.Errorf("expected no envelope for nil")
+	}
+	if _, err := goerr.FromEnvelope([]byte("not json")); err == nil {
+		t.Errorf("expected an error")
+	}
+}
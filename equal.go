@@ -0,0 +1,98 @@
+package goerr
+
+import "reflect"
+
+// A CompareOption configures EqualChains.
+type CompareOption func(*compareOptions)
+
+type compareOptions struct {
+	messages  bool
+	fields    bool
+	functions bool
+}
+
+// IgnoreMessages makes EqualChains compare the layers without their
+// messages, e.g. when messages contain generated IDs.
+func IgnoreMessages() CompareOption {
+	return func(o *compareOptions) {
+		o.messages = false
+	}
+}
+
+// CompareFields makes EqualChains compare the fields of the layers as well.
+func CompareFields() CompareOption {
+	return func(o *compareOptions) {
+		o.fields = true
+	}
+}
+
+// CompareFunctions makes EqualChains compare the functions in which the
+// layers were created as well.
+func CompareFunctions() CompareOption {
+	return func(o *compareOptions) {
+		o.functions = true
+	}
+}
+
+// EqualChains reports whether the chains of a and b have the same layers,
+// comparing the message and code of every layer, but not the files and line
+// numbers the layers were created at, so that tests do not break whenever
+// code shifts lines. Layers that are not goerr layers are compared by their
+// message.
+//
+//	want := goerr.New(goerr.New(nil, "no rows"), http.StatusNotFound, "order not found")
+//	if !goerr.EqualChains(err, want) {
+//		t.Errorf("unexpected error:\n%s", goerr.Diff(want, err))
+//	}
+func EqualChains(a, b error, opts ...CompareOption) bool {
+	o := compareOptions{messages: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var as, bs []error
+	walk(a, func(_ int, err error) bool {
+		as = append(as, err)
+		return true
+	})
+	walk(b, func(_ int, err error) bool {
+		bs = append(bs, err)
+		return true
+	})
+	if len(as) != len(bs) {
+		return false
+	}
+
+	for i := range as {
+		if !o.equal(as[i], bs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// equal reports whether a and b, which are single layers, are equal.
+func (o *compareOptions) equal(a, b error) bool {
+	ea, okA := a.(*Error)
+	eb, okB := b.(*Error)
+	if okA != okB {
+		return false
+	}
+	if !okA {
+		return !o.messages || a.Error() == b.Error()
+	}
+
+	if o.messages && ea.message != eb.message {
+		return false
+	}
+	if ea.code != eb.code {
+		return false
+	}
+	if o.fields && (len(ea.fields) != 0 || len(eb.fields) != 0) && !reflect.DeepEqual(ea.fields, eb.fields) {
+		return false
+	}
+	if o.functions && ea.Func() != eb.Func() {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package goerr_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+func TestEqualChains(t *testing.T) {
+	want := goerr.New(goerr.New(goerr.New(nil, "error from database"), "service failed"), "controller failed")
+
+	if !goerr.EqualChains(samplesrc.Controller(), want) {
+		t.Errorf("expected chains created at different lines to be equal")
+	}
+	if goerr.EqualChains(samplesrc.Service(), want) {
+		t.Errorf("expected chains of different lengths to differ")
+	}
+	if goerr.EqualChains(goerr.New(nil, http.StatusConflict, "failed"), goerr.New(nil, "failed")) {
+		t.Errorf("expected chains with different codes to differ")
+	}
+	if goerr.EqualChains(goerr.New(nil, "order 1 failed"), goerr.New(nil, "order 2 failed")) {
+		t.Errorf("expected chains with different messages to differ")
+	}
+	if !goerr.EqualChains(goerr.New(nil, "order 1 failed"), goerr.New(nil, "order 2 failed"), goerr.IgnoreMessages()) {
+		t.Errorf("expected messages to be ignored")
+	}
+	if !goerr.EqualChains(nil, nil) || goerr.EqualChains(want, nil) {
+		t.Errorf("unexpected result for nil errors")
+	}
+}
+
+func TestEqualChainsPlainErrors(t *testing.T) {
+	a := goerr.New(errors.New("no rows"), "lookup failed")
+	b := goerr.New(errors.New("no rows"), "lookup failed")
+
+	if !goerr.EqualChains(a, b) {
+		t.Errorf("expected equal plain errors to be equal")
+	}
+	if goerr.EqualChains(a, goerr.New(goerr.New(nil, "no rows"), "lookup failed")) {
+		t.Errorf("expected a plain error to differ from a goerr layer")
+	}
+}
+
+func TestEqualChainsOptions(t *testing.T) {
+	a := goerr.WithField(goerr.New(nil, "failed"), "id", 1)
+	b := goerr.WithField(goerr.New(nil, "failed"), "id", 2)
+
+	if !goerr.EqualChains(a, b) || goerr.EqualChains(a, b, goerr.CompareFields()) {
+		t.Errorf("expected fields to be compared only with CompareFields")
+	}
+	if goerr.EqualChains(samplesrc.Repository(), goerr.New(nil, "error from database"), goerr.CompareFunctions()) {
+		t.Errorf("expected functions to be compared with CompareFunctions")
+	}
+}
@@ -0,0 +1,55 @@
+package goerr
+
+import (
+	"fmt"
+	"os"
+)
+
+// WithExitCode returns err with the exit code of the process set on its
+// outermost layer, see ExitCode and Main. If err is not a goerr, it is
+// wrapped in a new goerr first.
+func WithExitCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+
+	e := layer(err)
+	e.exitCode = code
+	return e
+}
+
+// ExitCode returns the exit code last given in the call chain of err with
+// WithExitCode. Errors without an exit code exit with 1, and a nil error
+// with 0.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	e := find(err, func(e *Error) bool { return e.exitCode != 0 })
+	if e == nil {
+		return 1
+	}
+	return e.exitCode
+}
+
+// Main runs fn, the body of the main function of a command line tool. If fn
+// returns an error, Main prints its stack to stderr, rendered by Pretty if
+// stderr is a terminal, and exits with the ExitCode of the error.
+//
+//	func main() {
+//		goerr.Main(run)
+//	}
+func Main(fn func() error) {
+	err := fn()
+	if err == nil {
+		return
+	}
+
+	if fi, e := os.Stderr.Stat(); e == nil && fi.Mode()&os.ModeCharDevice != 0 {
+		fmt.Fprintln(os.Stderr, Pretty(err))
+	} else {
+		fmt.Fprintln(os.Stderr, Stack(err))
+	}
+	os.Exit(ExitCode(err))
+}
@@ -0,0 +1,57 @@
+package goerr_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, 0},
+		{"default", errors.New("plain"), 1},
+		{"set", goerr.WithExitCode(errors.New("plain"), 3), 3},
+		{"nested", goerr.New(goerr.WithExitCode(goerr.New(nil, "invalid flag"), 64), "run failed"), 64},
+	}
+
+	for _, tt := range tests {
+		if got := goerr.ExitCode(tt.err); got != tt.want {
+			t.Errorf("%s. Want: %d, Got: %d", tt.name, tt.want, got)
+		}
+	}
+	if goerr.WithExitCode(nil, 3) != nil {
+		t.Errorf("expected nil for a nil error")
+	}
+}
+
+func TestMainExit(t *testing.T) {
+	if os.Getenv("GOERR_TEST_MAIN") == "1" {
+		goerr.Main(func() error {
+			return goerr.WithExitCode(goerr.New(nil, "config missing"), 3)
+		})
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestMainExit$")
+	cmd.Env = append(os.Environ(), "GOERR_TEST_MAIN=1")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) || exit.ExitCode() != 3 {
+		t.Fatalf("Want: exit code 3, Got: %v", err)
+	}
+	if !strings.Contains(stderr.String(), "config missing [") {
+		t.Errorf("expected the stack on stderr, Got: %s", stderr.String())
+	}
+}
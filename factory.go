@@ -0,0 +1,285 @@
+package goerr
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Options are the settings of the errors created by the package level
+// functions, see Configure, or by a Factory.
+type Options struct {
+	// FrameFormat sets how function names are rendered, see SetFrameFormat.
+	FrameFormat FrameFormat
+	// PathMode sets how file paths are rendered, see SetPathMode.
+	PathMode PathMode
+	// Formatter renders the line of every layer, see SetFrameFormatter.
+	Formatter FrameFormatter
+	// Redactors mask sensitive data in rendered output, see AddRedactor.
+	Redactors []Redactor
+	// Hooks are called with every new layer, see OnNew. Configure drops
+	// the hooks registered with OnNew before it.
+	Hooks []func(e *Error)
+}
+
+// Configure replaces the global settings with opts, which is the same as
+// calling SetFrameFormat, SetPathMode and SetFrameFormatter, and replacing
+// the registered redactors and hooks. The zero Options restore the defaults.
+// Errors created by a Factory are not affected.
+func Configure(opts Options) {
+	SetFrameFormat(opts.FrameFormat)
+	SetPathMode(opts.PathMode)
+	SetFrameFormatter(opts.Formatter)
+
+	redactorsMu.Lock()
+	redactors = append([]Redactor(nil), opts.Redactors...)
+	redactorsMu.Unlock()
+
+	hooksMu.Lock()
+	hooks = append(([]func(e *Error))(nil), opts.Hooks...)
+	hooksMu.Unlock()
+}
+
+// A Factory creates errors with its own settings instead of the global ones,
+// so that a library can render its errors its own way and have its own hooks
+// without fighting over global state with the application or other
+// libraries. The settings travel with the layers created by the factory:
+// Stack and the other renderers apply them to these layers wherever the
+// error ends up, while the other layers of the chain use the global
+// settings.
+//
+// Its methods mirror the package level functions creating layers, and its
+// NewValidation returns a Validation whose Err has the settings of the
+// factory. Wrap2 and Wrap3 have no methods, as methods can not have type
+// parameters: use New with the same message instead. The package level
+// functions enriching a layer, like WithField or WithKind,
+// keep the settings of the layer they copy, so they can be used on the
+// errors of a factory as well.
+//
+//	var errs = goerr.NewFactory(goerr.Options{
+//		FrameFormat: goerr.FrameReceiver,
+//		Redactors:   []goerr.Redactor{goerr.KeyRedactor("token")},
+//	})
+//
+//	return errs.New(err, http.StatusBadGateway, "fetch rates failed")
+type Factory struct {
+	opts Options
+}
+
+// NewFactory returns a factory creating errors with the settings opts.
+func NewFactory(opts Options) *Factory {
+	opts.Redactors = append([]Redactor(nil), opts.Redactors...)
+	opts.Hooks = append(([]func(e *Error))(nil), opts.Hooks...)
+	return &Factory{opts: opts}
+}
+
+// New is like the package level New, with the settings of f.
+func (f *Factory) New(nested error, message ...any) error {
+	return f.finish(newError(nested, message, 1))
+}
+
+// NewSkip is like the package level NewSkip, with the settings of f.
+func (f *Factory) NewSkip(skip int, nested error, message ...any) error {
+	if skip < 0 {
+		skip = 0
+	}
+	return f.finish(newError(nested, message, skip+1))
+}
+
+// NewCtx is like the package level NewCtx, with the settings of f.
+func (f *Factory) NewCtx(ctx context.Context, nested error, message ...any) error {
+	return f.finish(newCtxError(ctx, nested, message, 1))
+}
+
+// NewT is like the package level NewT, with the settings of f, whose
+// redactors apply to the arguments put in the message.
+func (f *Factory) NewT(nested error, template string, args Args) error {
+	return f.finish(newTemplated(f, nested, template, args, 1))
+}
+
+// Errorf is like the package level Errorf, with the settings of f.
+func (f *Factory) Errorf(format string, args ...any) error {
+	return f.finish(newErrorf(format, args, 1))
+}
+
+// Mask is like the package level Mask, with the settings of f.
+func (f *Factory) Mask(err error, message ...any) error {
+	e := newError(err, message, 1)
+	e.masks = true
+	return f.finish(e)
+}
+
+// Wrap is like the package level Wrap, with the settings of f.
+func (f *Factory) Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return f.finish(newError(err, []any{message + ": " + err.Error()}, 1))
+}
+
+// Wrapf is like the package level Wrapf, with the settings of f.
+func (f *Factory) Wrapf(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return f.finish(newError(err, []any{fmt.Sprintf(format, args...) + ": " + err.Error()}, 1))
+}
+
+// WithMessage is like the package level WithMessage, with the settings of f.
+func (f *Factory) WithMessage(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return f.finish(newError(err, []any{message + ": " + err.Error()}, 1))
+}
+
+// WithStack is like the package level WithStack, with the settings of f.
+func (f *Factory) WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	return f.finish(newError(err, nil, 1))
+}
+
+// BadRequest is like the package level BadRequest, with the settings of f.
+func (f *Factory) BadRequest(nested error, format string, args ...any) error {
+	return f.withKind(nested, KindBadRequest, format, args)
+}
+
+// Unauthorized is like the package level Unauthorized, with the settings of
+// f.
+func (f *Factory) Unauthorized(nested error, format string, args ...any) error {
+	return f.withKind(nested, KindUnauthorized, format, args)
+}
+
+// Forbidden is like the package level Forbidden, with the settings of f.
+func (f *Factory) Forbidden(nested error, format string, args ...any) error {
+	return f.withKind(nested, KindForbidden, format, args)
+}
+
+// NotFound is like the package level NotFound, with the settings of f.
+func (f *Factory) NotFound(nested error, format string, args ...any) error {
+	return f.withKind(nested, KindNotFound, format, args)
+}
+
+// Conflict is like the package level Conflict, with the settings of f.
+func (f *Factory) Conflict(nested error, format string, args ...any) error {
+	return f.withKind(nested, KindConflict, format, args)
+}
+
+// Unprocessable is like the package level Unprocessable, with the settings
+// of f.
+func (f *Factory) Unprocessable(nested error, format string, args ...any) error {
+	return f.withKind(nested, KindUnprocessable, format, args)
+}
+
+// TooManyRequests is like the package level TooManyRequests, with the
+// settings of f.
+func (f *Factory) TooManyRequests(nested error, format string, args ...any) error {
+	return f.withKind(nested, KindTooManyRequests, format, args)
+}
+
+// Internal is like the package level Internal, with the settings of f.
+func (f *Factory) Internal(nested error, format string, args ...any) error {
+	return f.withKind(nested, KindInternal, format, args)
+}
+
+// Unavailable is like the package level Unavailable, with the settings of f.
+func (f *Factory) Unavailable(nested error, format string, args ...any) error {
+	return f.withKind(nested, KindUnavailable, format, args)
+}
+
+// Timeout is like the package level Timeout, with the settings of f.
+func (f *Factory) Timeout(nested error, format string, args ...any) error {
+	return f.withKind(nested, KindTimeout, format, args)
+}
+
+// NewWithArgs is like the package level NewWithArgs, with the settings of f.
+func (f *Factory) NewWithArgs(nested error, message string, args ...Option) error {
+	return f.finish(newWithArgs(nested, message, args, 1))
+}
+
+// NewMulti is like the package level NewMulti, with the settings of f.
+func (f *Factory) NewMulti(message string, code int, errs ...error) error {
+	e := newMulti(message, code, errs, 1)
+	if e == nil {
+		return nil
+	}
+	return f.finish(e)
+}
+
+// Trace is like the package level Trace, with the settings of f.
+func (f *Factory) Trace(err error) error {
+	if err == nil {
+		return nil
+	}
+	return f.finish(newTrace(err, 1))
+}
+
+// FromContextErr is like the package level FromContextErr, with the settings
+// of f.
+func (f *Factory) FromContextErr(ctx context.Context, msg string) error {
+	e := newContextErr(ctx, msg, 1)
+	if e == nil {
+		return nil
+	}
+	return f.finish(e)
+}
+
+// CarryAcross is like the package level CarryAcross, with the settings of f.
+func (f *Factory) CarryAcross(err error) error {
+	if err == nil {
+		return nil
+	}
+	return f.finish(newCarried(err, 1))
+}
+
+// ResumeFrom is like the package level ResumeFrom, with the settings of f
+// for the layer it creates. The copied layers of err keep their settings.
+func (f *Factory) ResumeFrom(carried error, err error) error {
+	return resumeFrom(f, carried, err)
+}
+
+// Translate is like the package level Translate, with the settings of f for
+// the layer it creates when the translation has a message.
+func (f *Factory) Translate(err error) error {
+	return translate(f, err)
+}
+
+// FromHTTPResponse is like the package level FromHTTPResponse, with the
+// settings of f.
+func (f *Factory) FromHTTPResponse(resp *http.Response, opts ...ResponseOption) error {
+	if resp == nil || resp.StatusCode < 400 {
+		return nil
+	}
+	return fromHTTPResponse(f, resp, nil, opts)
+}
+
+// FromAPIResponse is like the package level FromAPIResponse, with the
+// settings of f.
+func (f *Factory) FromAPIResponse(resp *http.Response, opts ...ResponseOption) error {
+	if resp == nil || resp.StatusCode < 400 {
+		return nil
+	}
+	return fromHTTPResponse(f, resp, decoderOf(resp), opts)
+}
+
+// NewValidation is like the package level NewValidation, with the settings
+// of f for the error returned by Err.
+func (f *Factory) NewValidation() *Validation {
+	return &Validation{factory: f}
+}
+
+// withKind creates the layer of the kind constructors above, attributed to
+// their caller.
+func (f *Factory) withKind(nested error, kind Kind, format string, args []any) error {
+	return f.finish(newError(nested, append([]any{kind.Code(), format}, args...), 2))
+}
+
+// finish sets f on the new layer e and notifies the hooks of f, or the
+// registered hooks if f is nil.
+func (f *Factory) finish(e *Error) error {
+	e.factory = f
+	notify(e)
+	return e
+}
@@ -0,0 +1,174 @@
+package goerr_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestFactory(t *testing.T) {
+	var created []string
+	errs := goerr.NewFactory(goerr.Options{
+		FrameFormat: goerr.FrameShort,
+		PathMode:    goerr.PathBasename,
+		Redactors:   []goerr.Redactor{goerr.KeyRedactor("token")},
+		Hooks:       []func(e *goerr.Error){func(e *goerr.Error) { created = append(created, e.Message()) }},
+	})
+
+	inner := errs.New(errors.New("token=abc expired"), http.StatusUnauthorized, "auth failed token=abc")
+	err := goerr.New(inner, "login failed token=abc")
+	stack := goerr.Stack(err)
+
+	if !strings.Contains(stack, "\tauth failed token=[REDACTED] (401) [factory_test.go:") || !strings.Contains(stack, "(TestFactory)]") {
+		t.Errorf("expected the factory settings on its layer, Got: %s", stack)
+	}
+	if !strings.Contains(stack, "login failed token=abc [/") || !strings.Contains(stack, "(goerr_test.TestFactory)]") {
+		t.Errorf("expected the global settings on the other layers, Got: %s", stack)
+	}
+	if len(created) != 1 || created[0] != "auth failed token=abc" {
+		t.Errorf("expected the factory hook to be called once, Got: %q", created)
+	}
+	if got := goerr.Code(err); got != http.StatusUnauthorized {
+		t.Errorf("Want: %d, Got: %d", http.StatusUnauthorized, got)
+	}
+
+	enriched := goerr.WithField(inner, "user", "alice")
+	if stack := goerr.Stack(enriched); !strings.Contains(stack, "(TestFactory)]") {
+		t.Errorf("expected enrichment to keep the factory settings, Got: %s", stack)
+	}
+}
+
+func TestFactoryWrap(t *testing.T) {
+	errs := goerr.NewFactory(goerr.Options{FrameFormat: goerr.FrameShort})
+	root := errors.New("connection refused")
+
+	for _, err := range []error{errs.Wrap(root, "query failed"), errs.Wrapf(root, "query %d failed", 1), errs.WithStack(root)} {
+		if !errors.Is(err, root) || !strings.HasSuffix(err.Error(), "connection refused") {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if stack := goerr.ListStacks(err)[0]; !strings.HasSuffix(stack, "(TestFactoryWrap)]") {
+			t.Errorf("expected the factory settings, Got: %s", stack)
+		}
+	}
+	if errs.Wrap(nil, "x") != nil || errs.Wrapf(nil, "x") != nil || errs.WithStack(nil) != nil {
+		t.Errorf("expected nil for a nil error")
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	defer goerr.Configure(goerr.Options{})
+
+	var hooked int
+	goerr.Configure(goerr.Options{
+		FrameFormat: goerr.FrameShort,
+		Redactors:   []goerr.Redactor{goerr.KeyRedactor("token")},
+		Hooks:       []func(e *goerr.Error){func(e *goerr.Error) { hooked++ }},
+	})
+
+	err := goerr.New(nil, "login failed token=abc")
+	if got := goerr.ListStacks(err)[0]; !strings.HasPrefix(got, "login failed token=[REDACTED] [") || !strings.HasSuffix(got, "(TestConfigure)]") {
+		t.Errorf("expected the configured settings, Got: %s", got)
+	}
+	if hooked != 1 {
+		t.Errorf("Want: 1 hook call, Got: %d", hooked)
+	}
+
+	goerr.Configure(goerr.Options{})
+	if got := goerr.ListStacks(err)[0]; !strings.HasPrefix(got, "login failed token=abc [") || !strings.HasSuffix(got, "(goerr_test.TestConfigure)]") {
+		t.Errorf("expected the default settings, Got: %s", got)
+	}
+}
+
+func TestFactoryConstructors(t *testing.T) {
+	var created int
+	errs := goerr.NewFactory(goerr.Options{
+		FrameFormat: goerr.FrameShort,
+		Redactors:   []goerr.Redactor{goerr.KeyRedactor("token")},
+		Hooks:       []func(e *goerr.Error){func(e *goerr.Error) { created++ }},
+	})
+	root := errors.New("connection refused")
+
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{errs.NewSkip(0, root, "query failed"), 0},
+		{errs.NewCtx(context.Background(), root, "query failed"), 0},
+		{errs.NewT(root, "query failed token={token}", goerr.Args{"token": "abc"}), 0},
+		{errs.Errorf("query failed: %w", root), 0},
+		{errs.Mask(root, http.StatusInternalServerError, "internal error"), http.StatusInternalServerError},
+		{errs.WithMessage(root, "query failed"), 0},
+		{errs.BadRequest(root, "invalid id %d", 1), http.StatusBadRequest},
+		{errs.Unauthorized(root, "unauthorized"), http.StatusUnauthorized},
+		{errs.Forbidden(root, "forbidden"), http.StatusForbidden},
+		{errs.NotFound(root, "not found"), http.StatusNotFound},
+		{errs.Conflict(root, "conflict"), http.StatusConflict},
+		{errs.Unprocessable(root, "unprocessable"), http.StatusUnprocessableEntity},
+		{errs.TooManyRequests(root, "too many requests"), http.StatusTooManyRequests},
+		{errs.Internal(root, "internal"), http.StatusInternalServerError},
+		{errs.Unavailable(root, "unavailable"), http.StatusServiceUnavailable},
+		{errs.Timeout(root, "timeout"), http.StatusGatewayTimeout},
+	}
+	for i, tt := range tests {
+		if !errors.Is(tt.err, root) || goerr.Code(tt.err) != tt.code {
+			t.Errorf("%d. unexpected error: %v (%d)", i, tt.err, goerr.Code(tt.err))
+		}
+		stack := goerr.ListStacks(tt.err)[0]
+		if !strings.HasSuffix(stack, "(TestFactoryConstructors)]") || strings.Contains(stack, "abc") {
+			t.Errorf("%d. expected the factory settings, Got: %s", i, stack)
+		}
+	}
+	if created != len(tests) {
+		t.Errorf("Want: %d hook calls, Got: %d", len(tests), created)
+	}
+	if errs.WithMessage(nil, "x") != nil {
+		t.Errorf("expected nil for a nil error")
+	}
+}
+
+func TestFactoryBuilders(t *testing.T) {
+	var created int
+	errs := goerr.NewFactory(goerr.Options{
+		FrameFormat: goerr.FrameShort,
+		Redactors:   []goerr.Redactor{goerr.KeyRedactor("token")},
+		Hooks:       []func(e *goerr.Error){func(e *goerr.Error) { created++ }},
+	})
+	root := errors.New("connection refused")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resp := &http.Response{
+		StatusCode: http.StatusBadGateway,
+		Status:     "502 Bad Gateway",
+		Request:    httptest.NewRequest(http.MethodGet, "https://rates.example.com/eur", nil),
+	}
+
+	tests := []error{
+		errs.NewWithArgs(root, "query failed", goerr.Arg("token", "abc")),
+		errs.NewMulti("all replicas failed", http.StatusServiceUnavailable, root, nil),
+		errs.Trace(root),
+		errs.FromContextErr(ctx, "request cancelled"),
+		errs.CarryAcross(root),
+		errs.ResumeFrom(root, nil),
+		errs.Translate(&quotaError{Limit: 100}),
+		errs.FromHTTPResponse(resp),
+		errs.FromAPIResponse(resp),
+		errs.NewValidation().Field("email", "must be valid").Err(),
+	}
+	for i, err := range tests {
+		stack := goerr.ListStacks(err)[0]
+		if !strings.Contains(stack, "(TestFactoryBuilders)") || strings.Contains(stack, "abc") {
+			t.Errorf("%d. expected the factory settings, Got: %s", i, stack)
+		}
+	}
+	if created != len(tests) {
+		t.Errorf("Want: %d hook calls, Got: %d", len(tests), created)
+	}
+	if errs.NewMulti("failed", 0, nil) != nil || errs.Trace(nil) != nil || errs.FromContextErr(context.Background(), "x") != nil {
+		t.Errorf("expected nil without an error")
+	}
+}
@@ -0,0 +1,71 @@
+package goerr
+
+// WithWeight returns err with the given weight set on its outermost layer.
+// The weight tells error budgets and circuit breakers how much the error
+// counts, e.g. 0.5 for a degraded response, 2 for a data loss or 0 for an
+// error that should not count at all. If err is not a goerr, it is wrapped in
+// a new goerr first.
+func WithWeight(err error, w float64) error {
+	if err == nil {
+		return nil
+	}
+
+	e := layer(err)
+	e.weight, e.weighted = w, true
+	return e
+}
+
+// Weight returns the weight last given in the call chain of err. Errors
+// without a weight weigh 1, and a nil error weighs 0.
+func Weight(err error) float64 {
+	if err == nil {
+		return 0
+	}
+
+	e := find(err, func(e *Error) bool { return e.weighted })
+	if e == nil {
+		return 1
+	}
+	return e.weight
+}
+
+// IsClientFault reports whether err was caused by the caller, that is its
+// code is in the 4xx range.
+//
+// Circuit breakers should ignore client faults and only trip on server
+// faults. With github.com/sony/gobreaker:
+//
+//	gobreaker.NewCircuitBreaker(gobreaker.Settings{
+//		IsSuccessful: func(err error) bool {
+//			return err == nil || goerr.IsClientFault(err)
+//		},
+//	})
+func IsClientFault(err error) bool {
+	code := Code(err)
+	return code >= 400 && code < 500
+}
+
+// IsServerFault reports whether err is a non nil error that is not a client
+// fault. Errors without any code are server faults.
+func IsServerFault(err error) bool {
+	return err != nil && !IsClientFault(err)
+}
+
+// IsClientError is the same as IsClientFault. It pairs with IsServerError
+// for metrics and alerts.
+func IsClientError(err error) bool {
+	return IsClientFault(err)
+}
+
+// IsServerError reports whether err is a server fault with a code in the 5xx
+// range or without any code. Unlike IsServerFault, which is true for every
+// non nil error that is not a client fault, it is false for errors with a
+// code outside the 4xx and 5xx ranges, like business codes, so that these
+// are neither client nor server errors.
+func IsServerError(err error) bool {
+	if !IsServerFault(err) {
+		return false
+	}
+	code := Code(err)
+	return code == 0 || code >= 500 && code < 600
+}
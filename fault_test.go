@@ -0,0 +1,84 @@
+package goerr_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestFaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		client bool
+		server bool
+	}{
+		{"nil", nil, false, false},
+		{"plain", errors.New("plain"), false, true},
+		{"no code", goerr.New(nil, "failed"), false, true},
+		{"bad request", goerr.New(nil, http.StatusBadRequest, "failed"), true, false},
+		{"unavailable", goerr.New(nil, http.StatusServiceUnavailable, "failed"), false, true},
+		{"kind", goerr.WithKind(goerr.New(nil, "failed"), goerr.KindNotFound), true, false},
+		{"nested", goerr.New(goerr.New(nil, http.StatusConflict, "failed"), "wrapped"), true, false},
+	}
+
+	for _, tt := range tests {
+		if got := goerr.IsClientFault(tt.err); got != tt.client {
+			t.Errorf("%s: IsClientFault. Want: %v, Got: %v", tt.name, tt.client, got)
+		}
+		if got := goerr.IsServerFault(tt.err); got != tt.server {
+			t.Errorf("%s: IsServerFault. Want: %v, Got: %v", tt.name, tt.server, got)
+		}
+	}
+}
+
+func TestClientServerErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		client bool
+		server bool
+	}{
+		{"nil", nil, false, false},
+		{"plain", errors.New("plain"), false, true},
+		{"no code", goerr.New(nil, "failed"), false, true},
+		{"inferred", goerr.New(context.DeadlineExceeded, "failed"), false, true},
+		{"not found", goerr.New(nil, http.StatusNotFound, "failed"), true, false},
+		{"client closed", goerr.New(nil, goerr.StatusClientClosedRequest, "failed"), true, false},
+		{"unavailable", goerr.New(nil, http.StatusServiceUnavailable, "failed"), false, true},
+		{"redirect", goerr.New(nil, http.StatusFound, "failed"), false, false},
+		{"business code", goerr.New(nil, 1042, "failed"), false, false},
+	}
+
+	for _, tt := range tests {
+		if got := goerr.IsClientError(tt.err); got != tt.client {
+			t.Errorf("%s: IsClientError. Want: %v, Got: %v", tt.name, tt.client, got)
+		}
+		if got := goerr.IsServerError(tt.err); got != tt.server {
+			t.Errorf("%s: IsServerError. Want: %v, Got: %v", tt.name, tt.server, got)
+		}
+	}
+}
+
+func TestWeight(t *testing.T) {
+	err := goerr.New(goerr.WithWeight(goerr.New(nil, "degraded"), 0.5), "wrapped")
+
+	if got := goerr.Weight(err); got != 0.5 {
+		t.Errorf("Want: %v, Got: %v", 0.5, got)
+	}
+	if got := goerr.Weight(errors.New("plain")); got != 1 {
+		t.Errorf("Want: %v, Got: %v", 1, got)
+	}
+	if got := goerr.Weight(nil); got != 0 {
+		t.Errorf("Want: %v, Got: %v", 0, got)
+	}
+	if got := goerr.Weight(goerr.WithWeight(goerr.WithWeight(goerr.New(nil, "cancelled"), 2), 0)); got != 0 {
+		t.Errorf("expected an explicit zero weight. Want: %v, Got: %v", 0, got)
+	}
+	if got := goerr.Weight(goerr.Compact(goerr.New(goerr.New(goerr.WithWeight(goerr.New(nil, "cancelled"), 0), "retried"), "wrapped"), 1)); got != 0 {
+		t.Errorf("expected compaction to keep a zero weight. Want: %v, Got: %v", 0, got)
+	}
+}
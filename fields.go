@@ -0,0 +1,125 @@
+package goerr
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WithField returns err with the field key set to value on its outermost
+// layer. If err is not a goerr, it is wrapped in a new goerr first.
+func WithField(err error, key string, value any) error {
+	return WithFields(err, map[string]any{key: value})
+}
+
+// WithFields returns err with the given fields added to its outermost layer.
+// Fields already set on the layer with the same key are replaced. If err is
+// not a goerr, it is wrapped in a new goerr first.
+func WithFields(err error, fields map[string]any) error {
+	if err == nil {
+		return nil
+	}
+
+	return layer(err).withFields(fields)
+}
+
+// withFields sets fields on e, which must be a copy made by layer, without
+// modifying the fields of the layer it was copied from.
+func (e *Error) withFields(fields map[string]any) *Error {
+	merged := make(map[string]any, len(e.fields)+len(fields))
+	for k, v := range e.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	e.fields = merged
+	return e
+}
+
+// Fields returns the fields of all layers of err merged into one map, with
+// the registered redactors applied. If several layers set the same key, the
+// value of the outermost layer is returned.
+func Fields(err error) map[string]any {
+	fields := map[string]any{}
+	walk(err, func(_ int, err error) bool {
+		if e, ok := err.(*Error); ok {
+			for k, v := range e.fields {
+				if _, ok := fields[k]; !ok {
+					fields[k] = redactField(e.factory, k, v)
+				}
+			}
+		}
+		return true
+	})
+	return fields
+}
+
+// FieldsByLayer returns the fields of every layer of err, outermost first,
+// with the registered redactors applied. The slice has one map per entry of
+// ListStacks, which is empty for layers without fields.
+func FieldsByLayer(err error) []map[string]any {
+	var result []map[string]any
+	walk(err, func(_ int, err error) bool {
+		fields := map[string]any{}
+		if e, ok := err.(*Error); ok {
+			for k, v := range e.fields {
+				fields[k] = redactField(e.factory, k, v)
+			}
+		}
+		result = append(result, fields)
+		return true
+	})
+	return result
+}
+
+// QualifiedFields returns the fields of all layers of err in one map, with
+// every key qualified by the function of its layer, like
+// "samplesrc.Service.order_id". If several layers were created in the same
+// function, the qualifier of the inner ones includes their depth, like
+// "samplesrc.Service[2].order_id", so no value is lost.
+func QualifiedFields(err error) map[string]any {
+	fields := map[string]any{}
+	seen := map[string]bool{}
+	walk(err, func(depth int, err error) bool {
+		e, ok := err.(*Error)
+		if !ok || len(e.fields) == 0 {
+			return true
+		}
+
+		qualifier := formatFuncName(e.factory, e.Func())
+		if seen[qualifier] {
+			qualifier += "[" + strconv.Itoa(depth) + "]"
+		}
+		seen[qualifier] = true
+
+		for k, v := range e.fields {
+			fields[qualifier+"."+k] = redactField(e.factory, k, v)
+		}
+		return true
+	})
+	return fields
+}
+
+// writeFields writes the fields of e as space separated key=value pairs,
+// sorted by key.
+func (e *Error) writeFields(b *strings.Builder) {
+	for _, k := range sortedKeys(e.fields) {
+		b.WriteByte(' ')
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(fmt.Sprint(redactField(e.factory, k, e.fields[k])))
+	}
+}
+
+// sortedKeys returns the keys of fields sorted in byte order, the order in
+// which all renderers write fields so that their output is stable.
+func sortedKeys(fields map[string]any) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
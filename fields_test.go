@@ -0,0 +1,103 @@
+package goerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestFields(t *testing.T) {
+	repository := func() error {
+		err := goerr.New(errors.New("duplicate key"), 409, "order insert failed")
+		return goerr.WithFields(err, map[string]any{"order_id": 42, "table": "orders"})
+	}
+	err := goerr.WithField(goerr.New(repository(), "service error"), "order_id", 43)
+
+	fields := goerr.Fields(err)
+	if len(fields) != 2 || fields["order_id"] != 43 || fields["table"] != "orders" {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+
+	stack := goerr.Stack(err)
+	if !strings.Contains(stack, "order insert failed (409) order_id=42 table=orders [") {
+		t.Errorf("stack do not contain the fields. %s", stack)
+	}
+	if !strings.Contains(stack, "service error order_id=43 [") {
+		t.Errorf("stack do not contain the fields. %s", stack)
+	}
+}
+
+func TestWithFieldsDoesNotMutate(t *testing.T) {
+	base := goerr.WithField(goerr.New(nil, "base"), "a", 1)
+	_ = goerr.WithField(base, "b", 2)
+
+	if fields := goerr.Fields(base); len(fields) != 1 {
+		t.Errorf("original error was mutated. Got: %v", fields)
+	}
+}
+
+func TestFieldsByLayer(t *testing.T) {
+	retry := func(err error, attempt int) error {
+		return goerr.WithField(goerr.New(err, "attempt failed"), "attempt", attempt)
+	}
+	err := retry(retry(goerr.New(errors.New("timeout"), "call failed"), 1), 2)
+
+	layers := goerr.FieldsByLayer(err)
+	if len(layers) != 4 {
+		t.Fatalf("Nr. of layers. Want: %d, Got: %d", 4, len(layers))
+	}
+	if layers[0]["attempt"] != 2 || layers[1]["attempt"] != 1 || len(layers[2]) != 0 || len(layers[3]) != 0 {
+		t.Errorf("unexpected fields: %v", layers)
+	}
+
+	qualified := goerr.QualifiedFields(err)
+	want := map[string]any{
+		"goerr_test.TestFieldsByLayer.func1.attempt":    2,
+		"goerr_test.TestFieldsByLayer.func1[1].attempt": 1,
+	}
+	if len(qualified) != len(want) {
+		t.Fatalf("Want: %v, Got: %v", want, qualified)
+	}
+	for k, v := range want {
+		if qualified[k] != v {
+			t.Errorf("Want: %v, Got: %v", want, qualified)
+		}
+	}
+}
+
+func TestFieldOrder(t *testing.T) {
+	fields := map[string]any{}
+	for _, k := range []string{"zone", "b", "a_2", "A", "a", "order_id", "_x", "amount", "Zeta", "10"} {
+		fields[k] = len(k)
+	}
+	render := func() string {
+		err := goerr.WithFields(goerr.New(nil, "failed"), fields)
+		b, _ := json.Marshal(err)
+		bin, _ := goerr.MarshalBinary(err)
+		return goerr.Stack(err) + string(b) + goerr.Pretty(err) + string(bin)
+	}
+
+	first := render()
+	for i := 0; i < 20; i++ {
+		if got := render(); got != first {
+			t.Fatalf("expected the same output every time. Want: %s, Got: %s", first, got)
+		}
+	}
+
+	want := " 10=2 A=1 Zeta=4 _x=2 a=1 a_2=3 amount=6 b=1 order_id=8 zone=4 ["
+	if !strings.Contains(goerr.Stack(goerr.WithFields(goerr.New(nil, "failed"), fields)), want) {
+		t.Errorf("Want fields in byte order: %q, Got: %s", want, first)
+	}
+
+	var keys []string
+	goerr.Walk(goerr.WithFields(goerr.New(nil, "failed"), fields), func(l goerr.Layer) bool {
+		keys = l.FieldKeys()
+		return false
+	})
+	if got := strings.Join(keys, ","); got != "10,A,Zeta,_x,a,a_2,amount,b,order_id,zone" {
+		t.Errorf("Want: keys in byte order, Got: %s", got)
+	}
+}
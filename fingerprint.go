@@ -0,0 +1,33 @@
+package goerr
+
+import (
+	"hash/fnv"
+	"reflect"
+	"strconv"
+)
+
+// Fingerprint returns a short hash identifying where and how err was raised,
+// to group occurrences of the same error. It covers the code of err and the
+// function of every goerr layer, or the type of every other layer, but
+// neither messages nor line numbers, so it is stable across requests with
+// different values and across unrelated edits of the source files.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	h := fnv.New64a()
+	h.Write(strconv.AppendInt(nil, int64(Code(err)), 10))
+	walk(err, func(_ int, err error) bool {
+		h.Write([]byte{0})
+		if e, ok := err.(*Error); ok {
+			h.Write([]byte(e.Func()))
+		} else {
+			h.Write([]byte(reflect.TypeOf(err).String()))
+		}
+		return true
+	})
+
+	var b [16]byte
+	return string(strconv.AppendUint(b[:0], h.Sum64(), 16))
+}
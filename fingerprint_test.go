@@ -0,0 +1,32 @@
+package goerr_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+func failWith(id int) error {
+	return goerr.New(errors.New("not found"), http.StatusNotFound, "order %d not found", id)
+}
+
+func TestFingerprint(t *testing.T) {
+	if got := goerr.Fingerprint(nil); got != "" {
+		t.Errorf("Want: empty, Got: %s", got)
+	}
+
+	a, b := goerr.Fingerprint(failWith(1)), goerr.Fingerprint(failWith(2))
+	if a == "" || a != b {
+		t.Errorf("expected equal fingerprints for different values, Got: %s and %s", a, b)
+	}
+
+	if c := goerr.Fingerprint(samplesrc.Service()); c == a {
+		t.Errorf("expected different fingerprints for different chains, Got: %s", c)
+	}
+	if c := goerr.Fingerprint(goerr.New(nil, http.StatusGone, "gone")); c == a {
+		t.Errorf("expected different fingerprints for different layers, Got: %s", c)
+	}
+}
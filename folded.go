@@ -0,0 +1,81 @@
+package goerr
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ToFolded aggregates the call stacks at which errs originated, those of
+// their innermost goerr layers, into the folded stack format of flame graph
+// tools like flamegraph.pl, speedscope and inferno: one line per distinct
+// stack, with the functions from the outermost caller to the function that
+// created the layer separated by ';', followed by a space and the number of
+// errors that originated there. Lines are sorted. Errors without a goerr
+// layer with a call stack, e.g. created while stack capture was off, are
+// left out.
+//
+//	os.WriteFile("errors.folded", []byte(goerr.ToFolded(sampled)), 0o644)
+//	// flamegraph.pl errors.folded > errors.svg
+func ToFolded(errs []error) string {
+	counts := map[string]int{}
+	var b strings.Builder
+	for _, err := range errs {
+		var origin *Error
+		walk(err, func(_ int, err error) bool {
+			if e, ok := err.(*Error); ok && len(e.stack) > 0 {
+				origin = e
+			}
+			return true
+		})
+		if origin == nil {
+			continue
+		}
+
+		b.Reset()
+		frames := origin.StackFrames()
+		for i := len(frames) - 1; i >= 0; i-- {
+			f := &frames[i]
+			if f.Package == "runtime" && f.Name == "goexit" {
+				continue
+			}
+			if b.Len() > 0 {
+				b.WriteByte(';')
+			}
+			writeFoldedFrame(&b, f)
+		}
+		counts[b.String()]++
+	}
+
+	lines := make([]string, 0, len(counts))
+	for stack, n := range counts {
+		lines = append(lines, stack+" "+strconv.Itoa(n))
+	}
+	sort.Strings(lines)
+
+	b.Reset()
+	for _, line := range lines {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// foldedEscaper replaces the characters separating frames and counts in the
+// folded format.
+var foldedEscaper = strings.NewReplacer(";", ":", " ", "_")
+
+// writeFoldedFrame writes the function of frame, or its offset if the
+// function could not be resolved, escaped with foldedEscaper.
+func writeFoldedFrame(b *strings.Builder, frame *StackFrame) {
+	name := frame.Package + "." + frame.Name
+	switch {
+	case frame.Name == "" && frame.Offset != 0:
+		name = "offset_" + strconv.FormatInt(frame.Offset, 16)
+	case frame.Name == "":
+		name = "?"
+	case frame.Package == "":
+		name = frame.Name
+	}
+	b.WriteString(foldedEscaper.Replace(name))
+}
@@ -0,0 +1,53 @@
+package goerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestToFolded(t *testing.T) {
+	defer goerr.SetStackCapturer(nil)
+
+	handler := goerr.StackFrame{Package: "example.com/app", Name: "Handle", File: "/app/handler.go", LineNumber: 10}
+	main := goerr.StackFrame{Package: "main", Name: "main", File: "/app/main.go", LineNumber: 5}
+	query := goerr.StackFrame{Package: "example.com/app/db", Name: "(*Repo).Get", File: "/app/db/repo.go", LineNumber: 30}
+	exit := goerr.StackFrame{Package: "runtime", Name: "goexit"}
+
+	goerr.SetStackCapturer(goerr.FixedStack(query, handler, main, exit))
+	fromDB := goerr.New(errors.New("no rows"), "get failed")
+	goerr.SetStackCapturer(goerr.FixedStack(handler, main, exit))
+	fromHandler := goerr.New(nil, "invalid id")
+
+	var errs []error
+	for i := 0; i < 3; i++ {
+		// the outer layer does not matter, the stack of the innermost one does
+		errs = append(errs, goerr.New(fromDB, "request failed"))
+	}
+	errs = append(errs, fromHandler, errors.New("no goerr layer"), nil)
+
+	want := strings.Join([]string{
+		"main.main;example.com/app.Handle 1",
+		"main.main;example.com/app.Handle;example.com/app/db.(*Repo).Get 3",
+		"",
+	}, "\n")
+	if got := goerr.ToFolded(errs); got != want {
+		t.Errorf("Want: %q, Got: %q", want, got)
+	}
+}
+
+func TestToFoldedCapturedStack(t *testing.T) {
+	got := goerr.ToFolded([]error{goerr.New(nil, "failed")})
+
+	if !strings.HasSuffix(got, ";github.com/angel-one/goerr_test.TestToFoldedCapturedStack 1\n") {
+		t.Errorf("expected the stack to end in the test, Got: %q", got)
+	}
+	if strings.Contains(got, "goexit") || strings.Count(got, "\n") != 1 {
+		t.Errorf("expected a single line without runtime.goexit, Got: %q", got)
+	}
+	if got := goerr.ToFolded(nil); got != "" {
+		t.Errorf("Want: empty, Got: %q", got)
+	}
+}
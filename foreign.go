@@ -0,0 +1,91 @@
+package goerr
+
+import (
+	"errors"
+	"reflect"
+)
+
+// foreignFrame returns the frame at which err was created, if err is not a
+// goerr of this package but carries a call stack of its own, as the errors of
+// github.com/pkg/errors, github.com/go-errors/errors and goerr of another
+// module version do. The stack is probed by method name, so none of these
+// packages is needed:
+//
+//	StackTrace() []T    // T is a program counter, as in pkg/errors
+//	Callers() []uintptr
+//	StackFrames() []T   // T has a ProgramCounter field, as StackFrame
+func foreignFrame(err error) (StackFrame, bool) {
+	if _, ok := err.(*Error); ok || err == nil {
+		return StackFrame{}, false
+	}
+
+	v := reflect.ValueOf(err)
+	for _, name := range []string{"StackTrace", "Callers", "StackFrames"} {
+		m := v.MethodByName(name)
+		if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 || m.Type().Out(0).Kind() != reflect.Slice {
+			continue
+		}
+
+		elem := m.Type().Out(0).Elem()
+		if elem.Kind() != reflect.Uintptr && !(elem.Kind() == reflect.Struct && hasPCField(elem)) {
+			continue
+		}
+
+		stack := m.Call(nil)[0]
+		if stack.Len() == 0 {
+			return StackFrame{}, false
+		}
+		pc := stack.Index(0)
+		if pc.Kind() == reflect.Struct {
+			pc = pc.FieldByName("ProgramCounter")
+		}
+		frame := NewStackFrame(uintptr(pc.Uint()))
+		return frame, frame.Func() != nil
+	}
+	return StackFrame{}, false
+}
+
+func hasPCField(t reflect.Type) bool {
+	f, ok := t.FieldByName("ProgramCounter")
+	return ok && f.Type.Kind() == reflect.Uintptr
+}
+
+// next returns the layer following err in the chain: the error wrapped by a
+// goerr layer, or, for an error carrying a foreign call stack, the next error
+// in its chain that is a goerr layer or carries a call stack itself. Errors
+// in between are skipped, as their messages are usually contained in the
+// message of err. For other errors next returns nil, so they end the chain.
+func next(err error) error {
+	if e, ok := err.(*Error); ok {
+		return e.err
+	}
+	if _, ok := foreignFrame(err); !ok {
+		return nil
+	}
+
+	for depth := 0; depth < MaxChainDepth; depth++ {
+		err = unwrapCause(err)
+		if err == nil {
+			return nil
+		}
+		if _, ok := err.(*Error); ok {
+			return err
+		}
+		if _, ok := foreignFrame(err); ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// unwrapCause unwraps err with errors.Unwrap or, for the errors of
+// pkg/errors before version 0.9, with their Cause method.
+func unwrapCause(err error) error {
+	if u := errors.Unwrap(err); u != nil {
+		return u
+	}
+	if c, ok := err.(interface{ Cause() error }); ok {
+		return c.Cause()
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package goerr_test
+
+import (
+	"errors"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+// stackTrace mimics the StackTrace of github.com/pkg/errors.
+type stackTrace []frame
+
+type frame uintptr
+
+// pkgError mimics an error of github.com/pkg/errors carrying a stack.
+type pkgError struct {
+	msg   string
+	cause error
+	stack []uintptr
+}
+
+func newPkgError(msg string, cause error) error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	return &pkgError{msg: msg, cause: cause, stack: pcs[:n]}
+}
+
+func (e *pkgError) Error() string {
+	if e.cause == nil {
+		return e.msg
+	}
+	return e.msg + ": " + e.cause.Error()
+}
+
+func (e *pkgError) Unwrap() error { return e.cause }
+
+func (e *pkgError) StackTrace() stackTrace {
+	st := make(stackTrace, len(e.stack))
+	for i, pc := range e.stack {
+		st[i] = frame(pc)
+	}
+	return st
+}
+
+// messageError mimics a layer of pkg/errors without a stack.
+type messageError struct {
+	msg   string
+	cause error
+}
+
+func (e *messageError) Error() string { return e.msg + ": " + e.cause.Error() }
+func (e *messageError) Cause() error  { return e.cause }
+
+func fetch() error {
+	return newPkgError("connection refused", nil)
+}
+
+func query() error {
+	return newPkgError("query failed", &messageError{"retry", fetch()})
+}
+
+func TestForeignStacks(t *testing.T) {
+	err := goerr.New(query(), "service failed")
+
+	got := goerr.ListStacks(err)
+	t.Log(goerr.Stack(err))
+
+	wants := []string{
+		`^service failed \[.*foreign_test.go:\d+ \(goerr_test.TestForeignStacks\)\]$`,
+		`^query failed: retry: connection refused \[.*foreign_test.go:\d+ \(goerr_test.query\)\]$`,
+		`^connection refused \[.*foreign_test.go:\d+ \(goerr_test.fetch\)\]$`,
+	}
+	if len(got) != len(wants) {
+		t.Fatalf("Nr. of layers. Want: %d, Got: %q", len(wants), got)
+	}
+	for i, want := range wants {
+		if !regexp.MustCompile(want).MatchString(got[i]) {
+			t.Errorf("layer %d. Want: %s, Got: %s", i, want, got[i])
+		}
+	}
+
+	var funcs []string
+	goerr.Walk(err, func(l goerr.Layer) bool {
+		funcs = append(funcs, l.Function)
+		return true
+	})
+	if strings.Join(funcs, ",") != "goerr_test.TestForeignStacks,goerr_test.query,goerr_test.fetch" {
+		t.Errorf("unexpected functions: %q", funcs)
+	}
+}
+
+func TestForeignStackTopLevel(t *testing.T) {
+	got := goerr.Stack(query())
+
+	if !strings.HasPrefix(got, "\nquery failed") || !strings.Contains(got, "\n\tconnection refused [") {
+		t.Errorf("expected a multiline stack, Got: %s", got)
+	}
+}
+
+func TestPlainErrorsEndChain(t *testing.T) {
+	err := goerr.New(errors.New("outer"), "service failed")
+
+	if got := len(goerr.ListStacks(err)); got != 2 {
+		t.Errorf("Nr. of layers. Want: %d, Got: %d", 2, got)
+	}
+}
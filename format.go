@@ -0,0 +1,57 @@
+package goerr
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// FrameFormat controls how function names are rendered in stack lines.
+type FrameFormat int32
+
+const (
+	// FramePackage renders the package name, receiver and function, e.g.
+	// samplesrc.(*Service).Get. This is the default.
+	FramePackage FrameFormat = iota
+	// FrameFull renders the import path qualified name, e.g.
+	// github.com/angel-one/goerr/samplesrc.(*Service).Get.
+	FrameFull
+	// FrameReceiver renders the receiver and function without the package,
+	// e.g. (*Service).Get.
+	FrameReceiver
+	// FrameShort renders only the function name, e.g. Get.
+	FrameShort
+)
+
+var frameFormat atomic.Int32
+
+// SetFrameFormat sets how function names are rendered by Stack and
+// ListStacks.
+func SetFrameFormat(f FrameFormat) {
+	frameFormat.Store(int32(f))
+}
+
+// formatFuncName renders the fully qualified function name as returned by
+// runtime.Func.Name in the FrameFormat of f, or the configured one if f is
+// nil.
+func formatFuncName(f *Factory, name string) string {
+	format := FrameFormat(frameFormat.Load())
+	if f != nil {
+		format = f.opts.FrameFormat
+	}
+
+	// The package ends at the first period after the last slash, see
+	// splitFuncName.
+	switch format {
+	case FrameFull:
+		return strings.ReplaceAll(name, "%2e", ".")
+	case FrameReceiver:
+		name = name[strings.LastIndex(name, "/")+1:]
+		if period := strings.Index(name, "."); period >= 0 {
+			name = name[period+1:]
+		}
+		return name
+	case FrameShort:
+		return name[strings.LastIndex(name, ".")+1:]
+	}
+	return strings.ReplaceAll(name[strings.LastIndex(name, "/")+1:], "%2e", ".")
+}
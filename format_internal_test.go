@@ -0,0 +1,31 @@
+package goerr
+
+import "testing"
+
+func TestFormatFuncNameDottedPackage(t *testing.T) {
+	defer SetFrameFormat(FramePackage)
+
+	tests := []struct {
+		format FrameFormat
+		want   string
+	}{
+		{FramePackage, "yaml.v3.(*Decoder).Decode.func1"},
+		{FrameFull, "gopkg.in/yaml.v3.(*Decoder).Decode.func1"},
+		{FrameReceiver, "(*Decoder).Decode.func1"},
+		{FrameShort, "func1"},
+	}
+	for _, tt := range tests {
+		SetFrameFormat(tt.format)
+
+		if got := formatFuncName(nil, "gopkg.in/yaml%2ev3.(*Decoder).Decode.func1"); got != tt.want {
+			t.Errorf("format %d. Want: %s, Got: %s", tt.format, tt.want, got)
+		}
+	}
+}
+
+func TestSplitFuncNameDottedPackage(t *testing.T) {
+	pkg, fn := splitFuncName("gopkg.in/yaml%2ev3.(*Decoder).Decode")
+	if pkg != "gopkg.in/yaml.v3" || fn != "(*Decoder).Decode" {
+		t.Errorf("Want: gopkg.in/yaml.v3 (*Decoder).Decode, Got: %s %s", pkg, fn)
+	}
+}
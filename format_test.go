@@ -0,0 +1,33 @@
+package goerr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+func TestSetFrameFormat(t *testing.T) {
+	defer goerr.SetFrameFormat(goerr.FramePackage)
+
+	tests := []struct {
+		format goerr.FrameFormat
+		want   string
+	}{
+		{goerr.FramePackage, "(samplesrc.(*Store).Get)]"},
+		{goerr.FrameFull, "(github.com/angel-one/goerr/samplesrc.(*Store).Get)]"},
+		{goerr.FrameReceiver, "((*Store).Get)]"},
+		{goerr.FrameShort, "(Get)]"},
+	}
+
+	err := (&samplesrc.Store{}).Get()
+	for _, tt := range tests {
+		goerr.SetFrameFormat(tt.format)
+
+		got := goerr.Stack(err)
+		if !strings.HasSuffix(got, tt.want) {
+			t.Errorf("format %d. Want suffix: %s; Got: %s", tt.format, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package goerr
+
+import (
+	"strings"
+	"sync/atomic"
+	"text/template"
+	"time"
+)
+
+// Frame describes one layer of an error chain, as it is rendered in a line of
+// Stack. Redactors, the path mode and the frame format are already applied.
+// For an error that is not a goerr only Message and Depth are set, along with
+// File, Line and Function if it carries a call stack of another package.
+type Frame struct {
+	Message  string
+	Code     int
+	Severity SeverityLevel
+	Kind     Kind
+	// Fields are the fields of the layer. Renderers should write them in
+	// the order of FieldKeys, like Stack does.
+	Fields   map[string]any
+	File     string
+	Line     int
+	Function string
+	Depth    int
+	// Goroutine is the ID of the goroutine which created the layer, if
+	// goroutine capture was on, see SetGoroutineCapture.
+	Goroutine uint64
+	// Deadline is the deadline of the context the layer was created with,
+	// if the chain below the layer has context.DeadlineExceeded, see
+	// NewCtx.
+	Deadline time.Time
+}
+
+// FieldKeys returns the keys of the fields of f sorted in byte order, the
+// order in which Stack, Pretty and the JSON marshaler render fields.
+func (f Frame) FieldKeys() []string {
+	return sortedKeys(f.Fields)
+}
+
+// newFrame describes err, which is a layer at the given depth.
+func newFrame(depth int, err error) Frame {
+	e, ok := err.(*Error)
+	if !ok {
+		f := Frame{Message: redactMessage(nil, stackMessage(err)), Depth: depth}
+		if frame, ok := foreignFrame(err); ok {
+			f.File = formatPath(nil, &frame)
+			f.Line = frame.LineNumber
+			f.Function = formatFuncName(nil, frame.Func().Name())
+		}
+		return f
+	}
+
+	f := Frame{Message: redactMessage(e.factory, e.message), Depth: depth}
+	f.Code = e.code
+	f.Severity = e.severity
+	f.Kind = e.kind
+	if len(e.fields) > 0 {
+		f.Fields = make(map[string]any, len(e.fields))
+		for k, v := range e.fields {
+			f.Fields[k] = redactField(e.factory, k, v)
+		}
+	}
+	f.File = formatPath(e.factory, &e.frames[0])
+	f.Line = e.frames[0].LineNumber
+	f.Function = formatFuncName(e.factory, e.Func())
+	f.Goroutine = e.goroutine
+	if e.deadlineExceeded() {
+		f.Deadline = e.deadline
+	}
+	return f
+}
+
+// A FrameFormatter renders the line of one layer in Stack and ListStacks.
+// Stack still puts every line on its own line, indented by its depth.
+type FrameFormatter func(Frame) string
+
+var frameFormatter atomic.Pointer[FrameFormatter]
+
+// SetFrameFormatter sets the formatter used to render the line of every
+// layer, e.g. to conform to a log format standard. Passing nil restores the
+// default format.
+func SetFrameFormatter(f FrameFormatter) {
+	if f == nil {
+		frameFormatter.Store(nil)
+		return
+	}
+	frameFormatter.Store(&f)
+}
+
+// formatter returns the FrameFormatter of the factory of e, or the
+// configured one if e is nil or was not created by a factory.
+func (e *Error) formatter() FrameFormatter {
+	if e != nil && e.factory != nil {
+		return e.factory.opts.Formatter
+	}
+	if f := frameFormatter.Load(); f != nil {
+		return *f
+	}
+	return nil
+}
+
+// TemplateFormatter returns a FrameFormatter that executes t with the Frame
+// of every layer. If t fails, the error of the template is rendered instead.
+//
+//	t := template.Must(template.New("frame").Parse(`{{.File}}:{{.Line}} {{.Function}}: {{.Message}}`))
+//	goerr.SetFrameFormatter(goerr.TemplateFormatter(t))
+func TemplateFormatter(t *template.Template) FrameFormatter {
+	return func(f Frame) string {
+		var b strings.Builder
+		if err := t.Execute(&b, f); err != nil {
+			return err.Error()
+		}
+		return b.String()
+	}
+}
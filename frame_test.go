@@ -0,0 +1,49 @@
+package goerr_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"text/template"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+func TestSetFrameFormatter(t *testing.T) {
+	goerr.SetFrameFormatter(func(f goerr.Frame) string {
+		return fmt.Sprintf("%s:%d|%s|%d|%s", f.File, f.Line, f.Function, f.Code, f.Message)
+	})
+	defer goerr.SetFrameFormatter(nil)
+
+	err := goerr.New(samplesrc.Service(), 409, "controller failed")
+
+	pattern := `^
+.*/goerr/frame_test.go:\d+\|goerr_test.TestSetFrameFormatter\|409\|controller failed
+\t.*/goerr/samplesrc/samples.go:20\|samplesrc.Service\|0\|service failed
+\t\t.*/goerr/samplesrc/samples.go:27\|samplesrc.Repository\|0\|error from database$`
+	if match, _ := regexp.MatchString(pattern, goerr.Stack(err)); !match {
+		t.Errorf("stack is not matching the expectation: %s", goerr.Stack(err))
+	}
+}
+
+func TestTemplateFormatter(t *testing.T) {
+	tmpl := template.Must(template.New("frame").Parse(`{{.Depth}} {{.Function}}: {{.Message}}{{if .Code}} ({{.Code}}){{end}}`))
+	goerr.SetFrameFormatter(goerr.TemplateFormatter(tmpl))
+	defer goerr.SetFrameFormatter(nil)
+
+	stacks := goerr.ListStacks(goerr.New(samplesrc.Repository(), 500, "service failed"))
+
+	want := []string{
+		"0 goerr_test.TestTemplateFormatter: service failed (500)",
+		"1 samplesrc.Repository: error from database",
+	}
+	if len(stacks) != len(want) {
+		t.Fatalf("Want: %v, Got: %v", want, stacks)
+	}
+	for i := range want {
+		if stacks[i] != want[i] {
+			t.Errorf("Want: %s, Got: %s", want[i], stacks[i])
+		}
+	}
+}
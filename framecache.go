@@ -0,0 +1,105 @@
+package goerr
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultFrameCacheSize is the number of resolved frames kept by the frame
+// cache unless set with SetFrameCacheSize.
+const DefaultFrameCacheSize = 4096
+
+// FrameCacheStatistics describe the use of the frame cache since the program
+// started or the cache was last resized.
+type FrameCacheStatistics struct {
+	// Hits is the number of frames found in the cache.
+	Hits int64
+	// Misses is the number of frames resolved with the runtime.
+	Misses int64
+	// Entries is the number of frames in the cache.
+	Entries int
+}
+
+var frameCache = struct {
+	sync.RWMutex
+	size   int
+	frames map[uintptr]StackFrame
+	hits   atomic.Int64
+	misses atomic.Int64
+}{size: DefaultFrameCacheSize, frames: map[uintptr]StackFrame{}}
+
+// SetFrameCacheSize sets the number of frames kept by the cache of resolved
+// program counters. Hot wrap sites capture the same program counters over
+// and over, and the cache spares resolving their function, file and line
+// with the runtime every time. When the cache is full, it is emptied. A size
+// of 0 turns the cache off. Resizing empties the cache and resets its
+// statistics.
+func SetFrameCacheSize(n int) {
+	if n < 0 {
+		n = 0
+	}
+	frameCache.Lock()
+	frameCache.size = n
+	frameCache.frames = make(map[uintptr]StackFrame)
+	frameCache.hits.Store(0)
+	frameCache.misses.Store(0)
+	frameCache.Unlock()
+}
+
+// FrameCacheStats returns the statistics of the frame cache, e.g. to export
+// its hit rate as a metric and size it, see SetFrameCacheSize.
+func FrameCacheStats() FrameCacheStatistics {
+	frameCache.RLock()
+	defer frameCache.RUnlock()
+	return FrameCacheStatistics{
+		Hits:    frameCache.hits.Load(),
+		Misses:  frameCache.misses.Load(),
+		Entries: len(frameCache.frames),
+	}
+}
+
+// resolveFrames sets frames[i] to the frame of stack[i], from the cache if
+// possible. Zero program counters leave zero frames.
+func resolveFrames(stack []uintptr, frames []StackFrame) {
+	hits, misses := 0, 0
+	frameCache.RLock()
+	on := frameCache.size > 0
+	for i, pc := range stack {
+		if pc == 0 {
+			frames[i] = StackFrame{}
+			continue
+		}
+		if frame, ok := frameCache.frames[pc]; ok {
+			frames[i] = frame
+			hits++
+			continue
+		}
+		frames[i] = NewStackFrame(pc)
+		misses++
+	}
+	frameCache.RUnlock()
+
+	if !on {
+		return
+	}
+	frameCache.hits.Add(int64(hits))
+	if misses == 0 {
+		return
+	}
+	frameCache.misses.Add(int64(misses))
+
+	frameCache.Lock()
+	defer frameCache.Unlock()
+	for i, pc := range stack {
+		if pc == 0 || frameCache.size == 0 {
+			continue
+		}
+		if _, ok := frameCache.frames[pc]; ok {
+			continue
+		}
+		if len(frameCache.frames) >= frameCache.size {
+			frameCache.frames = make(map[uintptr]StackFrame, frameCache.size)
+		}
+		frameCache.frames[pc] = frames[i]
+	}
+}
@@ -0,0 +1,51 @@
+package goerr_test
+
+import (
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestFrameCache(t *testing.T) {
+	goerr.SetFrameCacheSize(8)
+	defer goerr.SetFrameCacheSize(goerr.DefaultFrameCacheSize)
+
+	var errs []error
+	for i := 0; i < 3; i++ {
+		errs = append(errs, goerr.New(nil, "failed"))
+	}
+
+	s := goerr.FrameCacheStats()
+	if s.Hits == 0 || s.Misses == 0 {
+		t.Errorf("expected hits and misses, Got: %+v", s)
+	}
+	if s.Entries == 0 || s.Entries > 8 {
+		t.Errorf("entries. Want: between 1 and 8, Got: %d", s.Entries)
+	}
+
+	want := errs[0].(*goerr.Error).StackFrames()
+	got := errs[2].(*goerr.Error).StackFrames()
+	if len(got) != len(want) {
+		t.Fatalf("frames. Want: %d, Got: %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("frame %d. Want: %+v, Got: %+v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestFrameCacheOff(t *testing.T) {
+	goerr.SetFrameCacheSize(0)
+	defer goerr.SetFrameCacheSize(goerr.DefaultFrameCacheSize)
+
+	err := goerr.New(nil, "failed")
+	goerr.New(nil, "failed")
+
+	if want, got := (goerr.FrameCacheStatistics{}), goerr.FrameCacheStats(); got != want {
+		t.Errorf("Want: %+v, Got: %+v", want, got)
+	}
+	if e := err.(*goerr.Error); e.Line() == 0 {
+		t.Errorf("expected the frames to be resolved without the cache")
+	}
+}
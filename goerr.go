@@ -3,25 +3,110 @@ package goerr
 import (
 	"bufio"
 	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"os"
+	"reflect"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 var MaxStackDepth = 50
 
-type errorEx struct {
-	err     error
-	message string
-	stack   []uintptr
-	frames  []StackFrame
-	code    int
+// MaxStackBytes caps the length of the output of Stack, so that a
+// pathologically long chain or a huge message does not produce a log line
+// too large for log shippers. Once the limit is reached, the line being
+// written is cut with a " ... truncated" marker and the remaining layers are
+// replaced by a marker like "... 12 more frames truncated", so the output
+// exceeds the limit by these markers and the build metadata at most. The number of layers is also
+// bounded by MaxChainDepth. A limit of 0 disables the cap.
+var MaxStackBytes = 64 << 10
+
+// Error is the error type returned by New. It holds the message, the optional
+// code and the call stack of one layer, along with the error it wraps.
+//
+// Most code should use the package level helpers like Stack and Code, which
+// work on any error. Error is exported for tooling that needs to inspect the
+// layers of a chain directly.
+type Error struct {
+	err       error
+	message   string
+	stack     []uintptr
+	frames    []StackFrame
+	code      int
+	codeStr   string
+	codes     map[Domain]int
+	severity  SeverityLevel
+	kind      Kind
+	fields    map[string]any
+	weight    float64
+	weighted  bool
+	hidden    bool
+	masks     bool
+	msgKey    string
+	msgArgs   []any
+	created   time.Time
+	deadline  time.Time
+	goroutine uint64
+	carried   bool
+	trace     bool
+	factory   *Factory
+	details   any
+	exitCode  int
+	retry     time.Duration
+	public    string
+	warnings  []error
+	def       *Definition
+	prev      [sha256.Size]byte
+	audited   bool
 }
 
+// New returns nested wrapped in a new layer recording the call stack. nested
+// may be nil. The message arguments are, in order, an optional int code and
+// a format with its arguments, like fmt.Sprintf; without a format, the
+// message is the one of nested. Options, like Msg and Coded, set the same
+// and more with types, and can be mixed with the positional arguments:
+//
+//	goerr.New(err, http.StatusNotFound, "order %s not found", id)
+//	goerr.New(err, goerr.Msg("order %s not found", id), goerr.Coded(http.StatusNotFound))
 func New(nested error, message ...any) error {
+	e := newError(nested, message, 1)
+	notify(e)
+	return e
+}
+
+// NewSkip is like New, but attributes the layer to a caller skip frames
+// above the caller of NewSkip, so that generated helpers wrapping errors can
+// attribute them to the call site in user code. NewSkip(0, ...) is the same
+// as New.
+//
+//	// generated code
+//	func wrapQueryErr(err error, query string) error {
+//		return goerr.NewSkip(1, err, "query %s failed", query)
+//	}
+func NewSkip(skip int, nested error, message ...any) error {
+	if skip < 0 {
+		skip = 0
+	}
+	e := newError(nested, message, skip+1)
+	notify(e)
+	return e
+}
+
+// newError creates a new layer wrapping nested from the message arguments of
+// New, capturing the call stack skipping skip frames above the caller of
+// newError. Hooks are not notified, so the caller can finish the layer first.
+func newError(nested error, message []any, skip int) *Error {
 	msg := "error"
 	code := 0
+	message, opts := splitOptions(message)
+	strict := strictMode.Load()
+	if strict {
+		checkArgs(message)
+	}
 
 	if nested != nil {
 		msg = nested.Error()
@@ -37,38 +122,141 @@ func New(nested error, message ...any) error {
 	if len(message) > 1 {
 		if c, ok := message[0].(int); ok {
 			code = c
+			if strict {
+				checkFormat(message[1].(string), message[2:])
+			}
 			msg = fmt.Sprintf(message[1].(string), message[2:]...)
 		} else {
+			if strict {
+				checkFormat(message[0].(string), message[1:])
+			}
 			msg = fmt.Sprintf(message[0].(string), message[1:]...)
 		}
 	}
 
+	stack, frames := capture(skip + 1)
+
+	e := &Error{
+		err:       nested,
+		message:   msg,
+		stack:     stack,
+		frames:    frames,
+		code:      code,
+		created:   now(),
+		goroutine: goroutineID(),
+	}
+	for _, o := range opts {
+		o.(Option)(e)
+	}
+	if strict {
+		checkCode(e.code)
+		// a format given positionally or with Msg describes the layer
+		described := len(message) > 1 || len(message) == 1 && code == 0 || e.message != msg
+		if nested == nil && (!described || strings.TrimSpace(e.message) == "") {
+			misuse("new error without a cause nor a message")
+		}
+	}
+	seal(e)
+	return e
+}
+
+// capture records the call stack starting at the function invoking capture,
+// skipping skip frames, or the stack of the configured StackCapturer. If the
+// capture is turned off, the stack is empty and there is one zero frame.
+func capture(skip int) ([]uintptr, []StackFrame) {
+	if stacksDisabled.Load() {
+		return nil, make([]StackFrame, 1)
+	}
+	if c := capturer.Load(); c != nil {
+		frames := (*c).Capture(skip + 3)
+		stack := make([]uintptr, len(frames))
+		if len(frames) == 0 {
+			frames = make([]StackFrame, 1)
+		}
+		return stack, frames
+	}
+
 	stack := make([]uintptr, MaxStackDepth)
-	length := runtime.Callers(2, stack[:])
+	length := runtime.Callers(skip+2, stack[:])
 
 	frames := make([]StackFrame, len(stack))
+	resolveFrames(stack, frames)
 
-	for i, pc := range stack {
-		frames[i] = NewStackFrame(pc)
+	return stack[:length], frames
+}
+
+// layer returns a copy of the outermost goerr layer of err which can be
+// enriched without mutating err. An error that is not a goerr is wrapped in a
+// new layer attributed to the caller of the exported function using layer.
+func layer(err error) *Error {
+	if e, ok := err.(*Error); ok {
+		c := *e
+		return &c
 	}
 
-	return &errorEx{
-		err:     nested,
-		message: msg,
-		stack:   stack[:length],
-		frames:  frames,
-		code:    code,
+	stack, frames := capture(2)
+	e := &Error{
+		err:       err,
+		message:   err.Error(),
+		stack:     stack,
+		frames:    frames,
+		created:   now(),
+		goroutine: goroutineID(),
 	}
+	seal(e)
+	notify(e)
+	return e
 }
 
-func (e *errorEx) Error() string {
+func (e *Error) Error() string {
 	return e.message
 }
 
-func (e *errorEx) Unwrap() error {
+func (e *Error) Unwrap() error {
 	return e.err
 }
 
+// Message returns the message of this layer.
+func (e *Error) Message() string {
+	return e.message
+}
+
+// Code returns the code set on this layer, or 0. Use the package level Code
+// function to get the code of the whole chain.
+func (e *Error) Code() int {
+	return e.code
+}
+
+// File returns the path of the file in which this layer was created.
+func (e *Error) File() string {
+	return e.frames[0].File
+}
+
+// Line returns the line number at which this layer was created.
+func (e *Error) Line() int {
+	return e.frames[0].LineNumber
+}
+
+// Func returns the import path qualified name of the function in which this
+// layer was created.
+func (e *Error) Func() string {
+	fn := e.frames[0].Func()
+	if fn == nil {
+		// a layer decoded by UnmarshalBinary or captured by a StackCapturer
+		// has no program counter
+		if e.frames[0].Name == "" {
+			return ""
+		}
+		return e.frames[0].Package + "." + e.frames[0].Name
+	}
+	return fn.Name()
+}
+
+// StackFrames returns the call stack captured when this layer was created.
+func (e *Error) StackFrames() []StackFrame {
+	return e.frames[:len(e.stack)]
+}
+
 // A StackFrame contains all necessary information about to generate a line
 // in a callstack.
 type StackFrame struct {
@@ -82,6 +270,12 @@ type StackFrame struct {
 	Package string
 	// The underlying ProgramCounter
 	ProgramCounter uintptr
+	// Offset is the distance of the ProgramCounter from a function of goerr
+	// and Module the hash of the build information of the binary, set only
+	// if the function of the frame could not be resolved. Symbolize resolves
+	// such frames offline with a copy of the binary.
+	Offset int64
+	Module string
 }
 
 // NewStackFrame popoulates a stack frame object from the program counter.
@@ -89,6 +283,9 @@ func NewStackFrame(pc uintptr) (frame StackFrame) {
 
 	frame = StackFrame{ProgramCounter: pc}
 	if frame.Func() == nil {
+		if pc != 0 {
+			frame.Offset, frame.Module = int64(pc-anchorPC), module()
+		}
 		return
 	}
 	frame.Package, frame.Name = packageAndName(frame.Func())
@@ -100,12 +297,16 @@ func NewStackFrame(pc uintptr) (frame StackFrame) {
 
 }
 
-// Func returns the function that contained this frame.
+// Func returns the function that contained this frame. If the call was
+// inlined by the compiler, it is the inlined function, the same as Name.
+// It returns nil for a frame without a program counter.
 func (frame *StackFrame) Func() *runtime.Func {
 	if frame.ProgramCounter == 0 {
 		return nil
 	}
-	return runtime.FuncForPC(frame.ProgramCounter)
+	// pc -1 for the same reason as in NewStackFrame, it also makes sure that
+	// calls inlined by the compiler resolve to the inlined function
+	return runtime.FuncForPC(frame.ProgramCounter - 1)
 }
 
 // String returns the stackframe formatted in the same way as go does
@@ -157,9 +358,6 @@ func (frame *StackFrame) sourceLine() (string, error) {
 }
 
 func packageAndName(fn *runtime.Func) (string, string) {
-	name := fn.Name()
-	pkg := ""
-
 	// The name includes the path name to the package, which is unnecessary
 	// since the file name is already included.  Plus, it has center dots.
 	// That is, we see
@@ -168,53 +366,120 @@ func packageAndName(fn *runtime.Func) (string, string) {
 	//  *T.ptrmethod
 	// Since the package path might contains dots (e.g. code.google.com/...),
 	// we first remove the path prefix if there is one.
-	if lastslash := strings.LastIndex(name, "/"); lastslash >= 0 {
-		pkg += name[:lastslash] + "/"
-		name = name[lastslash+1:]
-	}
-	if period := strings.Index(name, "."); period >= 0 {
-		pkg += name[:period]
-		name = name[period+1:]
-	}
+	pkg, name := splitFuncName(fn.Name())
 
 	name = strings.Replace(name, "·", ".", -1)
 	return pkg, name
 }
 
+// splitFuncName splits an import path qualified function name into the
+// package and the name within the package. The package ends at the first
+// period after the last slash: the toolchain escapes the periods of the last
+// path element as %2e, e.g. gopkg.in/yaml%2ev3.(*Decoder).Decode, which the
+// returned package has unescaped.
+func splitFuncName(name string) (pkg string, fn string) {
+	slash := strings.LastIndex(name, "/") + 1
+	period := strings.Index(name[slash:], ".")
+	if period < 0 {
+		return name[:slash], name[slash:]
+	}
+	return strings.ReplaceAll(name[:slash+period], "%2e", "."), name[slash+period+1:]
+}
+
 func ListStacks(err error) []string {
 	var result []string
-	e, ok := err.(*errorEx)
-	if !ok {
-		result = append(result, err.Error())
-		return result
-	}
-	packageParts := strings.Split(e.frames[0].Func().Name(), "/")
-	funcName := packageParts[len(packageParts)-1]
-	str := fmt.Sprintf("%s [%s:%d (%s)]", e.message, e.frames[0].File, e.frames[0].LineNumber, funcName)
+	r := walkTree(err, func(depth int, label string, err error) bool {
+		var b strings.Builder
+		if e, ok := err.(*Error); ok {
+			b.Grow(len(label) + e.lineSize())
+		}
+		b.WriteString(label)
+		writeEntry(&b, depth, err)
+		result = append(result, b.String())
+		return true
+	})
+	if r != walkDone {
+		result = append(result, r.marker())
+	}
+	return result
+}
+
+// lineSize estimates the length of the stack line of e, so that builders can
+// be sized up front.
+func (e *Error) lineSize() int {
+	size := len(e.message) + len(e.frames[0].File) + len(e.frames[0].Name) + len(e.frames[0].Package) + 16*len(e.fields) + 32
+	if !e.deadline.IsZero() {
+		size += 56
+	}
+	return size
+}
+
+func (e *Error) writeLine(b *strings.Builder) {
+	var num [20]byte
+
+	if e.traceOnly() {
+		e.writeTrace(b)
+		return
+	}
+
+	b.WriteString(redactMessage(e.factory, e.message))
 	if e.code != 0 {
-		str = fmt.Sprintf("%s (%d) [%s:%d (%s)]", e.message, e.code, e.frames[0].File, e.frames[0].LineNumber, funcName)
+		b.WriteString(" (")
+		b.Write(strconv.AppendInt(num[:0], int64(e.code), 10))
+		b.WriteByte(')')
+	}
+	if e.severity != 0 {
+		b.WriteString(" {")
+		b.WriteString(e.severity.String())
+		b.WriteByte('}')
+	}
+	if len(e.fields) > 0 {
+		e.writeFields(b)
+	}
+	if e.goroutine != 0 {
+		b.WriteString(" <goroutine ")
+		b.Write(strconv.AppendUint(num[:0], e.goroutine, 10))
+		b.WriteByte('>')
 	}
-	result = append(result, str)
-	if e.err == nil {
-		return result
+	if e.deadlineExceeded() {
+		e.writeDeadline(b)
 	}
 
-	return append(result, ListStacks(e.err)...)
+	if e.frames[0].File != "" {
+		writeFrame(b, e.factory, &e.frames[0], e.Func())
+	} else if e.frames[0].Offset != 0 {
+		writeOffset(b, &e.frames[0])
+	}
+}
+
+// writeFrame writes the location part of a stack line, the path and line of
+// frame and the name of its function fn, with the settings of f.
+func writeFrame(b *strings.Builder, f *Factory, frame *StackFrame, fn string) {
+	var num [20]byte
+
+	b.WriteString(" [")
+	b.WriteString(formatPath(f, frame))
+	b.WriteByte(':')
+	b.Write(strconv.AppendInt(num[:0], int64(frame.LineNumber), 10))
+	b.WriteString(" (")
+	b.WriteString(formatFuncName(f, fn))
+	b.WriteString(")]")
 }
 
 func ListErrors(err error) []string {
 	var result []string
-	e, ok := err.(*errorEx)
-	if !ok {
-		result = append(result, err.Error())
-		return result
-	}
-	result = append(result, e.message)
-	if e.err == nil {
-		return result
+	r := walk(err, func(_ int, err error) bool {
+		if e, ok := err.(*Error); ok {
+			result = append(result, redactMessage(e.factory, e.message))
+		} else {
+			result = append(result, redactMessage(nil, err.Error()))
+		}
+		return true
+	})
+	if r != walkDone {
+		result = append(result, r.marker())
 	}
-
-	return append(result, ListErrors(e.err)...)
+	return result
 }
 
 func Stack(err error) string {
@@ -222,38 +487,216 @@ func Stack(err error) string {
 		return ""
 	}
 
-	stacks := ListStacks(err)
-	if len(stacks) == 0 {
-		return ""
+	var b strings.Builder
+	b.Grow(stackSize(err))
+	writeStack(&b, err, debugMode.Load(), nil)
+	return b.String()
+}
+
+// stackSize estimates the length of the rendered stack of err, up to about
+// MaxStackBytes.
+func stackSize(err error) int {
+	size := 0
+	r := walkTree(err, func(depth int, label string, err error) bool {
+		size += depth + 1 + len(label)
+		if e, ok := err.(*Error); ok {
+			size += e.lineSize()
+		} else {
+			size += len(stackMessage(err))
+		}
+		return MaxStackBytes <= 0 || size < MaxStackBytes
+	})
+	if bi := buildMeta.Load(); bi != nil {
+		size += len(bi.line) + 1
+	}
+	return size + len(r.marker()) + 64
+}
+
+// writeStack writes one line per layer of err, each on a new line and
+// indented by its depth, including the layers in the branches of NewMulti
+// and Batch layers, see walkTree. A single layer is written without a line break. If
+// verbose is set, the time elapsed since the inner layer and the source code
+// around every layer are written as well. Layers carried across goroutines
+// are followed by their whole call stack. Consecutive layers repeating each
+// other are collapsed into one line ending in the number of layers, like
+// " x12". Once MaxStackBytes are written, the line being written is cut
+// with a " ... truncated" marker and the remaining layers are counted in a
+// marker instead. The build metadata, if set, ends the stack on a line of
+// its own, see SetBuildInfo. If drain is not nil, the contents of b are
+// passed to it and b is reset whenever streamChunk bytes are buffered.
+func writeStack(b *strings.Builder, err error, verbose bool, drain func(s string)) {
+	_, batch := err.(*Batch)
+	multiline := batch || next(err) != nil
+
+	// the trailing parts of the line of a goerr layer are written once the
+	// layers repeating it are counted
+	var pending, tail *Error
+	repeats, depth, indent := 0, 0, -1
+	start, skipped, drained := b.Len(), 0, 0
+
+	// shifts holds, per depth, the number of collapsed layers above the
+	// layer at that depth in its branch, by which its line is indented less
+	var buf [16]int
+	shifts := buf[:1]
+
+	// clip cuts the line being written at MaxStackBytes, as a single line,
+	// like one with a huge message or a carried stack, can exceed the limit
+	// on its own
+	clip := func() {
+		if MaxStackBytes <= 0 || drained+b.Len()-start <= MaxStackBytes {
+			return
+		}
+		out := b.String()
+		cut := start + MaxStackBytes - drained
+		for cut > start && !utf8.RuneStart(out[cut]) {
+			cut--
+		}
+		b.Reset()
+		b.WriteString(out[:cut])
+		b.WriteString(" ... truncated")
+		pending = nil
 	}
-	if len(stacks) == 1 {
-		return stacks[0]
+	flush := func() {
+		if pending == nil {
+			return
+		}
+		if repeats > 1 {
+			b.WriteString(" x")
+			b.WriteString(strconv.Itoa(repeats))
+		}
+		if verbose {
+			writeElapsed(b, pending, tail)
+			writeSource(b, pending, indent+1)
+		}
+		if pending.carried {
+			writeCarried(b, pending, indent+1)
+		}
+		writeWarnings(b, pending, indent+1)
+		pending = nil
+		clip()
 	}
 
-	var stack string
-	for i, line := range stacks {
-		stack += "\n"
-		for j := 0; j < i; j++ {
-			stack += "\t"
+	r := walkTree(err, func(d int, label string, err error) bool {
+		shift := shifts[d]
+		shifts = append(shifts[:d+1], shift)
+
+		// only the layers following each other in a branch are collapsed
+		e, ok := err.(*Error)
+		if ok && pending != nil && label == "" && d == depth+1 && pending.repeatedBy(e) {
+			repeats++
+			tail, depth = e, d
+			shifts[d+1]++
+			return true
 		}
-		stack += line
+		flush()
+		if drain != nil && b.Len() >= streamChunk {
+			drained += b.Len() - start
+			start = 0
+			drain(b.String())
+			b.Reset()
+		}
+		if MaxStackBytes > 0 && drained+b.Len()-start >= MaxStackBytes {
+			skipped++
+			return true
+		}
+
+		depth, indent = d, d-shift
+		if multiline {
+			writeIndent(b, indent)
+		}
+		b.WriteString(label)
+		writeEntry(b, indent, err)
+		if ok {
+			pending, tail, repeats = e, e, 1
+		}
+		clip()
+		return true
+	})
+	flush()
+	if skipped > 0 {
+		writeIndent(b, indent+1)
+		b.WriteString("... ")
+		b.WriteString(strconv.Itoa(skipped))
+		b.WriteString(" more frames truncated")
+	}
+	if r != walkDone {
+		writeIndent(b, indent+1)
+		b.WriteString(r.marker())
 	}
-	return stack
+	writeBuild(b)
 }
 
-func Code(err error) int {
-	if err == nil {
-		return 0
+// repeatedBy reports whether o repeats e, as it happens when errors are
+// wrapped in a retry loop or a recursion: it has the same message, code,
+// kind, severity, fields and details and was created at the same place, so
+// no data is lost when Stack collapses repeated layers into the line of the
+// first one.
+func (e *Error) repeatedBy(o *Error) bool {
+	return e.message == o.message && e.code == o.code && !e.carried && !o.carried && len(o.warnings) == 0 &&
+		e.frames[0].ProgramCounter == o.frames[0].ProgramCounter &&
+		e.frames[0].File == o.frames[0].File && e.frames[0].LineNumber == o.frames[0].LineNumber &&
+		e.codeStr == o.codeStr && e.kind == o.kind && e.severity == o.severity && e.goroutine == o.goroutine &&
+		e.hidden == o.hidden && e.masks == o.masks && e.public == o.public &&
+		reflect.DeepEqual(e.fields, o.fields) && reflect.DeepEqual(e.codes, o.codes) && reflect.DeepEqual(e.details, o.details)
+}
+
+// writeIndent starts a new line indented by depth tabs.
+func writeIndent(b *strings.Builder, depth int) {
+	b.WriteByte('\n')
+	for j := 0; j < depth; j++ {
+		b.WriteByte('\t')
 	}
+}
 
-	e, ok := err.(*errorEx)
-	if !ok {
-		return 0
+// writeEntry writes the stack line of err, which is a layer at the given
+// depth, using the FrameFormatter if one is set.
+func writeEntry(b *strings.Builder, depth int, err error) {
+	e, _ := err.(*Error)
+	if f := e.formatter(); f != nil {
+		b.WriteString(f(newFrame(depth, err)))
+		return
 	}
+	if e != nil {
+		e.writeLine(b)
+		return
+	}
+	b.WriteString(redactMessage(nil, stackMessage(err)))
+	if frame, ok := foreignFrame(err); ok {
+		writeFrame(b, nil, &frame, frame.Func().Name())
+	}
+}
 
-	if e.code != 0 {
-		return e.code
+// Code returns the code last given in the call chain of err, where a layer
+// with a predefined Kind but without a code counts as having the code of the
+// kind. If no layer has a code and the chain contains errors joined with
+// errors.Join (or any error with an Unwrap() []error method), the first
+// non-zero code of the joined errors, in order, is returned, except for a
+// Batch which has an aggregate code. Otherwise the
+// code inferred from the errors wrapped by err is returned, see InferCode.
+func Code(err error) int {
+	return code(err, MaxChainDepth)
+}
+
+// code implements Code, descending into at most budget joined errors.
+func code(err error, budget int) int {
+	e := find(err, func(e *Error) bool { return e.code != 0 || e.kind.Code() != 0 })
+	if e != nil {
+		if e.code != 0 {
+			return e.code
+		}
+		return e.kind.Code()
 	}
 
-	return Code(e.err)
+	if b := batchOf(err); b != nil {
+		return b.Code()
+	}
+	for _, branch := range branches(err) {
+		if budget--; budget <= 0 {
+			break
+		}
+		if c := code(branch, budget); c != 0 {
+			return c
+		}
+	}
+	return inferCode(err)
 }
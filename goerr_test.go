@@ -1,11 +1,14 @@
 package goerr_test
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/angel-one/goerr"
 	"github.com/angel-one/goerr/samplesrc"
@@ -230,3 +233,225 @@ func Test_Unwrap(t *testing.T) {
 		}
 	})
 }
+
+func TestStackAllocations(t *testing.T) {
+	err := samplesrc.Controller()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = goerr.Stack(err)
+	})
+	if allocs > 1 {
+		t.Errorf("Stack allocations. Want: <= 1; Got: %v", allocs)
+	}
+}
+
+func BenchmarkStack(b *testing.B) {
+	err := samplesrc.Controller()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = goerr.Stack(err)
+	}
+}
+
+func BenchmarkListStacks(b *testing.B) {
+	err := samplesrc.Controller()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = goerr.ListStacks(err)
+	}
+}
+
+func TestErrorAccessors(t *testing.T) {
+	err := goerr.New(samplesrc.Repository(), http.StatusConflict, "service failed")
+
+	var e *goerr.Error
+	if !errors.As(err, &e) {
+		t.Fatalf("expected err to be a *goerr.Error")
+	}
+
+	if got := e.Message(); got != "service failed" {
+		t.Errorf("Message. Want: %s, Got: %s", "service failed", got)
+	}
+	if got := e.Code(); got != http.StatusConflict {
+		t.Errorf("Code. Want: %d, Got: %d", http.StatusConflict, got)
+	}
+	if got := e.File(); !strings.HasSuffix(got, "/goerr/goerr_test.go") {
+		t.Errorf("File. Got: %s", got)
+	}
+	if got := e.Line(); got == 0 {
+		t.Errorf("Line. Got: %d", got)
+	}
+	if got := e.Func(); got != "github.com/angel-one/goerr_test.TestErrorAccessors" {
+		t.Errorf("Func. Got: %s", got)
+	}
+	if got := e.StackFrames(); len(got) == 0 || got[0].Name != "TestErrorAccessors" {
+		t.Errorf("StackFrames. Got: %v", got)
+	}
+
+	var inner *goerr.Error
+	if !errors.As(e.Unwrap(), &inner) || inner.Message() != "error from database" {
+		t.Errorf("Unwrap do not return the nested goerr")
+	}
+	if got := inner.Line(); got != 27 {
+		t.Errorf("Line. Want: %d, Got: %d", 27, got)
+	}
+}
+
+// newInlined is small enough to be inlined into its callers.
+func newInlined() error {
+	return goerr.New(nil, "inlined")
+}
+
+func TestStackFrameFunc(t *testing.T) {
+	e := newInlined().(*goerr.Error)
+	for _, frame := range e.StackFrames() {
+		if f := frame.Func(); f == nil || f.Name() != frame.Package+"."+frame.Name {
+			t.Errorf("Want: %s.%s, Got: %v", frame.Package, frame.Name, f)
+		}
+	}
+	if got := e.StackFrames()[0].Name; got != "newInlined" {
+		t.Errorf("Want: newInlined, Got: %s", got)
+	}
+	if (&goerr.StackFrame{}).Func() != nil {
+		t.Errorf("Want: nil for a frame without a program counter")
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	err := goerr.WithField(goerr.New(samplesrc.Service(), http.StatusConflict, "controller failed"), "order_id", 42)
+
+	b, jerr := json.Marshal(err)
+	if jerr != nil {
+		t.Fatalf("marshal failed: %v", jerr)
+	}
+	t.Log(string(b))
+
+	pattern := `^\{"message":"controller failed","code":409,"layers":\[` +
+		`\{"message":"controller failed","code":409,"fields":\{"order_id":42\}\},` +
+		`\{"message":"service failed"\},` +
+		`\{"message":"error from database"\}\]\}$`
+	if match, _ := regexp.MatchString(pattern, string(b)); !match {
+		t.Errorf("json is not matching the expectation")
+	}
+}
+
+func TestMaxStackBytes(t *testing.T) {
+	defer func(n int) { goerr.MaxStackBytes = n }(goerr.MaxStackBytes)
+	goerr.MaxStackBytes = 300
+
+	err := goerr.New(nil, "root")
+	for i := 0; i < 20; i++ {
+		err = goerr.New(err, "retry %d failed", i)
+	}
+
+	got := goerr.Stack(err)
+	if len(got) > goerr.MaxStackBytes+200 {
+		t.Errorf("expected the stack to be capped, Got %d bytes: %s", len(got), got)
+	}
+
+	marker := regexp.MustCompile(`\n\t+\.\.\. (\d+) more frames truncated$`).FindStringSubmatch(got)
+	if marker == nil {
+		t.Fatalf("expected a truncation marker, Got: %s", got)
+	}
+	if lines := strings.Count(got, "\n") - 1; marker[1] != strconv.Itoa(21-lines) {
+		t.Errorf("Want: %d more frames, Got: %s", 21-lines, marker[1])
+	}
+
+	goerr.MaxStackBytes = 0
+	if got := goerr.Stack(err); strings.Contains(got, "truncated") || strings.Count(got, "\n") != 21 {
+		t.Errorf("expected the whole stack without a cap, Got: %s", got)
+	}
+}
+
+func TestMaxStackBytesLongLine(t *testing.T) {
+	defer func(n int) { goerr.MaxStackBytes = n }(goerr.MaxStackBytes)
+	goerr.MaxStackBytes = 300
+
+	err := goerr.New(goerr.New(nil, "root"), "rejected "+strings.Repeat("é", 500))
+	got := goerr.Stack(err)
+	if len(got) > goerr.MaxStackBytes+len(" ... truncated")+64 {
+		t.Errorf("expected the line to be cut, Got %d bytes: %s", len(got), got)
+	}
+	if !strings.Contains(got, "é ... truncated") || !strings.HasSuffix(got, "... 1 more frames truncated") || !utf8.ValidString(got) {
+		t.Errorf("expected truncation markers, Got: %s", got)
+	}
+
+	var b strings.Builder
+	goerr.WriteStack(&b, err)
+	if b.String() != got {
+		t.Errorf("Want: %s\nGot: %s", got, b.String())
+	}
+}
+
+func retry(n int) error {
+	if n == 0 {
+		return samplesrc.Repository()
+	}
+	return goerr.New(retry(n-1), "retry failed")
+}
+
+func TestStackCollapsesRepeats(t *testing.T) {
+	err := goerr.New(retry(12), "handler failed")
+
+	got := goerr.Stack(err)
+	t.Log(got)
+
+	lines := strings.Split(got, "\n")[1:]
+	wants := []string{
+		`^handler failed \[.*goerr_test.go:\d+ \(goerr_test.TestStackCollapsesRepeats\)\]$`,
+		`^\tretry failed \[.*goerr_test.go:\d+ \(goerr_test.retry\)\] x12$`,
+		`^\t\terror from database \[.*samplesrc/samples.go:27 \(samplesrc.Repository\)\]$`,
+	}
+	if len(lines) != len(wants) {
+		t.Fatalf("Nr. of lines. Want: %d, Got: %d", len(wants), len(lines))
+	}
+	for i, want := range wants {
+		if !regexp.MustCompile(want).MatchString(lines[i]) {
+			t.Errorf("line %d. Want: %s, Got: %s", i, want, lines[i])
+		}
+	}
+
+	if got := len(goerr.ListStacks(err)); got != 14 {
+		t.Errorf("expected ListStacks to keep every layer. Want: %d, Got: %d", 14, got)
+	}
+	if got := goerr.StackVerbose(err); !regexp.MustCompile(`\(goerr_test.retry\)\] x12 \+[^\n]*\n\t\t `).MatchString(got) {
+		t.Errorf("expected the source after the collapsed line, Got: %s", got)
+	}
+
+	// layers with different data are not collapsed
+	err = nil
+	for attempt := 1; attempt <= 3; attempt++ {
+		err = goerr.New(err, "retry failed", goerr.F("attempt", attempt))
+	}
+	got = goerr.Stack(err)
+	for attempt := 1; attempt <= 3; attempt++ {
+		if !strings.Contains(got, "attempt="+strconv.Itoa(attempt)) {
+			t.Errorf("expected attempt=%d in the stack, Got: %s", attempt, got)
+		}
+	}
+	if strings.Contains(got, " x3") {
+		t.Errorf("expected the layers not to be collapsed, Got: %s", got)
+	}
+}
+
+// wrapQueryErr is like a generated helper wrapping errors for its caller.
+func wrapQueryErr(err error, query string) error {
+	return goerr.NewSkip(1, err, http.StatusInternalServerError, "query %s failed", query)
+}
+
+func TestNewSkip(t *testing.T) {
+	err := wrapQueryErr(errors.New("timeout"), "orders")
+	e := err.(*goerr.Error)
+	if !strings.HasSuffix(e.Func(), "goerr_test.TestNewSkip") {
+		t.Errorf("Want: the caller of the helper, Got: %s", e.Func())
+	}
+	if err.Error() != "query orders failed" || e.Code() != http.StatusInternalServerError {
+		t.Errorf("Want: query orders failed (500), Got: %s (%d)", err.Error(), e.Code())
+	}
+
+	if e := goerr.NewSkip(0, nil, "failed").(*goerr.Error); !strings.HasSuffix(e.Func(), "goerr_test.TestNewSkip") {
+		t.Errorf("Want: the caller of NewSkip, Got: %s", e.Func())
+	}
+}
@@ -0,0 +1,85 @@
+// Package goerrchi integrates goerr with github.com/go-chi/chi routers. It
+// builds on the goerrhttp middleware, so that chi services map errors to
+// responses like the other integrations: the status is the code of the
+// error, and the body its problem details.
+//
+// The package does not import chi: handlers and middleware are plain
+// net/http types, and ErrResponse implements render.Renderer of
+// github.com/go-chi/render structurally. To capture chi route patterns, set
+// goerrhttp.Options.Route:
+//
+//	m := goerrhttp.New(goerrhttp.Options{
+//		Route: func(r *http.Request) string {
+//			return chi.RouteContext(r.Context()).RoutePattern()
+//		},
+//		OnError: logError,
+//	})
+//	r := chi.NewRouter()
+//	r.Use(goerrchi.Recoverer(m))
+//	r.Get("/orders/{id}", goerrchi.Handler(m, getOrder))
+package goerrchi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/goerrhttp"
+)
+
+// Handler adapts fn, which returns an error, to the http.HandlerFunc taken
+// by chi routes. An error returned by fn is handled by m, see
+// goerrhttp.Middleware.Respond.
+func Handler(m *goerrhttp.Middleware, fn goerrhttp.HandlerFunc) http.HandlerFunc {
+	return m.Handler(fn).ServeHTTP
+}
+
+// Recoverer returns a chi middleware that recovers from panics of the next
+// handlers and responds with a 500 error wrapping the panic value, handled
+// by m. Like chi's own Recoverer, it lets http.ErrAbortHandler through.
+func Recoverer(m *goerrhttp.Middleware) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				m.Respond(w, r, goerr.New(fmt.Errorf("panic: %v", v), http.StatusInternalServerError, "handler panicked"))
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ErrResponse is the problem details of an error as a response for
+// render.Render of github.com/go-chi/render. It implements render.Renderer
+// and marshals to an RFC 7807 document, see goerr.ToProblem.
+//
+//	if err != nil {
+//		render.Render(w, r, goerrchi.ErrRender(err))
+//		return
+//	}
+type ErrResponse struct {
+	goerr.Problem
+}
+
+// ErrRender returns the response for err.
+func ErrRender(err error) *ErrResponse {
+	return &ErrResponse{Problem: goerr.ToProblem(err)}
+}
+
+// Render sets the instance of the problem to the path of r if it is empty,
+// and writes the content type and the status of the response, before
+// render.Render writes the document.
+func (e *ErrResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	if e.Instance == "" {
+		e.Instance = r.URL.Path
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(e.Status)
+	return nil
+}
@@ -0,0 +1,93 @@
+package goerrchi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/goerrchi"
+	"github.com/angel-one/goerr/goerrhttp"
+)
+
+func TestHandler(t *testing.T) {
+	var logged error
+	m := goerrhttp.New(goerrhttp.Options{
+		Route:   func(r *http.Request) string { return "/orders/{id}" },
+		OnError: func(r *http.Request, err error) { logged = err },
+	})
+	h := goerrchi.Handler(m, func(w http.ResponseWriter, r *http.Request) error {
+		return goerr.New(nil, http.StatusNotFound, "order not found")
+	})
+
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodGet, "/orders/42", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status. Want: 404, Got: %d", w.Code)
+	}
+	if got := goerr.Fields(logged)[goerrhttp.RouteField]; got != "/orders/{id}" {
+		t.Errorf("route. Want: /orders/{id}, Got: %v", got)
+	}
+}
+
+func TestRecoverer(t *testing.T) {
+	var logged error
+	m := goerrhttp.New(goerrhttp.Options{OnError: func(r *http.Request, err error) { logged = err }})
+	h := goerrchi.Recoverer(m)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status. Want: 500, Got: %d", w.Code)
+	}
+	if want, got := "panic: boom", errors.Unwrap(logged).Error(); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+}
+
+func TestRecovererAbort(t *testing.T) {
+	m := goerrhttp.New(goerrhttp.Options{})
+	h := goerrchi.Recoverer(m)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("Want: %v, Got: %v", http.ErrAbortHandler, v)
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestErrRender(t *testing.T) {
+	resp := goerrchi.ErrRender(goerr.New(errors.New("sql: no rows"), http.StatusNotFound, "order not found"))
+
+	w := httptest.NewRecorder()
+	if err := resp.Render(w, httptest.NewRequest(http.MethodGet, "/orders/42", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status. Want: 404, Got: %d", w.Code)
+	}
+	if want, got := "application/problem+json", w.Header().Get("Content-Type"); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+
+	b, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["detail"] != "order not found" || got["instance"] != "/orders/42" || got["status"] != 404.0 {
+		t.Errorf("unexpected document, Got: %s", b)
+	}
+}
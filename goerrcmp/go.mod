@@ -0,0 +1,10 @@
+module github.com/angel-one/goerr/goerrcmp
+
+go 1.19
+
+require (
+	github.com/angel-one/goerr v0.0.0
+	github.com/google/go-cmp v0.6.0
+)
+
+replace github.com/angel-one/goerr => ../
@@ -0,0 +1,27 @@
+// Package goerrcmp lets github.com/google/go-cmp compare goerr errors by
+// their layers, so that cmp.Diff shows the layers that differ instead of
+// unexported fields and pointers.
+package goerrcmp
+
+import (
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/goerrtest"
+	"github.com/google/go-cmp/cmp"
+)
+
+// Transform returns an option making cmp compare goerr errors, also when
+// they are nested in other values, as their chains of goerrtest.Layer: the
+// message, code, kind, severity and fields of every layer, but not where the
+// layers were created.
+//
+//	if diff := cmp.Diff(want, err, goerrcmp.Transform()); diff != "" {
+//		t.Errorf("unexpected error (-want +got):\n%s", diff)
+//	}
+func Transform() cmp.Option {
+	return cmp.Transformer("goerr.Chain", func(e *goerr.Error) []goerrtest.Layer {
+		if e == nil {
+			return nil
+		}
+		return goerrtest.Chain(e)
+	})
+}
@@ -0,0 +1,43 @@
+package goerrcmp_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/goerrcmp"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTransform(t *testing.T) {
+	want := goerr.New(goerr.New(errors.New("no rows"), "query failed"), http.StatusNotFound, "order not found")
+	same := goerr.New(goerr.New(errors.New("no rows"), "query failed"), http.StatusNotFound, "order not found")
+
+	if diff := cmp.Diff(want, same, goerrcmp.Transform()); diff != "" {
+		t.Errorf("expected no diff for chains created at different lines, Got:\n%s", diff)
+	}
+
+	got := goerr.New(goerr.New(errors.New("no rows"), "query failed"), http.StatusInternalServerError, "order not found")
+	diff := cmp.Diff(want, got, goerrcmp.Transform())
+	if !strings.Contains(diff, "Code") || !strings.Contains(diff, "404") || !strings.Contains(diff, "500") {
+		t.Errorf("expected a diff of the codes, Got:\n%s", diff)
+	}
+	if strings.Contains(diff, "frames") || strings.Contains(diff, "0x") {
+		t.Errorf("expected no unexported fields or pointers, Got:\n%s", diff)
+	}
+}
+
+func TestTransformNested(t *testing.T) {
+	type result struct {
+		ID  int
+		Err error
+	}
+	want := result{ID: 1, Err: goerr.New(nil, "failed")}
+	got := result{ID: 1, Err: goerr.New(nil, "failed")}
+
+	if diff := cmp.Diff(want, got, goerrcmp.Transform()); diff != "" {
+		t.Errorf("Want: no diff, Got:\n%s", diff)
+	}
+}
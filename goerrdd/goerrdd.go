@@ -0,0 +1,69 @@
+// Package goerrdd reports goerr errors on DataDog APM spans. It sets the tags
+// read by DataDog Error Tracking from the goerr chain, so the stack shown
+// for a span is the one of the layers rather than the one of the code
+// finishing the span, and tags the fingerprint to group occurrences.
+//
+// The package does not depend on gopkg.in/DataDog/dd-trace-go.v1: spans of
+// dd-trace-go implement Span.
+//
+//	span, ctx := tracer.StartSpanFromContext(ctx, "orders.get")
+//	defer span.Finish()
+//	if err := get(ctx, id); err != nil {
+//		goerrdd.SetError(span, err)
+//		return err
+//	}
+package goerrdd
+
+import (
+	"fmt"
+
+	"github.com/angel-one/goerr"
+)
+
+// Tags set by SetError. ErrorTag, MessageTag, TypeTag and StackTag are the
+// tags of DataDog Error Tracking.
+const (
+	ErrorTag       = "error"
+	MessageTag     = "error.msg"
+	TypeTag        = "error.type"
+	StackTag       = "error.stack"
+	FingerprintTag = "error.fingerprint"
+	CodeTag        = "error.code"
+)
+
+// Span is the part of a dd-trace-go span used by SetError, implemented by
+// ddtrace.Span.
+type Span interface {
+	SetTag(key string, value interface{})
+}
+
+// SetError marks span as failed with err. The message is the one of err,
+// the type is the one of the root cause of err, or of the goerr layer if
+// there is none, and the stack is the rendered chain, see goerr.Stack. The
+// registered redactors apply to the message as they do to the stack. The
+// fingerprint and the code of err, if it has one, are tagged as well. It
+// does nothing if err is nil.
+func SetError(span Span, err error) {
+	if err == nil {
+		return
+	}
+
+	// true, unlike err, marks the span without dd-trace-go overwriting the
+	// other tags with the ones of its own stack
+	span.SetTag(ErrorTag, true)
+	span.SetTag(MessageTag, goerr.ListEntries(err)[0].Message)
+	span.SetTag(TypeTag, typeName(err))
+	span.SetTag(StackTag, goerr.Stack(err))
+	span.SetTag(FingerprintTag, goerr.Fingerprint(err))
+	if code := goerr.Code(err); code != 0 {
+		span.SetTag(CodeTag, code)
+	}
+}
+
+// typeName returns the type of the root cause of err, or of err itself.
+func typeName(err error) string {
+	if root := goerr.Root(err); root != nil {
+		return fmt.Sprintf("%T", root)
+	}
+	return fmt.Sprintf("%T", err)
+}
@@ -0,0 +1,72 @@
+package goerrdd_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/goerrdd"
+)
+
+type span map[string]interface{}
+
+func (s span) SetTag(key string, value interface{}) {
+	s[key] = value
+}
+
+func TestSetError(t *testing.T) {
+	err := goerr.New(errors.New("sql: no rows"), 404, "order not found")
+	s := span{}
+	goerrdd.SetError(s, err)
+
+	want := map[string]interface{}{
+		goerrdd.ErrorTag:       true,
+		goerrdd.MessageTag:     err.Error(),
+		goerrdd.TypeTag:        "*errors.errorString",
+		goerrdd.StackTag:       goerr.Stack(err),
+		goerrdd.FingerprintTag: goerr.Fingerprint(err),
+		goerrdd.CodeTag:        404,
+	}
+	for k, v := range want {
+		if s[k] != v {
+			t.Errorf("%s. Want: %v, Got: %v", k, v, s[k])
+		}
+	}
+}
+
+func TestSetErrorGoerrOnly(t *testing.T) {
+	s := span{}
+	goerrdd.SetError(s, goerr.New(nil, "failed"))
+
+	if want, got := "*goerr.Error", s[goerrdd.TypeTag]; got != want {
+		t.Errorf("Want: %s, Got: %v", want, got)
+	}
+	if _, ok := s[goerrdd.CodeTag]; ok {
+		t.Errorf("expected no code tag, Got: %v", s[goerrdd.CodeTag])
+	}
+}
+
+func TestSetErrorRedacted(t *testing.T) {
+	goerr.AddRedactor(goerr.RegexRedactor(regexp.MustCompile(`token=\w+`)))
+	t.Cleanup(func() { goerr.Configure(goerr.Options{}) })
+
+	s := span{}
+	goerrdd.SetError(s, goerr.New(nil, "auth failed token=s3cr3t"))
+
+	for _, tag := range []string{goerrdd.MessageTag, goerrdd.StackTag} {
+		if strings.Contains(s[tag].(string), "s3cr3t") {
+			t.Errorf("%s. expected the message to be redacted, Got: %v", tag, s[tag])
+		}
+	}
+}
+
+func TestSetErrorNil(t *testing.T) {
+	s := span{}
+	goerrdd.SetError(s, nil)
+
+	if len(s) != 0 {
+		t.Errorf("expected no tags, Got: %v", s)
+	}
+}
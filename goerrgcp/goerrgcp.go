@@ -0,0 +1,159 @@
+// Package goerrgcp renders goerr errors as structured log entries of Google
+// Cloud Logging. Written to stdout as single JSON lines, which the logging
+// agents of Cloud Run, GKE and App Engine pick up, the entries have the
+// severity and source location of the error, and a stack trace in the
+// format of Go panics, so that Error Reporting groups the errors natively.
+//
+//	if err != nil {
+//		goerrgcp.Write(os.Stdout, err, goerrgcp.Options{Service: "orders"})
+//	}
+package goerrgcp
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/angel-one/goerr"
+)
+
+// ReportedErrorEventType is the type of entries reported to Error
+// Reporting.
+const ReportedErrorEventType = "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent"
+
+// Options configure the entries.
+type Options struct {
+	// Service is the name of the service reporting the errors in Error
+	// Reporting. If it is empty, the entries have no service context.
+	Service string
+	// Version is the version of the service, by default the version set
+	// with goerr.SetBuildInfo.
+	Version string
+}
+
+// Entry is a structured log entry of Cloud Logging.
+type Entry struct {
+	Severity string `json:"severity"`
+	// Message is the message of the error.
+	Message string `json:"message"`
+	// StackTrace is the stack of the layer where the error originated, in
+	// the format of runtime.Stack.
+	StackTrace     string          `json:"stack_trace,omitempty"`
+	SourceLocation *SourceLocation `json:"logging.googleapis.com/sourceLocation,omitempty"`
+	ServiceContext *ServiceContext `json:"serviceContext,omitempty"`
+	Type           string          `json:"@type"`
+	Code           int             `json:"code,omitempty"`
+	Fingerprint    string          `json:"fingerprint"`
+	Fields         map[string]any  `json:"fields,omitempty"`
+}
+
+// SourceLocation is the location in the source code of a log entry.
+type SourceLocation struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Function string `json:"function"`
+}
+
+// ServiceContext identifies the service reporting an error.
+type ServiceContext struct {
+	Service string `json:"service"`
+	Version string `json:"version,omitempty"`
+}
+
+// NewEntry returns the entry of err. The severity is the one of err, the
+// source location and the stack trace are the ones of the innermost goerr
+// layer, where the error originated. The registered redactors apply to the
+// message, which heads the stack trace, and to the fields.
+func NewEntry(err error, opts Options) Entry {
+	entry := Entry{
+		Severity:    severity(goerr.Severity(err)),
+		Message:     goerr.ListEntries(err)[0].Message,
+		Type:        ReportedErrorEventType,
+		Code:        goerr.Code(err),
+		Fingerprint: goerr.Fingerprint(err),
+	}
+	if fields := goerr.Fields(err); len(fields) > 0 {
+		entry.Fields = fields
+	}
+	if opts.Service != "" {
+		version := opts.Version
+		if version == "" {
+			version, _ = goerr.BuildInfo()
+		}
+		entry.ServiceContext = &ServiceContext{Service: opts.Service, Version: version}
+	}
+
+	var origin *goerr.Error
+	goerr.Walk(err, func(l goerr.Layer) bool {
+		if e, ok := l.Err.(*goerr.Error); ok && len(e.StackFrames()) > 0 {
+			origin = e
+		}
+		return true
+	})
+	if origin == nil {
+		return entry
+	}
+
+	frames := origin.StackFrames()
+	entry.SourceLocation = &SourceLocation{
+		File:     frames[0].File,
+		Line:     frames[0].LineNumber,
+		Function: frames[0].Package + "." + frames[0].Name,
+	}
+	entry.StackTrace = stackTrace(entry.Message, frames)
+	return entry
+}
+
+// Write writes the entry of err to w as a single JSON line. It does nothing
+// if err is nil.
+func Write(w io.Writer, err error, opts Options) error {
+	if err == nil {
+		return nil
+	}
+
+	entry := NewEntry(err, opts)
+	b, merr := json.Marshal(entry)
+	if merr != nil {
+		// a field cannot be encoded
+		entry.Fields = nil
+		if b, merr = json.Marshal(entry); merr != nil {
+			return merr
+		}
+	}
+	_, werr := w.Write(append(b, '\n'))
+	return werr
+}
+
+// severity returns the Cloud Logging severity of s.
+func severity(s goerr.SeverityLevel) string {
+	switch s {
+	case goerr.SeverityWarning:
+		return "WARNING"
+	case goerr.SeverityCritical:
+		return "CRITICAL"
+	}
+	return "ERROR"
+}
+
+// stackTrace renders frames like runtime.Stack, preceded by message, which
+// Error Reporting parses as a Go stack trace.
+func stackTrace(message string, frames []goerr.StackFrame) string {
+	var b strings.Builder
+	b.WriteString(message)
+	b.WriteString("\n\ngoroutine 1 [running]:\n")
+	for _, f := range frames {
+		if f.Package == "runtime" && f.Name == "goexit" {
+			continue
+		}
+		b.WriteString(f.Package)
+		b.WriteByte('.')
+		b.WriteString(f.Name)
+		b.WriteString("()\n\t")
+		b.WriteString(f.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(f.LineNumber))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,131 @@
+package goerrgcp_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/goerrgcp"
+)
+
+func TestNewEntry(t *testing.T) {
+	inner := goerr.New(errors.New("sql: no rows"), 404, "order not found", goerr.F("order", 7))
+	err := goerr.WithSeverity(goerr.New(inner, "get order"), goerr.SeverityCritical)
+	entry := goerrgcp.NewEntry(err, goerrgcp.Options{Service: "orders", Version: "v1.2.0"})
+
+	if want, got := "CRITICAL", entry.Severity; got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+	if want, got := err.Error(), entry.Message; got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+	if want, got := goerrgcp.ReportedErrorEventType, entry.Type; got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+	if want, got := goerr.Fingerprint(err), entry.Fingerprint; got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+	if want, got := (goerrgcp.ServiceContext{Service: "orders", Version: "v1.2.0"}), entry.ServiceContext; got == nil || *got != want {
+		t.Errorf("Want: %v, Got: %v", want, got)
+	}
+	if entry.Fields["order"] != 7 {
+		t.Errorf("Want: 7, Got: %v", entry.Fields["order"])
+	}
+
+	// the location is the one of the innermost layer
+	frame := inner.(*goerr.Error).StackFrames()[0]
+	loc := entry.SourceLocation
+	if loc == nil {
+		t.Fatal("expected a source location")
+	}
+	if loc.File != frame.File || loc.Line != frame.LineNumber || loc.Function != "github.com/angel-one/goerr/goerrgcp_test.TestNewEntry" {
+		t.Errorf("Want: %s:%d (TestNewEntry), Got: %+v", frame.File, frame.LineNumber, *loc)
+	}
+
+	wantHead := err.Error() + "\n\ngoroutine 1 [running]:\ngithub.com/angel-one/goerr/goerrgcp_test.TestNewEntry()\n\t" + frame.File + ":"
+	if !strings.HasPrefix(entry.StackTrace, wantHead) {
+		t.Errorf("Want prefix: %q, Got: %q", wantHead, entry.StackTrace)
+	}
+	if strings.Contains(entry.StackTrace, "runtime.goexit") {
+		t.Errorf("expected no runtime.goexit, Got: %q", entry.StackTrace)
+	}
+}
+
+func TestNewEntrySeverity(t *testing.T) {
+	tests := map[goerr.SeverityLevel]string{
+		goerr.SeverityWarning:  "WARNING",
+		goerr.SeverityError:    "ERROR",
+		goerr.SeverityCritical: "CRITICAL",
+	}
+	for s, want := range tests {
+		if got := goerrgcp.NewEntry(goerr.WithSeverity(goerr.New(nil, "failed"), s), goerrgcp.Options{}).Severity; got != want {
+			t.Errorf("%s. Want: %s, Got: %s", s, want, got)
+		}
+	}
+}
+
+func TestNewEntryBuildVersion(t *testing.T) {
+	goerr.SetBuildInfo("v2.0.0", "abc123")
+	defer goerr.SetBuildInfo("", "")
+
+	entry := goerrgcp.NewEntry(goerr.New(nil, "failed"), goerrgcp.Options{Service: "orders"})
+	if entry.ServiceContext == nil || entry.ServiceContext.Version != "v2.0.0" {
+		t.Errorf("Want: v2.0.0, Got: %v", entry.ServiceContext)
+	}
+}
+
+func TestNewEntryForeign(t *testing.T) {
+	entry := goerrgcp.NewEntry(errors.New("failed"), goerrgcp.Options{})
+
+	if entry.SourceLocation != nil || entry.StackTrace != "" || entry.ServiceContext != nil {
+		t.Errorf("expected no location, stack or service, Got: %+v", entry)
+	}
+	if want, got := "ERROR", entry.Severity; got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	err := goerr.New(nil, 500, "failed", goerr.F("ch", make(chan int)))
+	if werr := goerrgcp.Write(&buf, err, goerrgcp.Options{}); werr != nil {
+		t.Fatal(werr)
+	}
+
+	if !strings.HasSuffix(buf.String(), "}\n") || strings.Count(buf.String(), "\n") != 1 {
+		t.Errorf("expected a single JSON line, Got: %q", buf.String())
+	}
+	var got map[string]any
+	if jerr := json.Unmarshal(buf.Bytes(), &got); jerr != nil {
+		t.Fatal(jerr)
+	}
+	for _, k := range []string{"severity", "message", "stack_trace", "logging.googleapis.com/sourceLocation", "@type", "code", "fingerprint"} {
+		if _, ok := got[k]; !ok {
+			t.Errorf("expected %s, Got: %v", k, got)
+		}
+	}
+	if _, ok := got["fields"]; ok {
+		t.Errorf("expected the fields that cannot be encoded to be dropped, Got: %v", got["fields"])
+	}
+}
+
+func TestNewEntryRedacted(t *testing.T) {
+	goerr.AddRedactor(goerr.RegexRedactor(regexp.MustCompile(`token=\w+`)))
+	t.Cleanup(func() { goerr.Configure(goerr.Options{}) })
+
+	entry := goerrgcp.NewEntry(goerr.New(nil, "auth failed token=s3cr3t"), goerrgcp.Options{})
+	if strings.Contains(entry.Message, "s3cr3t") || strings.Contains(entry.StackTrace, "s3cr3t") {
+		t.Errorf("expected the message to be redacted, Got: %q, %q", entry.Message, entry.StackTrace)
+	}
+}
+
+func TestWriteNil(t *testing.T) {
+	var buf bytes.Buffer
+	if err := goerrgcp.Write(&buf, nil, goerrgcp.Options{}); err != nil || buf.Len() != 0 {
+		t.Errorf("expected nothing written, Got: %q, %v", buf.String(), err)
+	}
+}
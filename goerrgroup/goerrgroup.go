@@ -0,0 +1,119 @@
+// Package goerrgroup provides a Group like golang.org/x/sync/errgroup whose
+// Wait returns the errors of all failed goroutines instead of only the first
+// one, each labeled with its task.
+package goerrgroup
+
+import (
+	"context"
+	"sync"
+
+	"github.com/angel-one/goerr"
+)
+
+// TaskField is the field set to the label of the task on its error.
+const TaskField = "task"
+
+// A Group is a collection of goroutines working on subtasks of a common
+// task. The zero value is ready to use, does not limit the number of
+// goroutines and does not cancel on error.
+type Group struct {
+	cancel func()
+
+	wg  sync.WaitGroup
+	sem chan struct{}
+
+	mu     sync.Mutex
+	tasks  int
+	failed bool
+	errs   goerr.Collector
+}
+
+// WithContext returns a new Group and a context derived from ctx, which is
+// canceled when a goroutine of the group first returns an error or when Wait
+// returns, whichever occurs first.
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// SetLimit limits the number of goroutines of the group running at once to
+// n. A negative n removes the limit. It must not be called while goroutines
+// of the group are running.
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Go calls f in a new goroutine, after waiting for a free slot if the
+// number of goroutines is limited.
+func (g *Group) Go(f func() error) {
+	g.GoTask("", f)
+}
+
+// GoTask is like Go, but sets the TaskField of the error returned by f to
+// label.
+//
+//	for _, id := range ids {
+//		id := id
+//		g.GoTask("order "+id, func() error {
+//			return sync(ctx, id)
+//		})
+//	}
+func (g *Group) GoTask(label string, f func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+
+	g.mu.Lock()
+	index := g.tasks
+	g.tasks++
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.done()
+		if err := f(); err != nil {
+			if label != "" {
+				err = goerr.WithField(err, TaskField, label)
+			}
+			g.fail(index, err)
+		}
+	}()
+}
+
+// done releases the slot of a finished goroutine.
+func (g *Group) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
+
+// fail records the error of the task with the given index and cancels the
+// context of the group on the first error.
+func (g *Group) fail(index int, err error) {
+	g.errs.AddAt(index, err)
+
+	g.mu.Lock()
+	first := !g.failed
+	g.failed = true
+	g.mu.Unlock()
+	if first && g.cancel != nil {
+		g.cancel()
+	}
+}
+
+// Wait waits for all goroutines of the group to return, and returns nil if
+// none of them failed. Otherwise it returns a *goerr.Batch of their errors,
+// ordered like the calls to Go, whose code is the aggregate code of the
+// errors and which goerr.Stack renders with the chain of every error.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+	return g.errs.Err()
+}
@@ -0,0 +1,94 @@
+package goerrgroup_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/goerrgroup"
+)
+
+func TestGroup(t *testing.T) {
+	var g goerrgroup.Group
+	g.GoTask("order 1", func() error { return nil })
+	g.GoTask("order 2", func() error { return goerr.New(nil, http.StatusNotFound, "order 2 not found") })
+	g.Go(func() error { return errors.New("db down") })
+
+	err := g.Wait()
+	var b *goerr.Batch
+	if !errors.As(err, &b) {
+		t.Fatalf("Want: *goerr.Batch, Got: %T", err)
+	}
+	items := b.Items()
+	if len(items) != 2 || items[0].Index != 1 || items[1].Index != 2 {
+		t.Fatalf("Want: tasks 1 and 2, Got: %v", items)
+	}
+	if got := goerr.Fields(items[0].Err)[goerrgroup.TaskField]; got != "order 2" {
+		t.Errorf("Want: order 2, Got: %v", got)
+	}
+	if _, ok := goerr.Fields(items[1].Err)[goerrgroup.TaskField]; ok {
+		t.Errorf("expected no task field without a label")
+	}
+	if goerr.Code(err) != http.StatusInternalServerError {
+		t.Errorf("code. Want: 500, Got: %d", goerr.Code(err))
+	}
+	if stack := goerr.Stack(err); !strings.Contains(stack, "task=order 2") || !strings.Contains(stack, "db down") {
+		t.Errorf("expected the chains of all tasks, Got: %s", stack)
+	}
+
+	var ok goerrgroup.Group
+	ok.Go(func() error { return nil })
+	if err := ok.Wait(); err != nil {
+		t.Errorf("Want: nil, Got: %v", err)
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	g, ctx := goerrgroup.WithContext(context.Background())
+	g.Go(func() error { return goerr.New(nil, "failed") })
+	g.Go(func() error {
+		select {
+		case <-ctx.Done():
+			return goerr.New(ctx.Err(), "canceled")
+		case <-time.After(5 * time.Second):
+			return nil
+		}
+	})
+
+	var b *goerr.Batch
+	if !errors.As(g.Wait(), &b) || b.Count() != 2 {
+		t.Errorf("expected both errors, the second one after cancellation")
+	}
+}
+
+func TestSetLimit(t *testing.T) {
+	var g goerrgroup.Group
+	g.SetLimit(2)
+
+	var running, max int32
+	for i := 0; i < 10; i++ {
+		g.Go(func() error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&max)
+				if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if max > 2 {
+		t.Errorf("Want: at most 2 goroutines, Got: %d", max)
+	}
+}
@@ -0,0 +1,211 @@
+// Package goerrhttp provides HTTP middleware for handlers returning errors.
+// The errors are enriched with fields describing the request, handed to a
+// callback for logging, and answered with their problem details.
+package goerrhttp
+
+import (
+	"net"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/angel-one/goerr"
+)
+
+// Fields set by Middleware.
+const (
+	MethodField    = "http_method"
+	PathField      = "http_path"
+	RouteField     = "http_route"
+	StatusField    = "http_status"
+	RequestIDField = "request_id"
+	RemoteIPField  = "remote_ip"
+)
+
+// DefaultRequestIDHeader is the header holding the request ID unless set in
+// Options.
+const DefaultRequestIDHeader = "X-Request-Id"
+
+// Options configure a Middleware.
+type Options struct {
+	// Allow lists the fields captured from requests. If it is empty, all
+	// fields are captured.
+	Allow []string
+	// Deny lists fields that are never captured, even if allowed.
+	Deny []string
+	// RequestIDHeader is the header holding the request ID, by default
+	// DefaultRequestIDHeader.
+	RequestIDHeader string
+	// TrustForwardedFor makes the remote IP the last address of the
+	// X-Forwarded-For header, if present, which is the one added by the
+	// proxy in front of the server; the addresses before it are set by the
+	// client and cannot be trusted. Only enable it behind a single proxy
+	// appending to the header.
+	TrustForwardedFor bool
+	// Route returns the route pattern matched by a request. By default it is
+	// the pattern matched by http.ServeMux, which is only reported from Go
+	// 1.23 on.
+	Route func(r *http.Request) string
+	// OnError is called with every error returned by a handler, after it was
+	// enriched, e.g. to log it.
+	OnError func(r *http.Request, err error)
+}
+
+// Middleware adapts handlers returning errors to http.Handler.
+type Middleware struct {
+	opts    Options
+	capture map[string]bool
+	routes  []route
+}
+
+// route holds the problem options of the requests matching a pattern.
+type route struct {
+	pattern string
+	opts    []goerr.ProblemOption
+}
+
+// New returns a middleware configured with opts.
+func New(opts Options) *Middleware {
+	if opts.RequestIDHeader == "" {
+		opts.RequestIDHeader = DefaultRequestIDHeader
+	}
+	if opts.Route == nil {
+		opts.Route = pattern
+	}
+
+	m := &Middleware{opts: opts, capture: map[string]bool{}}
+	fields := opts.Allow
+	if len(fields) == 0 {
+		fields = []string{MethodField, PathField, RouteField, StatusField, RequestIDField, RemoteIPField}
+	}
+	for _, f := range fields {
+		m.capture[f] = true
+	}
+	for _, f := range opts.Deny {
+		delete(m.capture, f)
+	}
+	return m
+}
+
+// A HandlerFunc is an HTTP handler returning an error.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Handler returns a handler calling fn. If fn returns an error, it is
+// enriched with the captured fields of the request and passed to
+// Options.OnError, and the response is the problem details of the error as
+// written by goerr.WriteProblem. The fields are not part of the response.
+//
+//	m := goerrhttp.New(goerrhttp.Options{
+//		OnError: func(r *http.Request, err error) {
+//			logger.WithError(err).Error("request failed")
+//		},
+//	})
+//	mux.Handle("GET /orders/{id}", m.Handler(func(w http.ResponseWriter, r *http.Request) error {
+//		...
+//	}))
+func (m *Middleware) Handler(fn HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := fn(w, r); err != nil {
+			m.Respond(w, r, err)
+		}
+	})
+}
+
+// Respond handles the error err of the request r like Handler does: it
+// passes err, enriched with the captured fields of r, to Options.OnError and
+// writes the problem details of err as the response. It lets other
+// integrations, like routers with their own handler types, respond
+// consistently. Unless err has an ID, it is given one with goerr.WithID, so
+// that the ID in the response, under the goerr.ErrorIDField member, matches
+// the one of the logged error. A 429 or 503 response carries the delay
+// given with goerr.WithRetryAfter as its Retry-After header, in whole
+// seconds. The status is goerr.ProblemStatus(err), as in the problem details
+// and the StatusField of the logged error.
+func (m *Middleware) Respond(w http.ResponseWriter, r *http.Request, err error) {
+	err = goerr.WithID(err, "")
+	if m.opts.OnError != nil {
+		m.opts.OnError(r, m.Enrich(r, err))
+	}
+	if status := goerr.ProblemStatus(err); status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable {
+		if d, ok := goerr.RetryAfter(err); ok && d > 0 {
+			w.Header().Set("Retry-After", strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10))
+		}
+	}
+	goerr.WriteProblem(w, r, err, m.problemOptions(r)...)
+}
+
+// MapRoute sets the options of the problem details written for requests
+// whose path matches pattern, so one binary can serve internal debugging
+// endpoints along with customer facing APIs, which only get the public
+// parts of errors. A pattern ending in "/*" matches every path below it,
+// other patterns are matched with path.Match. The first mapped pattern
+// matching a request applies. MapRoute is not safe to call
+// while the middleware serves requests.
+//
+//	m.MapRoute("/internal/*", goerr.ExposeStacks())
+func (m *Middleware) MapRoute(pattern string, opts ...goerr.ProblemOption) {
+	m.routes = append(m.routes, route{pattern: pattern, opts: opts})
+}
+
+// problemOptions returns the options of the first mapped route matching r.
+func (m *Middleware) problemOptions(r *http.Request) []goerr.ProblemOption {
+	for _, rt := range m.routes {
+		if matchRoute(rt.pattern, r.URL.Path) {
+			return rt.opts
+		}
+	}
+	return nil
+}
+
+// matchRoute reports whether p matches pattern, see MapRoute.
+func matchRoute(pattern, p string) bool {
+	if strings.HasSuffix(pattern, "/*") {
+		return strings.HasPrefix(p, strings.TrimSuffix(pattern, "*"))
+	}
+	ok, _ := path.Match(pattern, p)
+	return ok
+}
+
+// Enrich returns err with the captured fields of r. The status is the one
+// of the problem details of err, see goerr.ProblemStatus.
+func (m *Middleware) Enrich(r *http.Request, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	fields := map[string]any{}
+	m.add(fields, MethodField, r.Method)
+	m.add(fields, PathField, r.URL.Path)
+	if m.capture[RouteField] {
+		m.add(fields, RouteField, m.opts.Route(r))
+	}
+	if m.capture[StatusField] {
+		fields[StatusField] = goerr.ProblemStatus(err)
+	}
+	m.add(fields, RequestIDField, r.Header.Get(m.opts.RequestIDHeader))
+	m.add(fields, RemoteIPField, m.remoteIP(r))
+	return goerr.WithFields(err, fields)
+}
+
+// add sets fields[key] to value if key is captured and value is not empty.
+func (m *Middleware) add(fields map[string]any, key, value string) {
+	if m.capture[key] && value != "" {
+		fields[key] = value
+	}
+}
+
+// remoteIP returns the address of the client of r.
+func (m *Middleware) remoteIP(r *http.Request) string {
+	if m.opts.TrustForwardedFor {
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			return strings.TrimSpace(xff[strings.LastIndexByte(xff, ',')+1:])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
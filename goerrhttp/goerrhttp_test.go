@@ -0,0 +1,181 @@
+package goerrhttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/goerrhttp"
+)
+
+func serve(m *goerrhttp.Middleware, err error, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	m.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return err
+	}).ServeHTTP(w, r)
+	return w
+}
+
+func TestHandler(t *testing.T) {
+	var logged error
+	m := goerrhttp.New(goerrhttp.Options{
+		Route: func(r *http.Request) string { return "/orders/{id}" },
+		OnError: func(r *http.Request, err error) {
+			logged = err
+		},
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/orders/42", nil)
+	r.RemoteAddr = "10.0.0.1:5123"
+	r.Header.Set("X-Request-Id", "req-1")
+	w := serve(m, goerr.New(nil, http.StatusNotFound, "order not found"), r)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status. Want: 404, Got: %d", w.Code)
+	}
+	if body := w.Body.String(); strings.Contains(body, "req-1") {
+		t.Errorf("expected the request fields to be left out of the response, Got: %s", body)
+	}
+
+	want := map[string]any{
+		goerrhttp.MethodField:    "GET",
+		goerrhttp.PathField:      "/orders/42",
+		goerrhttp.RouteField:     "/orders/{id}",
+		goerrhttp.StatusField:    404,
+		goerrhttp.RequestIDField: "req-1",
+		goerrhttp.RemoteIPField:  "10.0.0.1",
+		goerr.ErrorIDField:       goerr.ID(logged),
+	}
+	if got := goerr.Fields(logged); !reflect.DeepEqual(got, want) {
+		t.Errorf("Want: %v, Got: %v", want, got)
+	}
+}
+
+func TestHandlerNoError(t *testing.T) {
+	called := false
+	m := goerrhttp.New(goerrhttp.Options{OnError: func(*http.Request, error) { called = true }})
+
+	w := serve(m, nil, httptest.NewRequest(http.MethodGet, "/", nil))
+	if called || w.Code != http.StatusOK {
+		t.Errorf("expected no error handling, Got: %d", w.Code)
+	}
+}
+
+func TestEnrichAllowDeny(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/pay", nil)
+	r.RemoteAddr = "10.0.0.1:5123"
+	r.Header.Set("X-Forwarded-For", "198.51.100.9, 203.0.113.7")
+	r.Header.Set("X-Correlation-Id", "corr-1")
+	err := goerr.New(nil, "payment failed")
+
+	m := goerrhttp.New(goerrhttp.Options{
+		Allow:             []string{goerrhttp.MethodField, goerrhttp.StatusField, goerrhttp.RequestIDField, goerrhttp.RemoteIPField},
+		Deny:              []string{goerrhttp.MethodField},
+		RequestIDHeader:   "X-Correlation-Id",
+		TrustForwardedFor: true,
+	})
+	want := map[string]any{
+		goerrhttp.StatusField:    500,
+		goerrhttp.RequestIDField: "corr-1",
+		goerrhttp.RemoteIPField:  "203.0.113.7",
+	}
+	if got := goerr.Fields(m.Enrich(r, err)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Want: %v, Got: %v", want, got)
+	}
+
+	if got := goerr.Fields(err); len(got) != 0 {
+		t.Errorf("expected the original error to be unchanged, Got: %v", got)
+	}
+	if m.Enrich(r, nil) != nil {
+		t.Errorf("Want: nil")
+	}
+}
+
+func TestEnrichStatus(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/rules", nil)
+	for _, err := range []error{
+		goerr.New(nil, 1042, "business rule"),
+		goerr.Mask(goerr.New(nil, http.StatusNotFound, "no rows"), "internal error"),
+		goerr.WithKind(goerr.New(nil, "order exists"), goerr.KindConflict),
+	} {
+		var logged error
+		m := goerrhttp.New(goerrhttp.Options{OnError: func(_ *http.Request, err error) { logged = err }})
+		w := serve(m, err, r)
+		if got := goerr.Fields(logged)[goerrhttp.StatusField]; got != w.Code {
+			t.Errorf("%v. Want: %d, Got: %v", err, w.Code, got)
+		}
+	}
+}
+
+func TestHandlerRetryAfter(t *testing.T) {
+	m := goerrhttp.New(goerrhttp.Options{})
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"429", goerr.WithRetryAfter(goerr.New(nil, http.StatusTooManyRequests, "rate limited"), 30*time.Second), "30"},
+		{"503 rounded up", goerr.WithRetryAfter(goerr.New(nil, http.StatusServiceUnavailable, "draining"), 1500*time.Millisecond), "2"},
+		{"other status", goerr.WithRetryAfter(goerr.New(nil, http.StatusBadGateway, "upstream failed"), time.Second), ""},
+		{"no hint", goerr.New(nil, http.StatusServiceUnavailable, "draining"), ""},
+		{"masked", goerr.Mask(goerr.WithRetryAfter(goerr.New(nil, http.StatusTooManyRequests, "rate limited"), time.Second), "internal error"), ""},
+	}
+
+	for _, tt := range tests {
+		w := serve(m, tt.err, httptest.NewRequest(http.MethodGet, "/", nil))
+		if got := w.Header().Get("Retry-After"); got != tt.want {
+			t.Errorf("%s. Want: %q, Got: %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestMapRoute(t *testing.T) {
+	m := goerrhttp.New(goerrhttp.Options{})
+	m.MapRoute("/internal/*", goerr.ExposeStacks())
+	m.MapRoute("/debug/?", goerr.ExposeStacks())
+	err := goerr.Mask(goerr.New(nil, "pq: deadlock detected"), http.StatusInternalServerError, "internal error")
+
+	tests := []struct {
+		path   string
+		stacks bool
+	}{
+		{"/internal/orders/42", true},
+		{"/debug/x", true},
+		{"/debug/xy", false},
+		{"/orders/42", false},
+		{"/internal", false},
+	}
+	for _, tt := range tests {
+		w := serve(m, err, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		body := w.Body.String()
+		if got := strings.Contains(body, `"stack"`) && strings.Contains(body, "pq: deadlock detected"); got != tt.stacks {
+			t.Errorf("%s. Want stacks: %t, Got: %s", tt.path, tt.stacks, body)
+		}
+		if !strings.Contains(body, `"detail":"internal error"`) {
+			t.Errorf("%s. expected the public message, Got: %s", tt.path, body)
+		}
+	}
+}
+
+func TestHandlerErrorID(t *testing.T) {
+	var logged error
+	m := goerrhttp.New(goerrhttp.Options{OnError: func(r *http.Request, err error) { logged = err }})
+
+	w := serve(m, goerr.Mask(goerr.New(nil, "pq: deadlock"), http.StatusInternalServerError, "internal error"), httptest.NewRequest(http.MethodGet, "/", nil))
+	id := goerr.ID(logged)
+	if id == "" {
+		t.Fatalf("expected the logged error to have an ID")
+	}
+	if body := w.Body.String(); !strings.Contains(body, `"error_id":"`+id+`"`) {
+		t.Errorf("expected the response to contain the ID %s, Got: %s", id, body)
+	}
+
+	w = serve(m, goerr.WithID(goerr.New(nil, "failed"), "ref-1"), httptest.NewRequest(http.MethodGet, "/", nil))
+	if want, got := "ref-1", goerr.ID(logged); got != want {
+		t.Errorf("expected the ID to be kept. Want: %s, Got: %s", want, got)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build go1.23
+
+package goerrhttp
+
+import "net/http"
+
+// pattern returns the pattern matched by http.ServeMux.
+func pattern(r *http.Request) string {
+	return r.Pattern
+}
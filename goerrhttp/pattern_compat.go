@@ -0,0 +1,11 @@
+//go:build !go1.23
+
+package goerrhttp
+
+import "net/http"
+
+// pattern returns an empty string, as http.ServeMux does not report the
+// pattern it matched before Go 1.23.
+func pattern(r *http.Request) string {
+	return ""
+}
@@ -0,0 +1,29 @@
+//go:build go1.23
+
+//go:debug httpmuxgo121=0
+
+package goerrhttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/goerrhttp"
+)
+
+func TestServeMuxRoute(t *testing.T) {
+	var logged error
+	m := goerrhttp.New(goerrhttp.Options{OnError: func(r *http.Request, err error) { logged = err }})
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /orders/{id}", m.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return goerr.New(nil, "failed")
+	}))
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/42", nil))
+
+	if got := goerr.Fields(logged)[goerrhttp.RouteField]; got != "GET /orders/{id}" {
+		t.Errorf("Want: GET /orders/{id}, Got: %v", got)
+	}
+}
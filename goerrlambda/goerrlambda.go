@@ -0,0 +1,102 @@
+// Package goerrlambda adapts AWS Lambda handlers returning goerr errors. The
+// errors are logged with their full stack as single line JSON, which
+// CloudWatch Logs indexes field by field, and returned to the invoker as a
+// compact error carrying their code and fingerprint.
+//
+// The package does not depend on github.com/aws/aws-lambda-go: handlers are
+// wrapped before they are passed to lambda.Start.
+//
+//	lambda.Start(goerrlambda.Wrap(handle, goerrlambda.Options{}))
+package goerrlambda
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/angel-one/goerr"
+)
+
+// Options configure Wrap.
+type Options struct {
+	// Output receives the log lines, by default os.Stdout, which Lambda sends
+	// to CloudWatch Logs.
+	Output io.Writer
+}
+
+// Error is the error returned to the invoker in place of a goerr chain. The
+// Lambda runtime reports its Error() text as the errorMessage of the
+// response, so invokers like Step Functions can parse the code and the
+// fingerprint from it.
+type Error struct {
+	// Message is the public message of the chain, see goerr.PublicMessage.
+	Message string `json:"message"`
+	// Code is the code of the chain, see goerr.Code.
+	Code int `json:"code,omitempty"`
+	// Fingerprint identifies the kind of failure, see goerr.Fingerprint.
+	Fingerprint string `json:"fingerprint"`
+}
+
+// Error returns e as a JSON document.
+func (e *Error) Error() string {
+	b, _ := json.Marshal(e)
+	return string(b)
+}
+
+// line is a log line written by Wrap.
+type line struct {
+	Level       string         `json:"level"`
+	Message     string         `json:"message"`
+	Code        int            `json:"code,omitempty"`
+	Fingerprint string         `json:"fingerprint"`
+	Stack       []string       `json:"stack"`
+	Fields      map[string]any `json:"fields,omitempty"`
+}
+
+// outputMu serializes log lines, so concurrent invocations do not interleave.
+var outputMu sync.Mutex
+
+// Wrap returns a handler calling handler. If handler returns an error, it is
+// logged to Options.Output as one JSON line with its message, code,
+// fingerprint, rendered layers and fields, and replaced by an *Error.
+func Wrap[In, Out any](handler func(ctx context.Context, in In) (Out, error), opts Options) func(ctx context.Context, in In) (Out, error) {
+	if opts.Output == nil {
+		opts.Output = os.Stdout
+	}
+
+	return func(ctx context.Context, in In) (Out, error) {
+		out, err := handler(ctx, in)
+		if err == nil {
+			return out, nil
+		}
+
+		fp := goerr.Fingerprint(err)
+		l := line{
+			Level:       "ERROR",
+			Message:     message(err),
+			Code:        goerr.Code(err),
+			Fingerprint: fp,
+			Stack:       goerr.ListStacks(err),
+			Fields:      goerr.Fields(err),
+		}
+		b, merr := json.Marshal(l)
+		if merr != nil {
+			// a field cannot be encoded
+			l.Fields = nil
+			b, _ = json.Marshal(l)
+		}
+		outputMu.Lock()
+		opts.Output.Write(append(b, '\n'))
+		outputMu.Unlock()
+
+		return out, &Error{Message: goerr.PublicMessage(err), Code: goerr.Code(err), Fingerprint: fp}
+	}
+}
+
+// message returns the message of err with the registered redactors applied,
+// like the one of its first stack line.
+func message(err error) string {
+	return goerr.ListEntries(err)[0].Message
+}
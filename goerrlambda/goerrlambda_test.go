@@ -0,0 +1,109 @@
+package goerrlambda_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/goerrlambda"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+type event struct {
+	ID string
+}
+
+func TestWrap(t *testing.T) {
+	var out bytes.Buffer
+	failure := goerr.WithField(goerr.New(samplesrc.Service(), http.StatusNotFound, "order not found"), "order_id", "42")
+	handler := goerrlambda.Wrap(func(ctx context.Context, e event) (string, error) {
+		return "partial", failure
+	}, goerrlambda.Options{Output: &out})
+
+	got, err := handler(context.Background(), event{ID: "42"})
+	if got != "partial" {
+		t.Errorf("Want: partial, Got: %s", got)
+	}
+
+	var lerr *goerrlambda.Error
+	if !errors.As(err, &lerr) {
+		t.Fatalf("Want: *goerrlambda.Error, Got: %T", err)
+	}
+	want := goerrlambda.Error{Message: "order not found", Code: http.StatusNotFound, Fingerprint: goerr.Fingerprint(failure)}
+	if *lerr != want {
+		t.Errorf("Want: %+v, Got: %+v", want, *lerr)
+	}
+	if !strings.HasPrefix(err.Error(), `{"message":"order not found","code":404,"fingerprint":"`) {
+		t.Errorf("expected a JSON message, Got: %s", err.Error())
+	}
+
+	if n := strings.Count(out.String(), "\n"); n != 1 {
+		t.Fatalf("expected a single line, Got: %q", out.String())
+	}
+	var line struct {
+		Level       string
+		Message     string
+		Code        int
+		Fingerprint string
+		Stack       []string
+		Fields      map[string]any
+	}
+	if err := json.Unmarshal(out.Bytes(), &line); err != nil {
+		t.Fatal(err)
+	}
+	if line.Level != "ERROR" || line.Message != "order not found" || line.Code != 404 || line.Fingerprint != want.Fingerprint {
+		t.Errorf("unexpected log line: %s", out.String())
+	}
+	if len(line.Stack) != 3 || !strings.Contains(line.Stack[1], "service failed") {
+		t.Errorf("expected the stack, Got: %v", line.Stack)
+	}
+	if line.Fields["order_id"] != "42" {
+		t.Errorf("expected the fields, Got: %v", line.Fields)
+	}
+}
+
+func TestWrapNoError(t *testing.T) {
+	var out bytes.Buffer
+	handler := goerrlambda.Wrap(func(ctx context.Context, n int) (int, error) {
+		return n * 2, nil
+	}, goerrlambda.Options{Output: &out})
+
+	if got, err := handler(context.Background(), 21); got != 42 || err != nil {
+		t.Errorf("Want: 42, <nil>, Got: %d, %v", got, err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected nothing to be logged, Got: %s", out.String())
+	}
+}
+
+func TestWrapMasked(t *testing.T) {
+	handler := goerrlambda.Wrap(func(ctx context.Context, _ struct{}) (struct{}, error) {
+		return struct{}{}, goerr.Mask(goerr.New(errors.New("dial tcp 10.0.0.1:5432"), "db down"), "try again later")
+	}, goerrlambda.Options{Output: &bytes.Buffer{}})
+
+	_, err := handler(context.Background(), struct{}{})
+	if got := err.(*goerrlambda.Error).Message; got != "try again later" {
+		t.Errorf("Want: try again later, Got: %s", got)
+	}
+}
+
+func TestWrapRedacted(t *testing.T) {
+	goerr.AddRedactor(goerr.RegexRedactor(regexp.MustCompile(`token=\w+`)))
+	t.Cleanup(func() { goerr.Configure(goerr.Options{}) })
+
+	var out bytes.Buffer
+	handler := goerrlambda.Wrap(func(ctx context.Context, _ struct{}) (struct{}, error) {
+		return struct{}{}, goerr.New(nil, "auth failed token=s3cr3t")
+	}, goerrlambda.Options{Output: &out})
+
+	handler(context.Background(), struct{}{})
+	if strings.Contains(out.String(), "s3cr3t") {
+		t.Errorf("expected the message to be redacted, Got: %s", out.String())
+	}
+}
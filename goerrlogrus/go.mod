@@ -0,0 +1,12 @@
+module github.com/angel-one/goerr/goerrlogrus
+
+go 1.19
+
+require (
+	github.com/angel-one/goerr v0.0.0
+	github.com/sirupsen/logrus v1.9.3
+)
+
+require golang.org/x/sys v0.18.0 // indirect
+
+replace github.com/angel-one/goerr => ../
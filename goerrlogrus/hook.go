@@ -0,0 +1,66 @@
+// Package goerrlogrus provides a logrus hook that logs goerr errors as
+// structured fields instead of a flat message.
+package goerrlogrus
+
+import (
+	"errors"
+
+	"github.com/angel-one/goerr"
+	"github.com/sirupsen/logrus"
+)
+
+// Keys of the fields added to entries logged with a goerr error.
+const (
+	CodeKey        = "error_code"
+	FramesKey      = "error_frames"
+	FieldsKey      = "error_fields"
+	FingerprintKey = "error_fingerprint"
+	RepeatsKey     = "error_repeats"
+)
+
+// Hook replaces a goerr error added with WithError by its message and adds
+// its code, the rendered layers and its fields as separate fields. Entries
+// without a goerr error are left untouched.
+//
+// With goerr.SuppressRepeats, errors repeating within the window are logged
+// with their fingerprint and the number of repeats instead of their layers.
+// In pretty mode (see goerr.SetPretty) the layers are appended to the message
+// as rendered by goerr.Pretty, for the text formatter in a terminal.
+//
+//	logger.AddHook(goerrlogrus.Hook{})
+type Hook struct{}
+
+// Levels returns all levels, so that the hook fires for every entry.
+func (Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds the goerr fields to entry.
+func (Hook) Fire(entry *logrus.Entry) error {
+	err, ok := entry.Data[logrus.ErrorKey].(error)
+	if !ok {
+		return nil
+	}
+
+	var e *goerr.Error
+	if !errors.As(err, &e) {
+		return nil
+	}
+
+	entry.Data[logrus.ErrorKey] = goerr.ListEntries(err)[0].Message
+	if code := goerr.Code(err); code != 0 {
+		entry.Data[CodeKey] = code
+	}
+	if count, suppress := goerr.Repeated(err); suppress {
+		entry.Data[FingerprintKey] = goerr.Fingerprint(err)
+		entry.Data[RepeatsKey] = count
+	} else if goerr.PrettyMode() {
+		entry.Message += "\n" + goerr.Pretty(err)
+	} else {
+		entry.Data[FramesKey] = goerr.ListStacks(err)
+	}
+	if fields := goerr.Fields(err); len(fields) > 0 {
+		entry.Data[FieldsKey] = fields
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package goerrlogrus_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/goerrlogrus"
+	"github.com/angel-one/goerr/samplesrc"
+	"github.com/sirupsen/logrus"
+)
+
+func newLogger(buf *bytes.Buffer) *logrus.Logger {
+	logger := logrus.New()
+	logger.Out = buf
+	logger.Formatter = &logrus.JSONFormatter{}
+	logger.AddHook(goerrlogrus.Hook{})
+	return logger
+}
+
+func TestHook(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLogger(&buf)
+
+	err := goerr.WithField(goerr.New(samplesrc.Service(), 409, "controller failed"), "order_id", 42)
+	logger.WithError(err).Error("request failed")
+
+	var entry struct {
+		Error  string         `json:"error"`
+		Code   int            `json:"error_code"`
+		Frames []string       `json:"error_frames"`
+		Fields map[string]any `json:"error_fields"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid log entry: %v", err)
+	}
+
+	if entry.Error != "controller failed" {
+		t.Errorf("Want: %s, Got: %s", "controller failed", entry.Error)
+	}
+	if entry.Code != 409 {
+		t.Errorf("Want: %d, Got: %d", 409, entry.Code)
+	}
+	if len(entry.Frames) != 3 {
+		t.Errorf("Nr. of frames. Want: %d, Got: %d", 3, len(entry.Frames))
+	}
+	if entry.Fields["order_id"] != 42.0 {
+		t.Errorf("unexpected fields: %v", entry.Fields)
+	}
+}
+
+func TestHookRedacted(t *testing.T) {
+	goerr.AddRedactor(goerr.RegexRedactor(regexp.MustCompile(`token=\w+`)))
+	t.Cleanup(func() { goerr.Configure(goerr.Options{}) })
+
+	var buf bytes.Buffer
+	logger := newLogger(&buf)
+	logger.WithError(goerr.New(nil, "auth failed token=s3cr3t")).Error("request failed")
+
+	if strings.Contains(buf.String(), "s3cr3t") {
+		t.Errorf("expected the message to be redacted, Got: %s", buf.String())
+	}
+}
+
+func TestHookNonGoErr(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLogger(&buf)
+
+	logger.WithError(errors.New("plain")).Error("request failed")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid log entry: %v", err)
+	}
+	if entry["error"] != "plain" {
+		t.Errorf("Want: %s, Got: %v", "plain", entry["error"])
+	}
+	if _, ok := entry[goerrlogrus.FramesKey]; ok {
+		t.Errorf("unexpected frames for a plain error")
+	}
+}
+
+func TestHookSuppressRepeats(t *testing.T) {
+	goerr.SuppressRepeats(time.Minute)
+	defer goerr.SuppressRepeats(0)
+
+	var buf bytes.Buffer
+	logger := newLogger(&buf)
+
+	for i := 0; i < 3; i++ {
+		logger.WithError(samplesrc.Controller()).Error("request failed")
+	}
+
+	dec := json.NewDecoder(&buf)
+	for i := 1; i <= 3; i++ {
+		var entry map[string]any
+		if err := dec.Decode(&entry); err != nil {
+			t.Fatalf("invalid log entry: %v", err)
+		}
+		_, full := entry[goerrlogrus.FramesKey]
+		if full != (i == 1) {
+			t.Errorf("entry %d. Want frames: %v, Got: %v", i, i == 1, entry)
+		}
+		if i > 1 && (entry[goerrlogrus.RepeatsKey] != float64(i) || entry[goerrlogrus.FingerprintKey] == nil) {
+			t.Errorf("entry %d: expected the counter, Got: %v", i, entry)
+		}
+	}
+}
+
+func TestHookPretty(t *testing.T) {
+	defer goerr.SetPretty(goerr.PrettyMode())
+	goerr.SetPretty(true)
+
+	var buf bytes.Buffer
+	logger := newLogger(&buf)
+	err := samplesrc.Service()
+	logger.WithError(err).Error("request failed")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid log entry: %v", err)
+	}
+	if want := "request failed\n" + goerr.Pretty(err); entry["msg"] != want {
+		t.Errorf("Want: %q, Got: %q", want, entry["msg"])
+	}
+	if _, ok := entry[goerrlogrus.FramesKey]; ok {
+		t.Errorf("unexpected frames in pretty mode")
+	}
+}
@@ -0,0 +1,56 @@
+// Package goerrmetrics exposes Prometheus counters for goerr errors, labeled
+// by code, kind and the function in which the error was created.
+package goerrmetrics
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/angel-one/goerr"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Errors counts the observed errors. It must be registered with Register (or
+// any prometheus.Registerer) to be exported.
+var Errors = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "goerr",
+	Name:      "errors_total",
+	Help:      "Number of errors, by code, kind and the function that created them.",
+}, []string{"code", "kind", "function"})
+
+// Register registers the Errors counter with reg.
+func Register(reg prometheus.Registerer) error {
+	return reg.Register(Errors)
+}
+
+// Observe counts err once. It is meant to be called where errors are handled,
+// typically next to logging them. Nil errors are ignored.
+func Observe(err error) {
+	if err == nil {
+		return
+	}
+
+	function := ""
+	var e *goerr.Error
+	if errors.As(err, &e) {
+		function = e.Func()
+	}
+	Errors.WithLabelValues(labels(err, function)...).Inc()
+}
+
+// Hook counts every layer when it is created. Unlike Observe it counts an
+// error once for every time it was wrapped. Install it with
+//
+//	goerr.OnNew(goerrmetrics.Hook)
+func Hook(e *goerr.Error) {
+	Errors.WithLabelValues(labels(e, e.Func())...).Inc()
+}
+
+func labels(err error, function string) []string {
+	return []string{
+		strconv.Itoa(goerr.Code(err)),
+		string(goerr.KindOf(err)),
+		function[strings.LastIndex(function, "/")+1:],
+	}
+}
@@ -0,0 +1,51 @@
+package goerrmetrics_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/goerrmetrics"
+	"github.com/angel-one/goerr/samplesrc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserve(t *testing.T) {
+	goerrmetrics.Errors.Reset()
+
+	err := goerr.New(samplesrc.Repository(), http.StatusNotFound, "order not found")
+	goerrmetrics.Observe(err)
+	goerrmetrics.Observe(err)
+	goerrmetrics.Observe(errors.New("plain"))
+	goerrmetrics.Observe(nil)
+
+	got := testutil.ToFloat64(goerrmetrics.Errors.WithLabelValues("404", "not_found", "goerrmetrics_test.TestObserve"))
+	if got != 2 {
+		t.Errorf("Want: %d, Got: %v", 2, got)
+	}
+	if got := testutil.ToFloat64(goerrmetrics.Errors.WithLabelValues("0", "", "")); got != 1 {
+		t.Errorf("Want: %d, Got: %v", 1, got)
+	}
+}
+
+func TestHook(t *testing.T) {
+	goerrmetrics.Errors.Reset()
+	goerr.OnNew(goerrmetrics.Hook)
+
+	samplesrc.Controller()
+
+	if got := testutil.CollectAndCount(goerrmetrics.Errors); got != 3 {
+		t.Errorf("Nr. of series. Want: %d, Got: %d", 3, got)
+	}
+	if got := testutil.ToFloat64(goerrmetrics.Errors.WithLabelValues("0", "", "samplesrc.Service")); got != 1 {
+		t.Errorf("Want: %d, Got: %v", 1, got)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	if err := goerrmetrics.Register(prometheus.NewRegistry()); err != nil {
+		t.Errorf("register failed: %v", err)
+	}
+}
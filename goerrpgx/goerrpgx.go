@@ -0,0 +1,221 @@
+// Package goerrpgx maps the errors of Postgres, as reported by
+// github.com/jackc/pgx (*pgconn.PgError) and github.com/lib/pq (*pq.Error),
+// to goerr kinds by their SQLSTATE, and attaches the schema, table, column,
+// data type and constraint they name as fields. It covers more conditions
+// than goerrsql, tells which failures can be retried, and lets teams change
+// the mapping of any condition.
+//
+// Drivers are recognized without importing them, by the SQLState method of
+// their errors.
+package goerrpgx
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+
+	"github.com/angel-one/goerr"
+)
+
+// Fields set by Enrich. SQLStateField, ConstraintField, TableField and
+// ColumnField are the ones of goerrsql.
+const (
+	SQLStateField   = "sqlstate"
+	ConditionField  = "pg_condition"
+	SchemaField     = "schema"
+	TableField      = "table"
+	ColumnField     = "column"
+	DataTypeField   = "data_type"
+	ConstraintField = "constraint"
+	RetryableField  = "retryable"
+)
+
+// Mapping is how errors of a SQLSTATE are classified.
+type Mapping struct {
+	// Condition is the name of the SQLSTATE in the Postgres documentation,
+	// e.g. "unique_violation".
+	Condition string
+	// Kind is set on the enriched error, if not empty.
+	Kind goerr.Kind
+	// Retryable tells that the failed transaction can be retried as is.
+	Retryable bool
+}
+
+var (
+	mappingsMu sync.RWMutex
+	mappings   = map[string]Mapping{
+		// class 08, connection exception
+		"08000": {Condition: "connection_exception", Kind: goerr.KindUnavailable, Retryable: true},
+		"08003": {Condition: "connection_does_not_exist", Kind: goerr.KindUnavailable, Retryable: true},
+		"08006": {Condition: "connection_failure", Kind: goerr.KindUnavailable, Retryable: true},
+		// class 22, data exception
+		"22001": {Condition: "string_data_right_truncation", Kind: goerr.KindUnprocessable},
+		"22003": {Condition: "numeric_value_out_of_range", Kind: goerr.KindUnprocessable},
+		"22007": {Condition: "invalid_datetime_format", Kind: goerr.KindUnprocessable},
+		"22P02": {Condition: "invalid_text_representation", Kind: goerr.KindUnprocessable},
+		// class 23, integrity constraint violation
+		"23000": {Condition: "integrity_constraint_violation", Kind: goerr.KindUnprocessable},
+		"23001": {Condition: "restrict_violation", Kind: goerr.KindUnprocessable},
+		"23502": {Condition: "not_null_violation", Kind: goerr.KindUnprocessable},
+		"23503": {Condition: "foreign_key_violation", Kind: goerr.KindUnprocessable},
+		"23505": {Condition: "unique_violation", Kind: goerr.KindConflict},
+		"23514": {Condition: "check_violation", Kind: goerr.KindUnprocessable},
+		"23P01": {Condition: "exclusion_violation", Kind: goerr.KindConflict},
+		// class 25, invalid transaction state, e.g. writing to a replica
+		// during a failover
+		"25006": {Condition: "read_only_sql_transaction", Kind: goerr.KindUnavailable, Retryable: true},
+		// class 40, transaction rollback
+		"40001": {Condition: "serialization_failure", Kind: goerr.KindConflict, Retryable: true},
+		"40P01": {Condition: "deadlock_detected", Kind: goerr.KindConflict, Retryable: true},
+		// class 53, insufficient resources
+		"53300": {Condition: "too_many_connections", Kind: goerr.KindUnavailable, Retryable: true},
+		// class 55, object not in prerequisite state
+		"55P03": {Condition: "lock_not_available", Kind: goerr.KindTimeout, Retryable: true},
+		// class 57, operator intervention
+		"57014": {Condition: "query_canceled", Kind: goerr.KindTimeout},
+		"57P01": {Condition: "admin_shutdown", Kind: goerr.KindUnavailable, Retryable: true},
+		"57P03": {Condition: "cannot_connect_now", Kind: goerr.KindUnavailable, Retryable: true},
+	}
+)
+
+// Register sets the mapping of sqlstate, a SQLSTATE like "23505" or a class
+// like "23" which applies to the SQLSTATEs of the class without a mapping of
+// their own. It replaces the built in mapping, if any.
+//
+//	goerrpgx.Register("23503", goerrpgx.Mapping{Condition: "foreign_key_violation", Kind: goerr.KindNotFound})
+func Register(sqlstate string, m Mapping) {
+	mappingsMu.Lock()
+	defer mappingsMu.Unlock()
+	mappings[sqlstate] = m
+}
+
+// Lookup returns the mapping of sqlstate, or of its class, and reports
+// whether there is one.
+func Lookup(sqlstate string) (Mapping, bool) {
+	mappingsMu.RLock()
+	defer mappingsMu.RUnlock()
+
+	if m, ok := mappings[sqlstate]; ok {
+		return m, true
+	}
+	if len(sqlstate) == 5 {
+		m, ok := mappings[sqlstate[:2]]
+		return m, ok
+	}
+	return Mapping{}, false
+}
+
+// PgError is the Postgres error found in a chain.
+type PgError struct {
+	// Err is the error of the driver.
+	Err error
+	// SQLState is the SQLSTATE of the error, e.g. 23505.
+	SQLState string
+	// Schema, Table, Column, DataType and Constraint name the objects
+	// involved, if the server reports them.
+	Schema     string
+	Table      string
+	Column     string
+	DataType   string
+	Constraint string
+}
+
+// Find returns the first Postgres error in the chain of err and reports
+// whether there is one.
+func Find(err error) (PgError, bool) {
+	var pg interface {
+		error
+		SQLState() string
+	}
+	if !errors.As(err, &pg) {
+		return PgError{}, false
+	}
+
+	v := reflect.ValueOf(pg)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	return PgError{
+		Err:        pg,
+		SQLState:   pg.SQLState(),
+		Schema:     stringField(v, "SchemaName", "Schema"),
+		Table:      stringField(v, "TableName", "Table"),
+		Column:     stringField(v, "ColumnName", "Column"),
+		DataType:   stringField(v, "DataTypeName"),
+		Constraint: stringField(v, "ConstraintName", "Constraint"),
+	}, true
+}
+
+// Enrich returns err with the details of the Postgres error it wraps set as
+// fields, and the kind of its SQLSTATE set, on its outermost layer. The kind
+// implies the code, e.g. 409 for a unique violation, unless a code was given
+// explicitly. Errors not wrapping a Postgres error are returned as they are.
+//
+//	if err != nil {
+//		return goerrpgx.Enrich(goerr.New(err, "insert order failed"))
+//	}
+func Enrich(err error) error {
+	pg, ok := Find(err)
+	if !ok {
+		return err
+	}
+
+	fields := map[string]any{SQLStateField: pg.SQLState}
+	for k, v := range map[string]string{
+		SchemaField:     pg.Schema,
+		TableField:      pg.Table,
+		ColumnField:     pg.Column,
+		DataTypeField:   pg.DataType,
+		ConstraintField: pg.Constraint,
+	} {
+		if v != "" {
+			fields[k] = v
+		}
+	}
+
+	m, ok := Lookup(pg.SQLState)
+	if ok {
+		if m.Condition != "" {
+			fields[ConditionField] = m.Condition
+		}
+		if m.Retryable {
+			fields[RetryableField] = true
+		}
+	}
+	err = goerr.WithFields(err, fields)
+	if m.Kind != "" {
+		err = goerr.WithKind(err, m.Kind)
+	}
+	return err
+}
+
+// Retryable reports whether err wraps a Postgres error whose transaction can
+// be retried as is, like a serialization failure or a deadlock.
+//
+//	for attempt := 0; ; attempt++ {
+//		err := transfer(ctx)
+//		if attempt == 3 || !goerrpgx.Retryable(err) {
+//			return err
+//		}
+//	}
+func Retryable(err error) bool {
+	pg, ok := Find(err)
+	if !ok {
+		return false
+	}
+	m, _ := Lookup(pg.SQLState)
+	return m.Retryable
+}
+
+// stringField returns the value of the first of the named string fields of v.
+func stringField(v reflect.Value, names ...string) string {
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	for _, name := range names {
+		if f := v.FieldByName(name); f.IsValid() && f.Kind() == reflect.String {
+			return f.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package goerrpgx_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/goerrpgx"
+)
+
+// PgError mimics *pgconn.PgError.
+type PgError struct {
+	Code           string
+	Message        string
+	SchemaName     string
+	TableName      string
+	ColumnName     string
+	DataTypeName   string
+	ConstraintName string
+}
+
+func (e *PgError) Error() string    { return e.Message }
+func (e *PgError) SQLState() string { return e.Code }
+
+func TestEnrich(t *testing.T) {
+	dbErr := &PgError{Code: "23505", Message: "duplicate key value", SchemaName: "public", TableName: "orders", ConstraintName: "orders_pkey"}
+
+	err := goerrpgx.Enrich(goerr.New(fmt.Errorf("exec: %w", dbErr), "insert order failed"))
+
+	if got := goerr.Code(err); got != http.StatusConflict {
+		t.Errorf("Want: %d, Got: %d", http.StatusConflict, got)
+	}
+	fields := goerr.Fields(err)
+	want := map[string]any{
+		goerrpgx.SQLStateField:   "23505",
+		goerrpgx.ConditionField:  "unique_violation",
+		goerrpgx.SchemaField:     "public",
+		goerrpgx.TableField:      "orders",
+		goerrpgx.ConstraintField: "orders_pkey",
+	}
+	for k, v := range want {
+		if fields[k] != v {
+			t.Errorf("%s. Want: %v, Got: %v", k, v, fields[k])
+		}
+	}
+	for _, k := range []string{goerrpgx.ColumnField, goerrpgx.DataTypeField, goerrpgx.RetryableField} {
+		if _, ok := fields[k]; ok {
+			t.Errorf("unexpected field %s: %v", k, fields)
+		}
+	}
+	if goerrpgx.Retryable(err) {
+		t.Errorf("expected a unique violation not to be retryable")
+	}
+}
+
+func TestEnrichRetryable(t *testing.T) {
+	err := goerrpgx.Enrich(goerr.New(&PgError{Code: "40001", Message: "could not serialize access"}, "transfer failed"))
+
+	if got := goerr.Code(err); got != http.StatusConflict {
+		t.Errorf("Want: %d, Got: %d", http.StatusConflict, got)
+	}
+	if !goerrpgx.Retryable(err) || goerr.Fields(err)[goerrpgx.RetryableField] != true {
+		t.Errorf("expected a serialization failure to be retryable, Got: %v", goerr.Fields(err))
+	}
+}
+
+func TestEnrichClass(t *testing.T) {
+	// an integrity violation without a mapping of its own
+	err := goerrpgx.Enrich(goerr.New(&PgError{Code: "23999", Message: "violation"}, "insert failed"))
+	if got := goerr.KindOf(err); got != "" {
+		t.Errorf("expected no kind for an unmapped class, Got: %s", got)
+	}
+
+	goerrpgx.Register("23", goerrpgx.Mapping{Kind: goerr.KindUnprocessable})
+	defer goerrpgx.Register("23", goerrpgx.Mapping{})
+
+	err = goerrpgx.Enrich(goerr.New(&PgError{Code: "23999", Message: "violation"}, "insert failed"))
+	if got := goerr.Code(err); got != http.StatusUnprocessableEntity {
+		t.Errorf("Want: %d, Got: %d", http.StatusUnprocessableEntity, got)
+	}
+	if _, ok := goerr.Fields(err)[goerrpgx.ConditionField]; ok {
+		t.Errorf("expected no condition, Got: %v", goerr.Fields(err))
+	}
+
+	// the mapping of a SQLSTATE wins over the one of its class
+	if m, _ := goerrpgx.Lookup("23505"); m.Kind != goerr.KindConflict {
+		t.Errorf("Want: %s, Got: %s", goerr.KindConflict, m.Kind)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	goerrpgx.Register("23503", goerrpgx.Mapping{Condition: "foreign_key_violation", Kind: goerr.KindNotFound})
+	defer goerrpgx.Register("23503", goerrpgx.Mapping{Condition: "foreign_key_violation", Kind: goerr.KindUnprocessable})
+
+	err := goerrpgx.Enrich(goerr.New(&PgError{Code: "23503", Message: "violates foreign key"}, "insert item failed"))
+	if got := goerr.Code(err); got != http.StatusNotFound {
+		t.Errorf("Want: %d, Got: %d", http.StatusNotFound, got)
+	}
+}
+
+func TestEnrichOtherErrors(t *testing.T) {
+	err := goerr.New(errors.New("connection refused"), "insert order failed")
+
+	if got := goerrpgx.Enrich(err); got != err {
+		t.Errorf("expected err to be returned unchanged")
+	}
+	if goerrpgx.Retryable(err) || goerrpgx.Retryable(nil) {
+		t.Errorf("expected other errors not to be retryable")
+	}
+}
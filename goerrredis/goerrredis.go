@@ -0,0 +1,208 @@
+// Package goerrredis classifies the errors of github.com/redis/go-redis and
+// wraps them in goerr layers with the command and key that failed.
+//
+// Errors are recognized without importing go-redis: redis.Nil by its
+// message, errors replied by the server by their type, proto.RedisError, and
+// network errors as net.Error.
+package goerrredis
+
+import (
+	"errors"
+	"net"
+	"os"
+	"reflect"
+	"strings"
+	"sync/atomic"
+
+	"github.com/angel-one/goerr"
+)
+
+// Fields set by New and FromCmd.
+const (
+	CommandField = "redis_command"
+	KeyField     = "redis_key"
+	// ErrorPrefixField is the prefix of an error replied by the server, e.g.
+	// WRONGTYPE or READONLY.
+	ErrorPrefixField = "redis_error"
+	RetryableField   = "retryable"
+)
+
+// KeyMode controls how keys are set in the KeyField field.
+type KeyMode int32
+
+const (
+	// KeyPattern replaces the segments of a key separated by ':' that
+	// contain a digit by '*', e.g. user:*:session for user:42:session, so
+	// that errors are grouped by pattern and IDs are not logged. This is the
+	// default.
+	KeyPattern KeyMode = iota
+	// KeyFull keeps the whole key.
+	KeyFull
+	// KeyRedacted replaces the key by goerr.Redacted.
+	KeyRedacted
+)
+
+var keyMode atomic.Int32
+
+// SetKeyMode sets how keys are set in the KeyField field.
+func SetKeyMode(m KeyMode) {
+	keyMode.Store(int32(m))
+}
+
+// nilMessage is the message of redis.Nil, the reply to a missing key.
+const nilMessage = "redis: nil"
+
+// retryablePrefixes are the prefixes of server errors that go away when the
+// server is ready again, after loading its dataset or a failover.
+var retryablePrefixes = map[string]bool{
+	"LOADING":     true,
+	"READONLY":    true,
+	"MASTERDOWN":  true,
+	"CLUSTERDOWN": true,
+	"TRYAGAIN":    true,
+	"BUSY":        true,
+}
+
+// Classify returns the kind of the go-redis error in the chain of err, and
+// whether the command can be retried. redis.Nil is not found, network errors
+// and timeouts, including the timeout of the connection pool, are
+// unavailable and retryable, and so are the errors of a server loading its
+// dataset or being a read only replica (LOADING, READONLY, MASTERDOWN,
+// CLUSTERDOWN, TRYAGAIN and BUSY). Other server errors are internal. Errors
+// that are not go-redis errors have no kind.
+func Classify(err error) (kind goerr.Kind, retryable bool) {
+	if err == nil {
+		return "", false
+	}
+	if isNil(err) {
+		return goerr.KindNotFound, false
+	}
+	if prefix, ok := serverError(err); ok {
+		return goerr.KindInternal, false
+	} else if prefix != "" {
+		return goerr.KindUnavailable, true
+	}
+
+	var ne net.Error
+	if errors.As(err, &ne) || errors.Is(err, os.ErrDeadlineExceeded) || strings.Contains(err.Error(), "redis: connection pool timeout") {
+		return goerr.KindUnavailable, true
+	}
+	return "", false
+}
+
+// New returns err, the error of the Redis command cmd on key, wrapped in a
+// new layer with the kind given by Classify, the command and key as fields,
+// and the prefix of the server error, if any. Retryable errors have the
+// RetryableField field. key may be empty for commands without a key. New
+// returns nil if err is nil.
+//
+//	val, err := rdb.Get(ctx, key).Result()
+//	if err != nil {
+//		return goerrredis.New(err, "get", key)
+//	}
+func New(err error, cmd, key string) error {
+	if err == nil {
+		return nil
+	}
+	return wrap(err, cmd, key)
+}
+
+// wrap implements New and FromCmd, attributing the layer to their caller.
+func wrap(err error, cmd, key string) error {
+	cmd = strings.ToUpper(cmd)
+	fields := map[string]any{CommandField: cmd}
+	if key != "" {
+		fields[KeyField] = formatKey(key)
+	}
+	if prefix, _ := serverError(err); prefix != "" {
+		fields[ErrorPrefixField] = prefix
+	}
+	kind, retryable := Classify(err)
+	if retryable {
+		fields[RetryableField] = true
+	}
+
+	e := goerr.WithFields(goerr.NewSkip(2, err, "redis %s failed", cmd), fields)
+	if kind != "" {
+		e = goerr.WithKind(e, kind)
+	}
+	return e
+}
+
+// Cmder is the part of redis.Cmder used by FromCmd.
+type Cmder interface {
+	Name() string
+	Args() []interface{}
+	Err() error
+}
+
+// FromCmd is New with the error, the name and the key of a go-redis command.
+// The key is the first argument after the name, if it is a string. It
+// returns nil if the command did not fail.
+//
+//	if err := goerrredis.FromCmd(rdb.Set(ctx, key, value, time.Hour)); err != nil {
+//		return err
+//	}
+func FromCmd(c Cmder) error {
+	err := c.Err()
+	if err == nil {
+		return nil
+	}
+
+	key := ""
+	if args := c.Args(); len(args) > 1 {
+		key, _ = args[1].(string)
+	}
+	return wrap(err, c.Name(), key)
+}
+
+// Retryable reports whether err wraps a go-redis error after which the
+// command can be retried, see Classify.
+func Retryable(err error) bool {
+	_, retryable := Classify(err)
+	return retryable
+}
+
+// isNil reports whether the chain of err has redis.Nil.
+func isNil(err error) bool {
+	for depth := 0; err != nil && depth < goerr.MaxChainDepth; depth++ {
+		if err.Error() == nilMessage && reflect.TypeOf(err).Kind() == reflect.String {
+			return true
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
+
+// serverError returns the prefix of the first error replied by the server in
+// the chain of err, like WRONGTYPE, and reports whether it is not retryable.
+// The prefix is empty if there is no such error.
+func serverError(err error) (prefix string, permanent bool) {
+	for depth := 0; err != nil && depth < goerr.MaxChainDepth; depth++ {
+		t := reflect.TypeOf(err)
+		if t.Name() == "RedisError" && t.Kind() == reflect.String && err.Error() != nilMessage {
+			prefix, _, _ = strings.Cut(err.Error(), " ")
+			return prefix, !retryablePrefixes[prefix]
+		}
+		err = errors.Unwrap(err)
+	}
+	return "", false
+}
+
+// formatKey returns key in the configured KeyMode.
+func formatKey(key string) string {
+	switch KeyMode(keyMode.Load()) {
+	case KeyFull:
+		return key
+	case KeyRedacted:
+		return goerr.Redacted
+	}
+
+	segments := strings.Split(key, ":")
+	for i, s := range segments {
+		if strings.ContainsAny(s, "0123456789") {
+			segments[i] = "*"
+		}
+	}
+	return strings.Join(segments, ":")
+}
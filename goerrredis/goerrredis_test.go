@@ -0,0 +1,129 @@
+package goerrredis_test
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/goerrredis"
+)
+
+// RedisError mimics proto.RedisError, the type of redis.Nil and of the errors
+// replied by the server.
+type RedisError string
+
+func (e RedisError) Error() string { return string(e) }
+
+const Nil = RedisError("redis: nil")
+
+// cmd mimics a redis.Cmder.
+type cmd struct {
+	args []interface{}
+	err  error
+}
+
+func (c cmd) Name() string        { return c.args[0].(string) }
+func (c cmd) Args() []interface{} { return c.args }
+func (c cmd) Err() error          { return c.err }
+
+func TestNew(t *testing.T) {
+	err := goerrredis.New(Nil, "get", "user:42:session")
+
+	if got := err.Error(); got != "redis GET failed" {
+		t.Errorf("Want: redis GET failed, Got: %s", got)
+	}
+	if got := goerr.Code(err); got != http.StatusNotFound {
+		t.Errorf("Want: %d, Got: %d", http.StatusNotFound, got)
+	}
+	if !errors.Is(err, Nil) {
+		t.Errorf("expected errors.Is to find redis.Nil")
+	}
+	fields := goerr.Fields(err)
+	if fields[goerrredis.CommandField] != "GET" || fields[goerrredis.KeyField] != "user:*:session" {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+	if _, ok := fields[goerrredis.RetryableField]; ok {
+		t.Errorf("expected redis.Nil not to be retryable, Got: %v", fields)
+	}
+	if !strings.Contains(goerr.Stack(err), "goerrredis_test.go") {
+		t.Errorf("expected the layer to point to the caller, Got: %s", goerr.Stack(err))
+	}
+	if goerrredis.New(nil, "get", "key") != nil {
+		t.Errorf("expected nil for nil")
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		err       error
+		kind      goerr.Kind
+		retryable bool
+	}{
+		{Nil, goerr.KindNotFound, false},
+		{fmt.Errorf("get: %w", Nil), goerr.KindNotFound, false},
+		{RedisError("LOADING Redis is loading the dataset in memory"), goerr.KindUnavailable, true},
+		{RedisError("READONLY You can't write against a read only replica."), goerr.KindUnavailable, true},
+		{RedisError("WRONGTYPE Operation against a key holding the wrong kind of value"), goerr.KindInternal, false},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, goerr.KindUnavailable, true},
+		{errors.New("redis: connection pool timeout"), goerr.KindUnavailable, true},
+		{errors.New("redis: nil"), "", false},
+		{errors.New("other"), "", false},
+	}
+	for _, test := range tests {
+		kind, retryable := goerrredis.Classify(test.err)
+		if kind != test.kind || retryable != test.retryable {
+			t.Errorf("%v. Want: %s %v, Got: %s %v", test.err, test.kind, test.retryable, kind, retryable)
+		}
+	}
+}
+
+func TestNewServerError(t *testing.T) {
+	err := goerrredis.New(RedisError("READONLY You can't write against a read only replica."), "set", "")
+
+	if got := goerr.Code(err); got != http.StatusServiceUnavailable {
+		t.Errorf("Want: %d, Got: %d", http.StatusServiceUnavailable, got)
+	}
+	fields := goerr.Fields(err)
+	if fields[goerrredis.ErrorPrefixField] != "READONLY" || fields[goerrredis.RetryableField] != true {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+	if _, ok := fields[goerrredis.KeyField]; ok {
+		t.Errorf("expected no key, Got: %v", fields)
+	}
+	if !goerrredis.Retryable(err) {
+		t.Errorf("expected err to be retryable")
+	}
+}
+
+func TestFromCmd(t *testing.T) {
+	if goerrredis.FromCmd(cmd{args: []interface{}{"get", "k"}}) != nil {
+		t.Errorf("expected nil for a command that did not fail")
+	}
+
+	err := goerrredis.FromCmd(cmd{args: []interface{}{"hget", "order:9f2c", "status"}, err: Nil})
+	fields := goerr.Fields(err)
+	if fields[goerrredis.CommandField] != "HGET" || fields[goerrredis.KeyField] != "order:*" {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+	if !strings.Contains(goerr.Stack(err), "goerrredis_test.go") {
+		t.Errorf("expected the layer to point to the caller, Got: %s", goerr.Stack(err))
+	}
+}
+
+func TestKeyMode(t *testing.T) {
+	defer goerrredis.SetKeyMode(goerrredis.KeyPattern)
+
+	goerrredis.SetKeyMode(goerrredis.KeyFull)
+	if got := goerr.Fields(goerrredis.New(Nil, "get", "user:42"))[goerrredis.KeyField]; got != "user:42" {
+		t.Errorf("Want: user:42, Got: %v", got)
+	}
+
+	goerrredis.SetKeyMode(goerrredis.KeyRedacted)
+	if got := goerr.Fields(goerrredis.New(Nil, "get", "user:42"))[goerrredis.KeyField]; got != goerr.Redacted {
+		t.Errorf("Want: %s, Got: %v", goerr.Redacted, got)
+	}
+}
@@ -0,0 +1,199 @@
+// Package goerrsql enriches goerr errors wrapping database errors with the
+// details reported by the driver, and maps common classes of failures to
+// kinds and codes.
+//
+// Drivers are recognized without importing them: Postgres errors by their
+// SQLState method (github.com/jackc/pgx and github.com/lib/pq) and MySQL
+// errors by their Number field (github.com/go-sql-driver/mysql). Other drivers
+// can be supported with RegisterExtractor.
+package goerrsql
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+
+	"github.com/angel-one/goerr"
+)
+
+// Fields set by Enrich.
+const (
+	SQLStateField   = "sqlstate"
+	ErrNumberField  = "mysql_errno"
+	ConstraintField = "constraint"
+	TableField      = "table"
+	ColumnField     = "column"
+)
+
+// Details are the specifics of a database error reported by the driver.
+type Details struct {
+	// SQLState is the SQLSTATE of the error, e.g. 23505.
+	SQLState string
+	// Number is the MySQL error number, e.g. 1062.
+	Number int
+	// Constraint, Table and Column name the violated constraint and the
+	// affected table and column, if the driver reports them.
+	Constraint string
+	Table      string
+	Column     string
+}
+
+// An Extractor returns the details of err, which is a single error of a chain,
+// and reports whether it is a database error it knows.
+type Extractor func(err error) (Details, bool)
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   = []Extractor{extractPostgres, extractMySQL}
+)
+
+// RegisterExtractor adds an extractor for the errors of another driver.
+// Extractors are tried in the reverse order of their registration, before the
+// built in ones.
+func RegisterExtractor(x Extractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors = append([]Extractor{x}, extractors...)
+}
+
+// Extract returns the details of the first database error in the chain of err
+// and reports whether there was one.
+func Extract(err error) (Details, bool) {
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+
+	for depth := 0; err != nil && depth < goerr.MaxChainDepth; depth++ {
+		for _, x := range extractors {
+			if d, ok := x(err); ok {
+				return d, true
+			}
+		}
+		err = errors.Unwrap(err)
+	}
+	return Details{}, false
+}
+
+// Enrich returns err with the details of the database error it wraps set as
+// fields, and the kind of the error class set, on its outermost layer. The
+// kind implies the code, e.g. 409 for a duplicate key, unless a code was
+// given explicitly. Errors not wrapping a database error are returned as they
+// are.
+//
+// It is meant to be applied to the layer wrapping the database error, so that
+// the layer keeps pointing to the caller:
+//
+//	if err != nil {
+//		return goerrsql.Enrich(goerr.New(err, "insert order failed"))
+//	}
+func Enrich(err error) error {
+	d, ok := Extract(err)
+	if !ok {
+		return err
+	}
+
+	err = goerr.WithFields(err, d.fields())
+	if kind := Classify(d); kind != "" {
+		err = goerr.WithKind(err, kind)
+	}
+	return err
+}
+
+// Classify returns the kind of common classes of database errors: duplicate
+// keys are conflicts, foreign key, not null and check violations are
+// unprocessable, serialization failures and deadlocks are conflicts that can
+// be retried, and lock timeouts are timeouts. Other errors have no kind.
+func Classify(d Details) goerr.Kind {
+	switch d.SQLState {
+	case "23505", "40001", "40P01":
+		return goerr.KindConflict
+	case "23503", "23502", "23514":
+		return goerr.KindUnprocessable
+	case "55P03", "57014":
+		return goerr.KindTimeout
+	}
+
+	switch d.Number {
+	case 1062, 1213:
+		return goerr.KindConflict
+	case 1048, 1451, 1452, 3819:
+		return goerr.KindUnprocessable
+	case 1205:
+		return goerr.KindTimeout
+	}
+	return ""
+}
+
+func (d Details) fields() map[string]any {
+	fields := map[string]any{}
+	if d.SQLState != "" {
+		fields[SQLStateField] = d.SQLState
+	}
+	if d.Number != 0 {
+		fields[ErrNumberField] = d.Number
+	}
+	if d.Constraint != "" {
+		fields[ConstraintField] = d.Constraint
+	}
+	if d.Table != "" {
+		fields[TableField] = d.Table
+	}
+	if d.Column != "" {
+		fields[ColumnField] = d.Column
+	}
+	return fields
+}
+
+// extractPostgres recognizes *pgconn.PgError and *pq.Error.
+func extractPostgres(err error) (Details, bool) {
+	s, ok := err.(interface{ SQLState() string })
+	if !ok {
+		return Details{}, false
+	}
+
+	v := structOf(err)
+	return Details{
+		SQLState:   s.SQLState(),
+		Constraint: stringField(v, "ConstraintName", "Constraint"),
+		Table:      stringField(v, "TableName", "Table"),
+		Column:     stringField(v, "ColumnName", "Column"),
+	}, true
+}
+
+// extractMySQL recognizes *mysql.MySQLError.
+func extractMySQL(err error) (Details, bool) {
+	v := structOf(err)
+	if !v.IsValid() || v.Type().Name() != "MySQLError" {
+		return Details{}, false
+	}
+
+	number := v.FieldByName("Number")
+	if !number.IsValid() || number.Kind() != reflect.Uint16 {
+		return Details{}, false
+	}
+	return Details{Number: int(number.Uint())}, true
+}
+
+// structOf returns the struct err points to, or an invalid value.
+func structOf(err error) reflect.Value {
+	v := reflect.ValueOf(err)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return v
+}
+
+// stringField returns the value of the first of the named string fields of v.
+func stringField(v reflect.Value, names ...string) string {
+	if !v.IsValid() {
+		return ""
+	}
+	for _, name := range names {
+		if f := v.FieldByName(name); f.IsValid() && f.Kind() == reflect.String {
+			return f.String()
+		}
+	}
+	return ""
+}
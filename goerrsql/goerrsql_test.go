@@ -0,0 +1,97 @@
+package goerrsql_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/goerrsql"
+)
+
+// PgError mimics *pgconn.PgError.
+type PgError struct {
+	Code           string
+	Message        string
+	ConstraintName string
+	TableName      string
+	ColumnName     string
+}
+
+func (e *PgError) Error() string    { return e.Message }
+func (e *PgError) SQLState() string { return e.Code }
+
+// MySQLError mimics *mysql.MySQLError.
+type MySQLError struct {
+	Number  uint16
+	Message string
+}
+
+func (e *MySQLError) Error() string { return fmt.Sprintf("Error %d: %s", e.Number, e.Message) }
+
+func TestEnrichPostgres(t *testing.T) {
+	dbErr := &PgError{Code: "23505", Message: "duplicate key value", ConstraintName: "orders_pkey", TableName: "orders"}
+
+	err := goerrsql.Enrich(goerr.New(fmt.Errorf("exec: %w", dbErr), "insert order failed"))
+
+	if got := goerr.Code(err); got != http.StatusConflict {
+		t.Errorf("Want: %d, Got: %d", http.StatusConflict, got)
+	}
+	if !errors.Is(err, goerr.KindConflict) {
+		t.Errorf("expected err to be a conflict")
+	}
+
+	fields := goerr.Fields(err)
+	if fields[goerrsql.SQLStateField] != "23505" || fields[goerrsql.ConstraintField] != "orders_pkey" || fields[goerrsql.TableField] != "orders" {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+	if _, ok := fields[goerrsql.ColumnField]; ok {
+		t.Errorf("unexpected empty column field: %v", fields)
+	}
+}
+
+func TestEnrichMySQL(t *testing.T) {
+	err := goerrsql.Enrich(goerr.New(&MySQLError{Number: 1452, Message: "foreign key constraint fails"}, "insert item failed"))
+
+	if got := goerr.Code(err); got != http.StatusUnprocessableEntity {
+		t.Errorf("Want: %d, Got: %d", http.StatusUnprocessableEntity, got)
+	}
+	if got := goerr.Fields(err)[goerrsql.ErrNumberField]; got != 1452 {
+		t.Errorf("Want: %d, Got: %v", 1452, got)
+	}
+}
+
+func TestEnrichExplicitCode(t *testing.T) {
+	dbErr := &PgError{Code: "23505", Message: "duplicate key value"}
+
+	err := goerrsql.Enrich(goerr.New(dbErr, http.StatusOK, "already registered"))
+
+	if got := goerr.Code(err); got != http.StatusOK {
+		t.Errorf("Want: %d, Got: %d", http.StatusOK, got)
+	}
+}
+
+func TestEnrichOtherErrors(t *testing.T) {
+	err := goerr.New(errors.New("connection refused"), "insert order failed")
+
+	if got := goerrsql.Enrich(err); got != err {
+		t.Errorf("expected err to be returned unchanged")
+	}
+}
+
+func TestRegisterExtractor(t *testing.T) {
+	type sqliteError struct{ error }
+	goerrsql.RegisterExtractor(func(err error) (goerrsql.Details, bool) {
+		if _, ok := err.(sqliteError); ok {
+			return goerrsql.Details{SQLState: "23505"}, true
+		}
+		return goerrsql.Details{}, false
+	})
+
+	err := goerrsql.Enrich(goerr.New(sqliteError{errors.New("UNIQUE constraint failed")}, "insert failed"))
+
+	if got := goerr.Code(err); got != http.StatusConflict {
+		t.Errorf("Want: %d, Got: %d", http.StatusConflict, got)
+	}
+}
@@ -0,0 +1,48 @@
+package goerrtest
+
+import (
+	"reflect"
+
+	"github.com/angel-one/goerr"
+)
+
+// Layer is a comparable representation of a layer of an error chain. Like
+// goerr.EqualChains, it leaves out the file, line and function the layer was
+// created at, so that tests comparing chains with
+// reflect.DeepEqual or cmp.Diff do not break whenever code shifts lines.
+type Layer struct {
+	Message  string
+	Code     int
+	Kind     goerr.Kind
+	Severity goerr.SeverityLevel
+	Fields   map[string]any
+	// Type is the type of an error that is not a goerr layer, e.g.
+	// *errors.errorString, and empty for goerr layers.
+	Type string
+}
+
+// Chain returns the layers of err, outermost first, as visited by
+// goerr.Walk. Comparing the chains of two errors gives a readable diff of
+// the layers that differ:
+//
+//	if diff := cmp.Diff(goerrtest.Chain(want), goerrtest.Chain(err)); diff != "" {
+//		t.Errorf("unexpected error (-want +got):\n%s", diff)
+//	}
+func Chain(err error) []Layer {
+	var chain []Layer
+	goerr.Walk(err, func(l goerr.Layer) bool {
+		layer := Layer{
+			Message:  l.Message,
+			Code:     l.Code,
+			Kind:     l.Kind,
+			Severity: l.Severity,
+			Fields:   l.Fields,
+		}
+		if _, ok := l.Err.(*goerr.Error); !ok {
+			layer.Type = reflect.TypeOf(l.Err).String()
+		}
+		chain = append(chain, layer)
+		return true
+	})
+	return chain
+}
@@ -0,0 +1,33 @@
+package goerrtest_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/goerrtest"
+)
+
+func TestChain(t *testing.T) {
+	err := goerr.WithField(goerr.New(errors.New("no rows"), http.StatusNotFound, "order not found"), "order", 42)
+
+	want := []goerrtest.Layer{
+		{Message: "order not found", Code: http.StatusNotFound, Fields: map[string]any{"order": 42}},
+		{Message: "no rows", Type: "*errors.errorString"},
+	}
+	if got := goerrtest.Chain(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Want: %+v, Got: %+v", want, got)
+	}
+
+	// chains created at different lines are equal
+	other := goerr.WithField(goerr.New(errors.New("no rows"), http.StatusNotFound, "order not found"), "order", 42)
+	if !reflect.DeepEqual(goerrtest.Chain(err), goerrtest.Chain(other)) {
+		t.Errorf("expected the chains to be equal")
+	}
+
+	if got := goerrtest.Chain(nil); got != nil {
+		t.Errorf("Want: nil, Got: %v", got)
+	}
+}
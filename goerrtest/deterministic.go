@@ -0,0 +1,25 @@
+package goerrtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/angel-one/goerr"
+)
+
+// Deterministic makes every layer created until the end of the test appear
+// to be created at the given time, with the given call stack, so rendered
+// stacks can be compared with golden files. The system clock and the capture
+// of the real call stack are restored when the test finishes.
+//
+// Deterministic changes settings of the goerr package, so it must not be used
+// by parallel tests.
+func Deterministic(t testing.TB, at time.Time, frames ...goerr.StackFrame) {
+	t.Helper()
+	goerr.SetClock(goerr.ClockFunc(func() time.Time { return at }))
+	goerr.SetStackCapturer(goerr.FixedStack(frames...))
+	t.Cleanup(func() {
+		goerr.SetClock(nil)
+		goerr.SetStackCapturer(nil)
+	})
+}
@@ -0,0 +1,89 @@
+package goerrtest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/angel-one/goerr"
+)
+
+// A FrameMatcher matches one layer of an error chain. Only the properties
+// that were set on it are compared.
+type FrameMatcher struct {
+	message  *string
+	code     *int
+	file     *string
+	line     *int
+	function *string
+}
+
+// Frame returns a FrameMatcher that matches any layer.
+func Frame() *FrameMatcher {
+	return &FrameMatcher{}
+}
+
+// Message makes m match layers with the given message.
+func (m *FrameMatcher) Message(message string) *FrameMatcher {
+	m.message = &message
+	return m
+}
+
+// Code makes m match layers with the given code set on the layer itself.
+func (m *FrameMatcher) Code(code int) *FrameMatcher {
+	m.code = &code
+	return m
+}
+
+// File makes m match layers created in a file whose path ends with suffix.
+func (m *FrameMatcher) File(suffix string) *FrameMatcher {
+	m.file = &suffix
+	return m
+}
+
+// Line makes m match layers created at the given line.
+func (m *FrameMatcher) Line(line int) *FrameMatcher {
+	m.line = &line
+	return m
+}
+
+// Func makes m match layers created in a function whose import path
+// qualified name ends with suffix, like "samplesrc.Service".
+func (m *FrameMatcher) Func(suffix string) *FrameMatcher {
+	m.function = &suffix
+	return m
+}
+
+// Match reports whether err, which is a single layer, matches m.
+func (m *FrameMatcher) Match(err error) bool {
+	return m.mismatch(err) == ""
+}
+
+// mismatch describes why err does not match m, or returns an empty string if
+// it does.
+func (m *FrameMatcher) mismatch(err error) string {
+	if m.message != nil && err.Error() != *m.message {
+		return fmt.Sprintf("message. Want: %q; Got: %q", *m.message, err.Error())
+	}
+
+	e, ok := err.(*goerr.Error)
+	if !ok {
+		if m.code != nil || m.file != nil || m.line != nil || m.function != nil {
+			return fmt.Sprintf("not a goerr: %q", err.Error())
+		}
+		return ""
+	}
+
+	if m.code != nil && e.Code() != *m.code {
+		return fmt.Sprintf("code. Want: %d; Got: %d", *m.code, e.Code())
+	}
+	if m.file != nil && !strings.HasSuffix(e.File(), *m.file) {
+		return fmt.Sprintf("file. Want suffix: %s; Got: %s", *m.file, e.File())
+	}
+	if m.line != nil && e.Line() != *m.line {
+		return fmt.Sprintf("line. Want: %d; Got: %d", *m.line, e.Line())
+	}
+	if m.function != nil && !strings.HasSuffix(e.Func(), *m.function) {
+		return fmt.Sprintf("func. Want suffix: %s; Got: %s", *m.function, e.Func())
+	}
+	return ""
+}
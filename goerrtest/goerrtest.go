@@ -0,0 +1,82 @@
+// Package goerrtest provides test assertions for goerr errors, so tests can
+// check codes, messages and frames without matching regular expressions
+// against the output of goerr.Stack.
+package goerrtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+// AssertCode reports an error if the code of err, as returned by goerr.Code,
+// is not want.
+func AssertCode(t testing.TB, err error, want int) bool {
+	t.Helper()
+	if got := goerr.Code(err); got != want {
+		t.Errorf("code. Want: %d; Got: %d", want, got)
+		return false
+	}
+	return true
+}
+
+// AssertStackContains reports an error if the rendered stack of err does not
+// contain substr.
+func AssertStackContains(t testing.TB, err error, substr string) bool {
+	t.Helper()
+	if stack := goerr.Stack(err); !strings.Contains(stack, substr) {
+		t.Errorf("stack do not contain %q: %s", substr, stack)
+		return false
+	}
+	return true
+}
+
+// AssertFrameCount reports an error if err does not have want layers. Each
+// goerr layer counts as one, and so does the error wrapped by the innermost
+// goerr layer, if any.
+func AssertFrameCount(t testing.TB, err error, want int) bool {
+	t.Helper()
+	if got := len(layers(err)); got != want {
+		t.Errorf("nr. of frames. Want: %d; Got: %d\n%s", want, got, goerr.Stack(err))
+		return false
+	}
+	return true
+}
+
+// AssertFrames reports an error unless the layers of err, outermost first,
+// match the given matchers. Layers beyond the last matcher are not checked.
+func AssertFrames(t testing.TB, err error, matchers ...*FrameMatcher) bool {
+	t.Helper()
+
+	ls := layers(err)
+	if len(ls) < len(matchers) {
+		t.Errorf("nr. of frames. Want at least: %d; Got: %d\n%s", len(matchers), len(ls), goerr.Stack(err))
+		return false
+	}
+
+	ok := true
+	for i, m := range matchers {
+		if mismatch := m.mismatch(ls[i]); mismatch != "" {
+			t.Errorf("frame %d: %s\n%s", i, mismatch, goerr.Stack(err))
+			ok = false
+		}
+	}
+	return ok
+}
+
+// layers returns the goerr layers of err, outermost first, followed by the
+// error wrapped by the innermost goerr layer, if any.
+func layers(err error) []error {
+	var result []error
+	for err != nil && len(result) < goerr.MaxChainDepth {
+		result = append(result, err)
+
+		e, ok := err.(*goerr.Error)
+		if !ok {
+			break
+		}
+		err = e.Unwrap()
+	}
+	return result
+}
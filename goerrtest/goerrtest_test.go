@@ -0,0 +1,92 @@
+package goerrtest_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/goerrtest"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+// recorder records the failures reported by the assertions under test.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertions(t *testing.T) {
+	err := goerr.New(samplesrc.Service(), 409, "controller failed")
+
+	goerrtest.AssertCode(t, err, 409)
+	goerrtest.AssertStackContains(t, err, "service failed")
+	goerrtest.AssertFrameCount(t, err, 3)
+	goerrtest.AssertFrames(t, err,
+		goerrtest.Frame().Message("controller failed").Code(409).Func("goerrtest_test.TestAssertions"),
+		goerrtest.Frame().Message("service failed").File("samplesrc/samples.go").Line(20).Func("samplesrc.Service"),
+		goerrtest.Frame().Func("samplesrc.Repository"),
+	)
+}
+
+func TestAssertionFailures(t *testing.T) {
+	err := goerr.New(errors.New("db down"), "service failed")
+
+	r := &recorder{TB: t}
+	if goerrtest.AssertCode(r, err, 500) {
+		t.Errorf("AssertCode should fail")
+	}
+	if goerrtest.AssertStackContains(r, err, "controller failed") {
+		t.Errorf("AssertStackContains should fail")
+	}
+	if goerrtest.AssertFrameCount(r, err, 3) {
+		t.Errorf("AssertFrameCount should fail")
+	}
+	if goerrtest.AssertFrames(r, err, goerrtest.Frame(), goerrtest.Frame().Line(10)) {
+		t.Errorf("AssertFrames should fail for a frame that is not a goerr")
+	}
+	if len(r.failures) != 4 {
+		t.Errorf("Nr. of failures. Want: %d; Got: %d", 4, len(r.failures))
+	}
+}
+
+func TestFrameMatcher(t *testing.T) {
+	err := samplesrc.Repository()
+
+	if !goerrtest.Frame().Message("error from database").Line(27).Match(err) {
+		t.Errorf("expected the frame to match")
+	}
+	if goerrtest.Frame().Code(500).Match(err) {
+		t.Errorf("expected the frame not to match")
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	t.Run("golden", func(t *testing.T) {
+		goerrtest.Deterministic(t, at,
+			goerr.StackFrame{File: "/src/app/handler.go", LineNumber: 42, Package: "example.com/app", Name: "Handle"})
+
+		err := goerr.New(goerr.New(nil, "timeout"), "retry failed")
+		want := "\nretry failed [/src/app/handler.go:42 (app.Handle)]\n\ttimeout [/src/app/handler.go:42 (app.Handle)]"
+		if got := goerr.Stack(err); got != want {
+			t.Errorf("Want: %q, Got: %q", want, got)
+		}
+		if got := goerr.FirstOccurred(err); !got.Equal(at) {
+			t.Errorf("Want: %v, Got: %v", at, got)
+		}
+	})
+
+	err := goerr.New(nil, "failed")
+	goerrtest.AssertFrames(t, err, goerrtest.Frame().Func("goerrtest_test.TestDeterministic"))
+	if got := goerr.FirstOccurred(err); got.Equal(at) {
+		t.Errorf("expected the system clock to be restored, Got: %v", got)
+	}
+}
@@ -0,0 +1,193 @@
+// Package goerrwebhook provides a goerr.Sink posting critical errors to a
+// Slack or Microsoft Teams incoming webhook, for on-call channels. Every
+// error is summarized with its message, code, fingerprint, the locations of
+// its first layers and an optional link to its trace, and an error is posted
+// at most once per interval for each fingerprint, so a failure repeating in
+// a hot path does not flood the channel.
+//
+//	sink := goerrwebhook.New(goerrwebhook.Options{URL: os.Getenv("SLACK_WEBHOOK_URL")})
+//	goerr.SetReporter(goerr.NewReporter(goerr.ReporterOptions{}, sink))
+package goerrwebhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/angel-one/goerr"
+)
+
+// Defaults of Options.
+const (
+	DefaultInterval = 10 * time.Minute
+	DefaultFrames   = 5
+	DefaultTimeout  = 10 * time.Second
+)
+
+// Options configure a Sink.
+type Options struct {
+	// URL is the URL of the incoming webhook. Slack and Teams both accept
+	// the {"text": ...} payload posted by the sink.
+	URL string
+	// Client posts the payloads, by default a client with a timeout of
+	// DefaultTimeout.
+	Client *http.Client
+	// Filter selects the errors to post, by default the ones with
+	// goerr.SeverityCritical.
+	Filter func(err error) bool
+	// Interval is the shortest time between two posts of errors with the
+	// same fingerprint, by default DefaultInterval.
+	Interval time.Duration
+	// Frames is the number of layer locations in a summary, by default
+	// DefaultFrames.
+	Frames int
+	// TraceURL returns the URL of the trace of an error, linked from its
+	// summary, or an empty string. It is not set by default.
+	TraceURL func(err error) string
+}
+
+// Sink posts summaries of errors to a webhook. It is safe for concurrent
+// use.
+type Sink struct {
+	opts Options
+
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+// New returns a sink configured with opts.
+func New(opts Options) *Sink {
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: DefaultTimeout}
+	}
+	if opts.Filter == nil {
+		opts.Filter = func(err error) bool {
+			return goerr.Severity(err) == goerr.SeverityCritical
+		}
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.Frames <= 0 {
+		opts.Frames = DefaultFrames
+	}
+	return &Sink{opts: opts, last: map[string]time.Time{}}
+}
+
+// Send posts the summaries of the errors of errs selected by the filter and
+// not posted within the interval, in a single message. Nothing is posted if
+// there are none. Only a successful post counts as posted, so the errors of
+// a failed one are posted again with the next batch.
+func (s *Sink) Send(errs []error) error {
+	var summaries, fps []string
+	for _, err := range errs {
+		if err == nil || !s.opts.Filter(err) {
+			continue
+		}
+		fp := goerr.Fingerprint(err)
+		if !s.due(fp) || contains(fps, fp) {
+			continue
+		}
+		fps = append(fps, fp)
+		summaries = append(summaries, s.summary(err))
+	}
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	body, _ := json.Marshal(map[string]string{"text": strings.Join(summaries, "\n\n")})
+	resp, err := s.opts.Client.Post(s.opts.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// the URL in the error of the client holds the secret of the webhook
+		if uerr, ok := err.(*url.Error); ok {
+			err = uerr.Err
+		}
+		return goerr.New(err, "post to webhook failed")
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return goerr.New(nil, resp.StatusCode, "post to webhook failed: %s", resp.Status)
+	}
+	s.posted(fps)
+	return nil
+}
+
+// due reports whether an error with the fingerprint fp may be posted.
+func (s *Sink) due(fp string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.last[fp]
+	return !ok || time.Since(t) >= s.opts.Interval
+}
+
+// posted records that errors with the fingerprints fps were posted.
+func (s *Sink) posted(fps []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if len(s.last)+len(fps) > 1024 {
+		for k, t := range s.last {
+			if now.Sub(t) >= s.opts.Interval {
+				delete(s.last, k)
+			}
+		}
+	}
+	for _, fp := range fps {
+		s.last[fp] = now
+	}
+}
+
+// contains reports whether list contains s.
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// summary returns the text posted for err.
+func (s *Sink) summary(err error) string {
+	var b strings.Builder
+	frames := 0
+	goerr.Walk(err, func(l goerr.Layer) bool {
+		if l.Depth == 0 {
+			b.WriteByte('[')
+			b.WriteString(goerr.Severity(err).String())
+			b.WriteString("] ")
+			b.WriteString(l.Message)
+			b.WriteString("\ncode: ")
+			b.WriteString(strconv.Itoa(goerr.Code(err)))
+			b.WriteString(", fingerprint: ")
+			b.WriteString(goerr.Fingerprint(err))
+		}
+		if l.File != "" && frames < s.opts.Frames {
+			b.WriteString("\nat ")
+			b.WriteString(l.File)
+			b.WriteByte(':')
+			b.WriteString(strconv.Itoa(l.Line))
+			b.WriteString(" (")
+			b.WriteString(l.Function)
+			b.WriteByte(')')
+			frames++
+		}
+		return true
+	})
+	if s.opts.TraceURL != nil {
+		if u := s.opts.TraceURL(err); u != "" {
+			b.WriteString("\ntrace: ")
+			b.WriteString(u)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package goerrwebhook_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/goerrwebhook"
+)
+
+// webhook returns a server recording the texts posted to it.
+func webhook(t *testing.T, status int) (*httptest.Server, *[]string) {
+	var texts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct{ Text string }
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		texts = append(texts, payload.Text)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &texts
+}
+
+func TestSend(t *testing.T) {
+	srv, texts := webhook(t, http.StatusOK)
+	sink := goerrwebhook.New(goerrwebhook.Options{
+		URL:      srv.URL,
+		Frames:   1,
+		TraceURL: func(err error) string { return "https://traces.example.com/abc" },
+	})
+
+	critical := goerr.WithSeverity(goerr.New(goerr.New(nil, "disk full"), 500, "write ledger failed"), goerr.SeverityCritical)
+	minor := goerr.New(nil, 400, "invalid input")
+	if err := sink.Send([]error{critical, minor}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*texts) != 1 {
+		t.Fatalf("posts. Want: 1, Got: %d", len(*texts))
+	}
+	text := (*texts)[0]
+	for _, want := range []string{"[critical] write ledger failed", "code: 500", "fingerprint: " + goerr.Fingerprint(critical), "goerrwebhook_test.go:", "trace: https://traces.example.com/abc"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Want: %s in %s", want, text)
+		}
+	}
+	if strings.Count(text, "\nat ") != 1 {
+		t.Errorf("expected one frame, Got: %s", text)
+	}
+	if strings.Contains(text, "invalid input") {
+		t.Errorf("expected errors that are not critical to be left out, Got: %s", text)
+	}
+
+	if err := sink.Send([]error{critical}); err != nil {
+		t.Fatal(err)
+	}
+	if len(*texts) != 1 {
+		t.Errorf("expected the fingerprint to be rate limited, Got: %d posts", len(*texts))
+	}
+}
+
+func TestSendFailure(t *testing.T) {
+	srv, _ := webhook(t, http.StatusForbidden)
+	sink := goerrwebhook.New(goerrwebhook.Options{URL: srv.URL + "/services/secret", Filter: func(error) bool { return true }})
+
+	err := sink.Send([]error{errors.New("boom")})
+	if want, got := http.StatusForbidden, goerr.Code(err); got != want {
+		t.Errorf("Want: %d, Got: %d", want, got)
+	}
+	if strings.Contains(goerr.Stack(err), "secret") {
+		t.Errorf("expected the URL to be left out, Got: %s", goerr.Stack(err))
+	}
+}
+
+func TestSendRetriesFailedPosts(t *testing.T) {
+	statuses := []int{http.StatusServiceUnavailable, http.StatusOK, http.StatusOK}
+	posts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statuses[posts])
+		posts++
+	}))
+	t.Cleanup(srv.Close)
+	sink := goerrwebhook.New(goerrwebhook.Options{URL: srv.URL})
+
+	critical := goerr.WithSeverity(goerr.New(nil, 500, "write ledger failed"), goerr.SeverityCritical)
+	if err := sink.Send([]error{critical}); err == nil {
+		t.Fatal("expected the first post to fail")
+	}
+	if err := sink.Send([]error{critical, critical}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Send([]error{critical}); err != nil {
+		t.Fatal(err)
+	}
+	if posts != 2 {
+		t.Errorf("expected the error to be posted again after a failure and then rate limited, Got: %d posts", posts)
+	}
+}
@@ -0,0 +1,16 @@
+module github.com/angel-one/goerr/goerrzerolog
+
+go 1.19
+
+require (
+	github.com/angel-one/goerr v0.0.0
+	github.com/rs/zerolog v1.33.0
+)
+
+require (
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.19 // indirect
+	golang.org/x/sys v0.18.0 // indirect
+)
+
+replace github.com/angel-one/goerr => ../
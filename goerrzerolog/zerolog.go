@@ -0,0 +1,75 @@
+// Package goerrzerolog logs goerr errors with zerolog, rendering the whole
+// chain instead of the message of the outermost layer only.
+package goerrzerolog
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/angel-one/goerr"
+	"github.com/rs/zerolog"
+)
+
+// MarshalError renders goerr errors as a JSON array of their layers, as
+// returned by goerr.ListStacks. Other errors are returned unchanged. It can
+// be installed as the zerolog.ErrorMarshalFunc:
+//
+//	zerolog.ErrorMarshalFunc = goerrzerolog.MarshalError
+//
+// With goerr.SuppressRepeats, errors repeating within the window are
+// rendered as a single counter line instead, like
+// "controller failed (repeat 12, fingerprint 9f86d081884c7d65)". In pretty
+// mode (see goerr.SetPretty) errors are rendered by goerr.Pretty, for the
+// zerolog.ConsoleWriter in a terminal.
+func MarshalError(err error) interface{} {
+	var e *goerr.Error
+	if !errors.As(err, &e) {
+		return err
+	}
+	if count, suppress := goerr.Repeated(err); suppress {
+		return repeatLine(err, count)
+	}
+	if goerr.PrettyMode() {
+		return "\n" + goerr.Pretty(err)
+	}
+	return goerr.ListStacks(err)
+}
+
+// repeatLine renders the counter line of a suppressed error.
+func repeatLine(err error, count int) string {
+	return message(err) + " (repeat " + strconv.Itoa(count) + ", fingerprint " + goerr.Fingerprint(err) + ")"
+}
+
+// message returns the message of err with the redactors applied.
+func message(err error) string {
+	return goerr.ListEntries(err)[0].Message
+}
+
+// Dict returns a dictionary with the message, code, layers and fields of err,
+// to be added to an event with
+//
+//	log.Error().Dict("error", goerrzerolog.Dict(err)).Msg("request failed")
+//
+// With goerr.SuppressRepeats, errors repeating within the window have their
+// fingerprint and number of repeats instead of their layers.
+func Dict(err error) *zerolog.Event {
+	dict := zerolog.Dict()
+	if err == nil {
+		return dict
+	}
+
+	dict.Str("message", message(err))
+	if code := goerr.Code(err); code != 0 {
+		dict.Int("code", code)
+	}
+	if count, suppress := goerr.Repeated(err); suppress {
+		dict.Str("fingerprint", goerr.Fingerprint(err))
+		dict.Int("repeats", count)
+	} else {
+		dict.Strs("frames", goerr.ListStacks(err))
+	}
+	if fields := goerr.Fields(err); len(fields) > 0 {
+		dict.Fields(fields)
+	}
+	return dict
+}
@@ -0,0 +1,150 @@
+package goerrzerolog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/goerrzerolog"
+	"github.com/angel-one/goerr/samplesrc"
+	"github.com/rs/zerolog"
+)
+
+func TestMarshalError(t *testing.T) {
+	defer func(f func(error) interface{}) { zerolog.ErrorMarshalFunc = f }(zerolog.ErrorMarshalFunc)
+	zerolog.ErrorMarshalFunc = goerrzerolog.MarshalError
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+
+	logger.Error().Err(samplesrc.Controller()).Msg("request failed")
+	logger.Error().Err(errors.New("plain")).Msg("request failed")
+
+	dec := json.NewDecoder(&buf)
+
+	var chained struct {
+		Error []string `json:"error"`
+	}
+	if err := dec.Decode(&chained); err != nil {
+		t.Fatalf("invalid log entry: %v", err)
+	}
+	if len(chained.Error) != 3 {
+		t.Errorf("Nr. of layers. Want: %d, Got: %d", 3, len(chained.Error))
+	}
+
+	var plain struct {
+		Error string `json:"error"`
+	}
+	if err := dec.Decode(&plain); err != nil {
+		t.Fatalf("invalid log entry: %v", err)
+	}
+	if plain.Error != "plain" {
+		t.Errorf("Want: %s, Got: %s", "plain", plain.Error)
+	}
+}
+
+func TestDict(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+
+	err := goerr.WithField(goerr.New(samplesrc.Service(), 409, "controller failed"), "order_id", 42)
+	logger.Error().Dict("error", goerrzerolog.Dict(err)).Msg("request failed")
+
+	var entry struct {
+		Error struct {
+			Message string   `json:"message"`
+			Code    int      `json:"code"`
+			Frames  []string `json:"frames"`
+			OrderID int      `json:"order_id"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid log entry: %v", err)
+	}
+
+	if entry.Error.Message != "controller failed" || entry.Error.Code != 409 || entry.Error.OrderID != 42 {
+		t.Errorf("unexpected error entry: %+v", entry.Error)
+	}
+	if len(entry.Error.Frames) != 3 {
+		t.Errorf("Nr. of frames. Want: %d, Got: %d", 3, len(entry.Error.Frames))
+	}
+}
+
+func TestDictRedacted(t *testing.T) {
+	goerr.AddRedactor(goerr.RegexRedactor(regexp.MustCompile(`token=\w+`)))
+	t.Cleanup(func() { goerr.Configure(goerr.Options{}) })
+
+	goerr.SuppressRepeats(time.Minute)
+	defer goerr.SuppressRepeats(0)
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+	for i := 0; i < 2; i++ {
+		err := goerr.New(nil, "auth failed token=s3cr3t")
+		logger.Error().Dict("error", goerrzerolog.Dict(err)).Msg("request failed")
+		logger.Error().Interface("error", goerrzerolog.MarshalError(err)).Msg("request failed")
+	}
+
+	if strings.Contains(buf.String(), "s3cr3t") || !strings.Contains(buf.String(), "(repeat ") {
+		t.Errorf("expected the messages and repeat lines to be redacted, Got: %s", buf.String())
+	}
+}
+
+func TestMarshalErrorSuppressRepeats(t *testing.T) {
+	defer func(f func(error) interface{}) { zerolog.ErrorMarshalFunc = f }(zerolog.ErrorMarshalFunc)
+	zerolog.ErrorMarshalFunc = goerrzerolog.MarshalError
+	goerr.SuppressRepeats(time.Minute)
+	defer goerr.SuppressRepeats(0)
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+
+	logger.Error().Err(samplesrc.Service()).Msg("request failed")
+	logger.Error().Err(samplesrc.Service()).Msg("request failed")
+
+	dec := json.NewDecoder(&buf)
+	var first struct {
+		Error []string `json:"error"`
+	}
+	if err := dec.Decode(&first); err != nil || len(first.Error) != 2 {
+		t.Fatalf("expected the full stack first, Got: %v %v", first, err)
+	}
+
+	var repeated struct {
+		Error string `json:"error"`
+	}
+	if err := dec.Decode(&repeated); err != nil {
+		t.Fatalf("invalid log entry: %v", err)
+	}
+	want := "service failed (repeat 2, fingerprint " + goerr.Fingerprint(samplesrc.Service()) + ")"
+	if repeated.Error != want {
+		t.Errorf("Want: %s, Got: %s", want, repeated.Error)
+	}
+}
+
+func TestMarshalErrorPretty(t *testing.T) {
+	defer func(f func(error) interface{}) { zerolog.ErrorMarshalFunc = f }(zerolog.ErrorMarshalFunc)
+	zerolog.ErrorMarshalFunc = goerrzerolog.MarshalError
+	defer goerr.SetPretty(goerr.PrettyMode())
+	goerr.SetPretty(true)
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+	err := samplesrc.Service()
+	logger.Error().Err(err).Msg("request failed")
+
+	var entry struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid log entry: %v", err)
+	}
+	if want := "\n" + goerr.Pretty(err); entry.Error != want {
+		t.Errorf("Want: %q, Got: %q", want, entry.Error)
+	}
+}
@@ -0,0 +1,73 @@
+package goerr
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"sync/atomic"
+)
+
+var goroutineCapture atomic.Bool
+
+// SetGoroutineCapture turns the capture of the ID of the goroutine creating
+// every layer on or off. The ID is rendered in the stack lines, and helps to
+// correlate errors passed over channels with the logs of the goroutine they
+// originated in. Reading the ID costs about a microsecond per layer, so it is
+// off by default.
+func SetGoroutineCapture(on bool) {
+	goroutineCapture.Store(on)
+}
+
+// GoroutineID returns the ID of the goroutine which created the innermost
+// goerr layer of err with a captured ID, that is where err originated, or 0.
+func GoroutineID(err error) uint64 {
+	var id uint64
+	walk(err, func(_ int, err error) bool {
+		if e, ok := err.(*Error); ok && e.goroutine != 0 {
+			id = e.goroutine
+		}
+		return true
+	})
+	return id
+}
+
+// WithLabels returns err with the profiler labels of ctx, as set by
+// pprof.Do or pprof.WithLabels, added as fields of its outermost layer. If
+// err is not a goerr, it is wrapped in a new goerr first.
+func WithLabels(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	fields := map[string]any{}
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		fields[key] = value
+		return true
+	})
+	return layer(err).withFields(fields)
+}
+
+// goroutineID returns the ID of the current goroutine if goroutine capture is
+// on, or 0.
+func goroutineID() uint64 {
+	if !goroutineCapture.Load() {
+		return 0
+	}
+	return currentGoroutine()
+}
+
+// currentGoroutine returns the ID of the current goroutine. The runtime does
+// not expose the ID, so it is parsed from the header of the stack trace, e.g.
+// "goroutine 18 [running]:".
+func currentGoroutine() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
@@ -0,0 +1,56 @@
+package goerr_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"runtime/pprof"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestGoroutineCapture(t *testing.T) {
+	if got := goerr.GoroutineID(goerr.New(nil, "failed")); got != 0 {
+		t.Errorf("Want: 0 without capture, Got: %d", got)
+	}
+
+	goerr.SetGoroutineCapture(true)
+	defer goerr.SetGoroutineCapture(false)
+
+	errs := make(chan error)
+	go func() { errs <- goerr.New(nil, "produce failed") }()
+	produced := <-errs
+	err := goerr.New(produced, "consume failed")
+
+	origin, consumer := goerr.GoroutineID(produced), goerr.GoroutineID(goerr.New(nil, "x"))
+	if origin == 0 || origin == consumer {
+		t.Errorf("expected the ID of the producer, Got: %d and consumer %d", origin, consumer)
+	}
+	if got := goerr.GoroutineID(err); got != origin {
+		t.Errorf("Want: %d, Got: %d", origin, got)
+	}
+
+	if stack := goerr.Stack(err); !regexp.MustCompile(`produce failed <goroutine \d+> \[`).MatchString(stack) {
+		t.Errorf("expected the goroutine in the stack, Got: %s", stack)
+	}
+}
+
+func TestWithLabels(t *testing.T) {
+	if goerr.WithLabels(context.Background(), nil) != nil {
+		t.Errorf("expected nil for a nil error")
+	}
+
+	var err error
+	pprof.Do(context.Background(), pprof.Labels("worker", "billing"), func(ctx context.Context) {
+		err = goerr.WithLabels(ctx, errors.New("failed"))
+	})
+
+	if got := goerr.Fields(err)["worker"]; got != "billing" {
+		t.Errorf("Want: billing, Got: %v", got)
+	}
+	if stack := goerr.Stack(err); !strings.Contains(stack, "goroutine_test.go") {
+		t.Errorf("expected the layer to point to the caller, Got: %s", stack)
+	}
+}
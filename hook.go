@@ -0,0 +1,46 @@
+package goerr
+
+import "sync"
+
+var (
+	hooksMu sync.RWMutex
+	hooks   []func(e *Error)
+)
+
+// OnNew registers a hook that is called with every new layer created by New
+// and the other wrapping functions of this package. Hooks are called in the
+// order they were registered, on the goroutine creating the error, so they
+// should be fast and must not block.
+//
+// Hooks are meant to be registered during initialization, for example to
+// count errors or emit trace events. Configure replaces the registered hooks
+// with the ones of its Options, so hooks registered before calling it are
+// dropped: pass them in Options.Hooks or register them afterwards.
+func OnNew(hook func(e *Error)) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	hooks = append(hooks, hook)
+}
+
+// notify adds the new layer e to the statistics and calls the hooks of the
+// factory of e, or the registered hooks if e was not created by a factory,
+// with e.
+func notify(e *Error) {
+	count(e)
+
+	if e.factory != nil {
+		for _, hook := range e.factory.opts.Hooks {
+			hook(e)
+		}
+		return
+	}
+
+	// the hooks are called without holding the lock, as they may create
+	// errors themselves or register other hooks
+	hooksMu.RLock()
+	registered := hooks
+	hooksMu.RUnlock()
+	for _, hook := range registered {
+		hook(e)
+	}
+}
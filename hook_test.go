@@ -0,0 +1,78 @@
+package goerr_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+func TestOnNew(t *testing.T) {
+	// hooks can not be removed, so stop recording once the test is done
+	var recording atomic.Bool
+	recording.Store(true)
+	defer recording.Store(false)
+
+	var mu sync.Mutex
+	var created []string
+	goerr.OnNew(func(e *goerr.Error) {
+		if recording.Load() {
+			mu.Lock()
+			created = append(created, e.Message())
+			mu.Unlock()
+		}
+	})
+
+	samplesrc.Controller()
+	goerr.WithSeverity(errors.New("plain"), goerr.SeverityWarning)
+	goerr.WithSeverity(goerr.New(nil, "enriched"), goerr.SeverityWarning)
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"error from database", "service failed", "controller failed", "plain", "enriched"}
+	if len(created) != len(want) {
+		t.Fatalf("Want: %v, Got: %v", want, created)
+	}
+	for i := range want {
+		if created[i] != want[i] {
+			t.Errorf("Want: %v, Got: %v", want, created)
+		}
+	}
+}
+
+func TestOnNewReentrant(t *testing.T) {
+	var registered atomic.Bool
+	goerr.OnNew(func(e *goerr.Error) {
+		if e.Message() == "export failed" && registered.CompareAndSwap(false, true) {
+			goerr.OnNew(func(*goerr.Error) {})
+			goerr.New(nil, "metrics push failed")
+		}
+	})
+
+	done := make(chan struct{})
+	go func() {
+		goerr.New(nil, "export failed")
+		close(done)
+	}()
+	select {
+	case <-done:
+		goerr.Configure(goerr.Options{})
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected hooks creating errors and registering hooks not to deadlock")
+	}
+}
+
+func TestConfigureDropsHooks(t *testing.T) {
+	var called atomic.Bool
+	goerr.OnNew(func(*goerr.Error) { called.Store(true) })
+	goerr.Configure(goerr.Options{})
+
+	goerr.New(nil, "failed")
+	if called.Load() {
+		t.Errorf("expected Configure to drop the hooks registered with OnNew")
+	}
+}
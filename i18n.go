@@ -0,0 +1,81 @@
+package goerr
+
+import "sync"
+
+// A Translator renders the message registered under key in the given locale.
+// It reports false if it has no message for the key and locale.
+type Translator interface {
+	Translate(locale, key string, args ...any) (string, bool)
+}
+
+// TranslatorFunc adapts an ordinary function to the Translator interface.
+type TranslatorFunc func(locale, key string, args ...any) (string, bool)
+
+// Translate calls f(locale, key, args...).
+func (f TranslatorFunc) Translate(locale, key string, args ...any) (string, bool) {
+	return f(locale, key, args...)
+}
+
+var (
+	translatorMu sync.RWMutex
+	translator   Translator
+)
+
+// SetTranslator sets the Translator used by Localize. Passing nil removes it.
+func SetTranslator(t Translator) {
+	translatorMu.Lock()
+	defer translatorMu.Unlock()
+	translator = t
+}
+
+// WithMessageKey returns err with a message key and its template arguments
+// set on the outermost layer. The key identifies the public message of the
+// error independently of the language it is rendered in.
+func WithMessageKey(err error, key string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+
+	e := layer(err)
+	e.msgKey = key
+	e.msgArgs = append([]any(nil), args...)
+	return e
+}
+
+// MessageKey returns the message key last given in the call chain, or an
+// empty string if there is none.
+func MessageKey(err error) string {
+	e := keyed(err)
+	if e == nil {
+		return ""
+	}
+	return e.msgKey
+}
+
+// Localize renders the public message of err in the given locale using the
+// Translator set with SetTranslator. It falls back to PublicMessage, which
+// leaves out masked and hidden layers and applies the redactors, if err has
+// no message key or the translator has no message for it.
+func Localize(err error, locale string) string {
+	if err == nil {
+		return ""
+	}
+
+	translatorMu.RLock()
+	t := translator
+	translatorMu.RUnlock()
+
+	e := keyed(err)
+	if e == nil || t == nil {
+		return PublicMessage(err)
+	}
+
+	if msg, ok := t.Translate(locale, e.msgKey, e.msgArgs...); ok {
+		return msg
+	}
+	return PublicMessage(err)
+}
+
+func keyed(err error) *Error {
+	return find(err, func(e *Error) bool { return e.msgKey != "" })
+}
@@ -0,0 +1,62 @@
+package goerr_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestLocalize(t *testing.T) {
+	messages := map[string]map[string]string{
+		"en": {"order.duplicate": "Order %s already exists"},
+		"hi": {"order.duplicate": "ऑर्डर %s पहले से मौजूद है"},
+	}
+	goerr.SetTranslator(goerr.TranslatorFunc(func(locale, key string, args ...any) (string, bool) {
+		msg, ok := messages[locale][key]
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf(msg, args...), true
+	}))
+	defer goerr.SetTranslator(nil)
+
+	repository := func() error {
+		err := goerr.New(errors.New("duplicate key"), http.StatusConflict, "order insert failed")
+		return goerr.WithMessageKey(err, "order.duplicate", "A-42")
+	}
+	err := goerr.New(repository(), "service error")
+
+	if got := goerr.MessageKey(err); got != "order.duplicate" {
+		t.Errorf("Want: %s, Got: %s", "order.duplicate", got)
+	}
+
+	tests := map[string]string{
+		"en": "Order A-42 already exists",
+		"hi": "ऑर्डर A-42 पहले से मौजूद है",
+		"fr": "service error",
+	}
+	for locale, want := range tests {
+		if got := goerr.Localize(err, locale); got != want {
+			t.Errorf("locale %s. Want: %s, Got: %s", locale, want, got)
+		}
+	}
+}
+
+func TestLocalizeWithoutKey(t *testing.T) {
+	err := goerr.New(nil, "plain failure")
+
+	if got := goerr.Localize(err, "en"); got != "plain failure" {
+		t.Errorf("Want: %s, Got: %s", "plain failure", got)
+	}
+	if got := goerr.MessageKey(err); got != "" {
+		t.Errorf("Want no key, Got: %s", got)
+	}
+
+	hidden := goerr.Hide(goerr.New(goerr.New(nil, "order invalid"), "pq: deadlock detected"))
+	if got := goerr.Localize(hidden, "en"); got != "order invalid" {
+		t.Errorf("expected the public message. Want: %s, Got: %s", "order invalid", got)
+	}
+}
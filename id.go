@@ -0,0 +1,82 @@
+package goerr
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sync/atomic"
+)
+
+// ErrorIDField is the field holding the ID set with WithID.
+const ErrorIDField = "error_id"
+
+var idGenerator atomic.Pointer[func() string]
+
+// SetIDGenerator sets the function generating the IDs of WithID, e.g. to
+// use UUIDs. Passing nil restores the default, which generates ULIDs: 26
+// characters sorting by the time they were generated.
+func SetIDGenerator(f func() string) {
+	if f == nil {
+		idGenerator.Store(nil)
+		return
+	}
+	idGenerator.Store(&f)
+}
+
+// WithID returns err with id as the ID of the error instance, in the
+// ErrorIDField field of its outermost layer, or with a generated ID if id is
+// empty. Support can then correlate the ID a customer reports with the log
+// line of the error. If err already has an ID, it is kept unless id is not
+// empty. If err is not a goerr, it is wrapped in a new goerr first.
+//
+// The goerrhttp middleware sets an ID on every error it handles, so that it
+// is part of the response and of the error passed to the logging callback.
+func WithID(err error, id string) error {
+	if err == nil {
+		return nil
+	}
+	if id == "" {
+		if ID(err) != "" {
+			return err
+		}
+		id = newID()
+	}
+	return layer(err).withFields(map[string]any{ErrorIDField: id})
+}
+
+// ID returns the ID last set in the call chain of err with WithID, or an
+// empty string.
+func ID(err error) string {
+	e := find(err, func(e *Error) bool {
+		_, ok := e.fields[ErrorIDField]
+		return ok
+	})
+	if e == nil {
+		return ""
+	}
+	id, _ := e.fields[ErrorIDField].(string)
+	return id
+}
+
+// crockford is the alphabet of ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newID returns a new ID from the configured generator, or a ULID.
+func newID() string {
+	if f := idGenerator.Load(); f != nil {
+		return (*f)()
+	}
+
+	// 48 bits of milliseconds followed by 80 random bits
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(now().UnixMilli())<<16)
+	rand.Read(b[6:])
+
+	var s [26]byte
+	hi, lo := binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:])
+	for i := 25; i >= 0; i-- {
+		s[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(s[:])
+}
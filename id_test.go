@@ -0,0 +1,44 @@
+package goerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestWithID(t *testing.T) {
+	err := goerr.WithID(goerr.New(nil, "failed"), "")
+	id := goerr.ID(err)
+	if len(id) != 26 {
+		t.Errorf("expected a ULID, Got: %q", id)
+	}
+	if stack := goerr.Stack(err); !strings.Contains(stack, "error_id="+id) {
+		t.Errorf("expected the stack to contain the ID, Got: %s", stack)
+	}
+
+	wrapped := goerr.New(err, "handler failed")
+	if goerr.ID(goerr.WithID(wrapped, "")) != id {
+		t.Errorf("expected an existing ID to be kept")
+	}
+	if want, got := "ref-1", goerr.ID(goerr.WithID(wrapped, "ref-1")); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+
+	if other := goerr.ID(goerr.WithID(errors.New("plain"), "")); other == "" || other == id {
+		t.Errorf("expected a new unique ID, Got: %q", other)
+	}
+	if goerr.ID(goerr.New(nil, "failed")) != "" || goerr.WithID(nil, "x") != nil {
+		t.Errorf("expected no ID")
+	}
+}
+
+func TestSetIDGenerator(t *testing.T) {
+	goerr.SetIDGenerator(func() string { return "fixed" })
+	defer goerr.SetIDGenerator(nil)
+
+	if want, got := "fixed", goerr.ID(goerr.WithID(goerr.New(nil, "failed"), "")); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+}
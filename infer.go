@@ -0,0 +1,58 @@
+package goerr
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+type inference struct {
+	target error
+	code   int
+}
+
+var (
+	inferencesMu sync.RWMutex
+	inferences   = []inference{
+		{sql.ErrNoRows, http.StatusNotFound},
+		{context.DeadlineExceeded, http.StatusGatewayTimeout},
+		{os.ErrNotExist, http.StatusNotFound},
+		{io.EOF, http.StatusBadRequest},
+	}
+)
+
+// InferCode makes Code return code for errors without an explicit code that
+// wrap target, as reported by errors.Is. It replaces the code inferred for
+// target if there was one already; a code of 0 disables inference for it.
+//
+// By default codes are inferred for sql.ErrNoRows (404),
+// context.DeadlineExceeded (504), os.ErrNotExist (404) and io.EOF (400).
+func InferCode(target error, code int) {
+	inferencesMu.Lock()
+	defer inferencesMu.Unlock()
+
+	for i, inf := range inferences {
+		if inf.target == target {
+			inferences[i].code = code
+			return
+		}
+	}
+	inferences = append(inferences, inference{target: target, code: code})
+}
+
+// inferCode returns the code inferred for err, or 0 if it does not wrap any
+// of the registered targets.
+func inferCode(err error) int {
+	inferencesMu.RLock()
+	defer inferencesMu.RUnlock()
+
+	for _, inf := range inferences {
+		if is(err, inf.target) {
+			return inf.code
+		}
+	}
+	return 0
+}
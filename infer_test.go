@@ -0,0 +1,86 @@
+package goerr_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestInferredCode(t *testing.T) {
+	_, statErr := os.Stat("/does/not/exist")
+
+	tests := []struct {
+		cause error
+		want  int
+	}{
+		{sql.ErrNoRows, http.StatusNotFound},
+		{fmt.Errorf("query: %w", sql.ErrNoRows), http.StatusNotFound},
+		{context.DeadlineExceeded, http.StatusGatewayTimeout},
+		{statErr, http.StatusNotFound},
+		{io.EOF, http.StatusBadRequest},
+		{errors.New("plain"), 0},
+	}
+
+	for _, tt := range tests {
+		err := goerr.New(goerr.New(tt.cause, "repository error"), "service error")
+		if got := goerr.Code(err); got != tt.want {
+			t.Errorf("%v. Want: %d, Got: %d", tt.cause, tt.want, got)
+		}
+	}
+}
+
+func TestExplicitCodeOverridesInference(t *testing.T) {
+	err := goerr.New(sql.ErrNoRows, http.StatusBadRequest, "repository error")
+
+	if got := goerr.Code(err); got != http.StatusBadRequest {
+		t.Errorf("Want: %d, Got: %d", http.StatusBadRequest, got)
+	}
+}
+
+func TestInferCode(t *testing.T) {
+	errLocked := errors.New("locked")
+	goerr.InferCode(errLocked, http.StatusLocked)
+	goerr.InferCode(io.EOF, 0)
+	defer goerr.InferCode(io.EOF, http.StatusBadRequest)
+
+	if got := goerr.Code(goerr.New(errLocked, "update failed")); got != http.StatusLocked {
+		t.Errorf("Want: %d, Got: %d", http.StatusLocked, got)
+	}
+	if got := goerr.Code(goerr.New(io.EOF, "read failed")); got != 0 {
+		t.Errorf("Want: %d, Got: %d", 0, got)
+	}
+}
+
+// joinError mimics the errors created by errors.Join.
+type joinError []error
+
+func (j joinError) Error() string   { return "joined" }
+func (j joinError) Unwrap() []error { return j }
+
+func TestCodeJoined(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"first non-zero", joinError{errors.New("plain"), goerr.New(nil, http.StatusConflict, "conflict"), goerr.New(nil, http.StatusBadGateway, "bad gateway")}, http.StatusConflict},
+		{"kind", joinError{goerr.WithKind(errors.New("plain"), goerr.KindNotFound)}, http.StatusNotFound},
+		{"wrapped", goerr.New(fmt.Errorf("fan-out: %w", joinError{goerr.New(nil, http.StatusServiceUnavailable, "down")}), "aggregate failed"), http.StatusServiceUnavailable},
+		{"outer wins", goerr.New(joinError{goerr.New(nil, http.StatusConflict, "conflict")}, http.StatusBadRequest, "invalid"), http.StatusBadRequest},
+		{"inferred", joinError{errors.New("plain"), sql.ErrNoRows}, http.StatusNotFound},
+		{"none", joinError{errors.New("plain")}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := goerr.Code(tt.err); got != tt.want {
+			t.Errorf("%s. Want: %d, Got: %d", tt.name, tt.want, got)
+		}
+	}
+}
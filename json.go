@@ -0,0 +1,107 @@
+package goerr
+
+import "encoding/json"
+
+type jsonError struct {
+	jsonHead
+	Layers []jsonLayer `json:"layers"`
+}
+
+// jsonHead is the part of the JSON document of an error before its layers.
+type jsonHead struct {
+	Message    string         `json:"message"`
+	Code       int            `json:"code,omitempty"`
+	CodeString string         `json:"code_string,omitempty"`
+	Codes      map[Domain]int `json:"codes,omitempty"`
+	Details    any            `json:"details,omitempty"`
+	Warnings   []string       `json:"warnings,omitempty"`
+	Build      *jsonBuild     `json:"build,omitempty"`
+}
+
+type jsonLayer struct {
+	Message    string         `json:"message"`
+	Code       int            `json:"code,omitempty"`
+	CodeString string         `json:"code_string,omitempty"`
+	Severity   string         `json:"severity,omitempty"`
+	Kind       string         `json:"kind,omitempty"`
+	Fields     map[string]any `json:"fields,omitempty"`
+}
+
+// MarshalJSON renders the layers of the chain that are visible to public
+// renderers (see Mask and Hide), outermost first, along with the message,
+// code, string code and details of the outermost visible layers, the
+// warnings of all visible layers and the build metadata, see SetBuildInfo.
+// Redactors apply as they do for Stack. Being meant for API clients, it
+// leaves out the locations and goroutines of the layers, which Stack and
+// Dump render for logs.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newJSONError(e))
+}
+
+func newJSONError(err error) jsonError {
+	j := jsonError{jsonHead: newJSONHead(err), Layers: []jsonLayer{}}
+	walkPublic(err, func(err error) bool {
+		j.Layers = append(j.Layers, newJSONLayer(err))
+		return true
+	})
+	return j
+}
+
+// newJSONHead returns the message, code, details and warnings of the layers
+// of err visible to public renderers, and the build metadata.
+func newJSONHead(err error) jsonHead {
+	h := jsonHead{Build: newJSONBuild()}
+	first := true
+	walkPublic(err, func(err error) bool {
+		e, ok := err.(*Error)
+		if first {
+			h.Message = publicMessage(err)
+			first = false
+		}
+		if !ok {
+			return true
+		}
+		if h.Code == 0 {
+			h.Code = e.code
+		}
+		if h.CodeString == "" {
+			h.CodeString = e.codeStr
+		}
+		for d, c := range e.codes {
+			if _, ok := h.Codes[d]; ok || c == 0 {
+				continue
+			}
+			if h.Codes == nil {
+				h.Codes = map[Domain]int{}
+			}
+			h.Codes[d] = c
+		}
+		if h.Details == nil {
+			h.Details = e.details
+		}
+		h.Warnings = append(h.Warnings, warningMessages(e.factory, e.warnings)...)
+		return true
+	})
+	return h
+}
+
+// newJSONLayer describes the layer err.
+func newJSONLayer(err error) jsonLayer {
+	f := newFrame(0, err)
+	l := jsonLayer{
+		Message: f.Message,
+		Code:    f.Code,
+		Kind:    string(f.Kind),
+		Fields:  f.Fields,
+	}
+	if f.Severity != 0 {
+		l.Severity = f.Severity.String()
+	}
+	if e, ok := err.(*Error); ok {
+		l.CodeString = e.codeStr
+		if e.public != "" {
+			l.Message = publicMessage(e)
+		}
+	}
+	return l
+}
@@ -0,0 +1,113 @@
+package goerr
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind classifies an error by its meaning, independently of its message.
+//
+// A Kind is an error itself, so it can be used as the target of errors.Is:
+// errors.Is(err, goerr.KindNotFound) reports whether any layer of err has
+// that kind, either set with WithKind or implied by its code.
+type Kind string
+
+// Predefined kinds. Each corresponds to an HTTP status code, see Kind.Code.
+const (
+	KindBadRequest      Kind = "bad_request"
+	KindUnauthorized    Kind = "unauthorized"
+	KindForbidden       Kind = "forbidden"
+	KindNotFound        Kind = "not_found"
+	KindConflict        Kind = "conflict"
+	KindUnprocessable   Kind = "unprocessable"
+	KindTooManyRequests Kind = "too_many_requests"
+	KindInternal        Kind = "internal"
+	KindUnavailable     Kind = "unavailable"
+	KindTimeout         Kind = "timeout"
+)
+
+var kindCodes = map[Kind]int{
+	KindBadRequest:      http.StatusBadRequest,
+	KindUnauthorized:    http.StatusUnauthorized,
+	KindForbidden:       http.StatusForbidden,
+	KindNotFound:        http.StatusNotFound,
+	KindConflict:        http.StatusConflict,
+	KindUnprocessable:   http.StatusUnprocessableEntity,
+	KindTooManyRequests: http.StatusTooManyRequests,
+	KindInternal:        http.StatusInternalServerError,
+	KindUnavailable:     http.StatusServiceUnavailable,
+	KindTimeout:         http.StatusGatewayTimeout,
+}
+
+// Error returns the name of the kind.
+func (k Kind) Error() string {
+	return string(k)
+}
+
+// Code returns the HTTP status code that corresponds to the kind, or 0 for
+// kinds defined outside this package.
+func (k Kind) Code() int {
+	return kindCodes[k]
+}
+
+// kindOfCode returns the predefined kind that corresponds to code, or an
+// empty Kind if there is none.
+func kindOfCode(code int) Kind {
+	for k, c := range kindCodes {
+		if c == code {
+			return k
+		}
+	}
+	return ""
+}
+
+// WithKind returns err with the given kind set on its outermost layer.
+// If err is not a goerr, it is wrapped in a new goerr first.
+func WithKind(err error, kind Kind) error {
+	if err == nil {
+		return nil
+	}
+
+	e := layer(err)
+	e.kind = kind
+	return e
+}
+
+// KindOf returns the kind last given in the call chain of err. If no layer
+// has a kind set, the kind implied by Code(err) is returned, which is empty
+// if the code does not correspond to any predefined kind.
+func KindOf(err error) Kind {
+	if e := find(err, func(e *Error) bool { return e.kind != "" }); e != nil {
+		return e.kind
+	}
+	return kindOfCode(Code(err))
+}
+
+// IsKind reports whether any layer of err has the given kind. It is the same
+// as errors.Is(err, kind).
+func IsKind(err error, kind Kind) bool {
+	return errors.Is(err, kind)
+}
+
+// IsCode reports whether the code of err, as returned by Code, equals code.
+func IsCode(err error, code int) bool {
+	return Code(err) == code
+}
+
+// Is reports whether this layer matches target. A layer matches a Kind if it
+// has that kind set, or has no kind but a code that corresponds to the kind.
+// With SetDefinitionMatching on, a layer created by Definition.New also
+// matches any other layer created from a definition of the same name.
+func (e *Error) Is(target error) bool {
+	if t, ok := target.(*Error); ok {
+		return e.def != nil && t.def != nil && e.def.Name == t.def.Name && definitionMatching.Load()
+	}
+	k, ok := target.(Kind)
+	if !ok {
+		return false
+	}
+	if e.kind != "" {
+		return e.kind == k
+	}
+	return e.code != 0 && e.code == k.Code()
+}
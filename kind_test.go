@@ -0,0 +1,86 @@
+package goerr_test
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestIsKind(t *testing.T) {
+	repository := func() error {
+		return goerr.New(sql.ErrNoRows, http.StatusNotFound, "order not found")
+	}
+	err := goerr.New(repository(), "service error")
+
+	if !errors.Is(err, goerr.KindNotFound) {
+		t.Errorf("expected errors.Is(err, goerr.KindNotFound) to return true")
+	}
+	if errors.Is(err, goerr.KindConflict) {
+		t.Errorf("expected errors.Is(err, goerr.KindConflict) to return false")
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected errors.Is(err, sql.ErrNoRows) to return true")
+	}
+	if got := goerr.KindOf(err); got != goerr.KindNotFound {
+		t.Errorf("Want: %s, Got: %s", goerr.KindNotFound, got)
+	}
+}
+
+func TestWithKind(t *testing.T) {
+	const insufficientFunds goerr.Kind = "insufficient_funds"
+
+	err := goerr.WithKind(goerr.New(nil, http.StatusUnprocessableEntity, "debit failed"), insufficientFunds)
+	err = goerr.New(err, "payment failed")
+
+	if !goerr.IsKind(err, insufficientFunds) {
+		t.Errorf("expected IsKind(err, insufficientFunds) to return true")
+	}
+	if goerr.IsKind(err, goerr.KindUnprocessable) {
+		t.Errorf("explicit kind should take precedence over the code")
+	}
+	if got := goerr.KindOf(err); got != insufficientFunds {
+		t.Errorf("Want: %s, Got: %s", insufficientFunds, got)
+	}
+	if got := goerr.KindOf(errors.New("plain")); got != "" {
+		t.Errorf("Want no kind, Got: %s", got)
+	}
+}
+
+func TestCodeOfKind(t *testing.T) {
+	const insufficientFunds goerr.Kind = "insufficient_funds"
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"kind only", goerr.WithKind(goerr.New(nil, "order exists"), goerr.KindConflict), http.StatusConflict},
+		{"code and kind", goerr.WithKind(goerr.New(nil, http.StatusBadRequest, "order exists"), goerr.KindConflict), http.StatusBadRequest},
+		{"outer kind", goerr.WithKind(goerr.New(goerr.New(nil, http.StatusNotFound, "no rows"), "order exists"), goerr.KindConflict), http.StatusConflict},
+		{"custom kind", goerr.WithKind(goerr.New(goerr.New(nil, http.StatusNotFound, "no rows"), "debit failed"), insufficientFunds), http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if got := goerr.Code(tt.err); got != tt.want {
+			t.Errorf("%s. Want: %d, Got: %d", tt.name, tt.want, got)
+		}
+	}
+
+	err := goerr.WithKind(goerr.New(nil, "order exists"), goerr.KindConflict)
+	if !goerr.IsClientFault(err) || goerr.IsServerFault(err) {
+		t.Errorf("expected a conflict kind to be a client fault")
+	}
+}
+
+func TestIsCode(t *testing.T) {
+	err := goerr.New(goerr.New(nil, http.StatusConflict, "duplicate"), "service error")
+
+	if !goerr.IsCode(err, http.StatusConflict) {
+		t.Errorf("expected IsCode(err, 409) to return true")
+	}
+	if goerr.IsCode(err, http.StatusNotFound) {
+		t.Errorf("expected IsCode(err, 404) to return false")
+	}
+}
@@ -0,0 +1,19 @@
+package goerr
+
+// Layer is one layer of an error chain, as visited by Walk. The embedded
+// Frame describes it the way Stack renders it.
+type Layer struct {
+	Frame
+	// Err is the layer itself: a *Error, or the error wrapped by the innermost
+	// goerr layer.
+	Err error
+}
+
+// Walk calls fn for every layer of err, outermost first, until fn returns
+// false. Like Stack, it visits the error wrapped by the innermost goerr layer
+// as the last layer, and at most MaxChainDepth layers.
+func Walk(err error, fn func(layer Layer) bool) {
+	walk(err, func(depth int, err error) bool {
+		return fn(Layer{Frame: newFrame(depth, err), Err: err})
+	})
+}
@@ -0,0 +1,17 @@
+//go:build go1.23
+
+package goerr
+
+import "iter"
+
+// Chain returns an iterator over the layers of err, in the same order as
+// Walk.
+//
+//	for layer := range goerr.Chain(err) {
+//		...
+//	}
+func Chain(err error) iter.Seq[Layer] {
+	return func(yield func(Layer) bool) {
+		Walk(err, yield)
+	}
+}
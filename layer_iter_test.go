@@ -0,0 +1,24 @@
+//go:build go1.23
+
+package goerr_test
+
+import (
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+func TestChain(t *testing.T) {
+	var messages []string
+	for layer := range goerr.Chain(samplesrc.Controller()) {
+		messages = append(messages, layer.Message)
+		if layer.Function == "samplesrc.Service" {
+			break
+		}
+	}
+
+	if len(messages) != 2 || messages[1] != "service failed" {
+		t.Errorf("unexpected layers: %v", messages)
+	}
+}
@@ -0,0 +1,43 @@
+package goerr_test
+
+import (
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+func TestWalk(t *testing.T) {
+	err := goerr.New(samplesrc.Service(), 409, "controller failed")
+
+	var layers []goerr.Layer
+	goerr.Walk(err, func(layer goerr.Layer) bool {
+		layers = append(layers, layer)
+		return true
+	})
+
+	if len(layers) != 3 {
+		t.Fatalf("Nr. of layers. Want: %d, Got: %d", 3, len(layers))
+	}
+	if layers[0].Err != err || layers[0].Code != 409 || layers[0].Depth != 0 {
+		t.Errorf("unexpected outermost layer: %+v", layers[0])
+	}
+	if layers[2].Message != "error from database" || layers[2].Line != 27 || layers[2].Function != "samplesrc.Repository" {
+		t.Errorf("unexpected innermost layer: %+v", layers[2])
+	}
+}
+
+func TestWalkStop(t *testing.T) {
+	// find the deepest layer with a code
+	err := goerr.New(goerr.New(goerr.New(nil, 500, "db down"), 503, "unavailable"), "controller failed")
+
+	visited := 0
+	goerr.Walk(err, func(layer goerr.Layer) bool {
+		visited++
+		return layer.Code == 0
+	})
+
+	if visited != 2 {
+		t.Errorf("Nr. of visited layers. Want: %d, Got: %d", 2, visited)
+	}
+}
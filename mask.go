@@ -0,0 +1,83 @@
+package goerr
+
+import "sync/atomic"
+
+// Mask wraps err in a new layer that replaces it for public renderers, like
+// the JSON marshaler and PublicMessage, while Stack still renders the whole
+// chain for logs. The message arguments are the same as for New, so a code
+// can be given as well:
+//
+//	return goerr.Mask(err, http.StatusInternalServerError, "internal error")
+func Mask(err error, message ...any) error {
+	e := newError(err, message, 1)
+	e.masks = true
+	notify(e)
+	return e
+}
+
+// Hide returns err with its outermost layer hidden from public renderers.
+// Unlike Mask it adds no layer, so public renderers show the next layer of
+// the chain instead. If err is not a goerr, it is wrapped in a new goerr
+// first.
+func Hide(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	e := layer(err)
+	e.hidden = true
+	return e
+}
+
+// PublicMessage returns the message of the outermost layer of err that is not
+// hidden from public renderers, with the registered redactors applied. It
+// returns an empty string if all layers are hidden.
+func PublicMessage(err error) string {
+	msg := ""
+	walkPublic(err, func(err error) bool {
+		msg = publicMessage(err)
+		return false
+	})
+	return msg
+}
+
+// publicMessage returns the message of the layer err for public renderers:
+// the one set with Public, or its own, with the registered redactors applied.
+func publicMessage(err error) string {
+	if e, ok := err.(*Error); ok && e.public != "" {
+		return redactMessage(e.factory, e.public)
+	}
+	return newFrame(0, err).Message
+}
+
+var exposeForeign atomic.Bool
+
+// SetExposeForeign sets whether errors that are not goerrs, like the ones
+// returned by drivers or the standard library, are visible to public
+// renderers. They are hidden by default, since their messages often carry
+// internals, like queries, hosts or file paths, that API clients must not
+// see: only the goerr layers wrapping them are rendered.
+func SetExposeForeign(on bool) {
+	exposeForeign.Store(on)
+}
+
+// walkPublic calls fn for the layers of err that are visible to public
+// renderers, skipping layers hidden with Hide and, unless SetExposeForeign is
+// on, the ones that are not goerrs, and stopping after a layer created by
+// Mask, until fn returns false.
+func walkPublic(err error, fn func(err error) bool) {
+	foreign := exposeForeign.Load()
+	walk(err, func(_ int, err error) bool {
+		e, ok := err.(*Error)
+		if !ok {
+			if !foreign {
+				return true
+			}
+			return fn(err)
+		}
+		if e.hidden {
+			return true
+		}
+		return fn(err) && !e.masks
+	})
+}
@@ -0,0 +1,86 @@
+package goerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+func TestMask(t *testing.T) {
+	err := goerr.Mask(samplesrc.Service(), http.StatusInternalServerError, "internal error")
+
+	if got := goerr.PublicMessage(err); got != "internal error" {
+		t.Errorf("Want: %s, Got: %s", "internal error", got)
+	}
+
+	b, _ := json.Marshal(err)
+	if strings.Contains(string(b), "service failed") || strings.Contains(string(b), "database") {
+		t.Errorf("json contains masked layers: %s", b)
+	}
+	if !strings.HasPrefix(string(b), `{"message":"internal error","code":500,"layers":[{"message":"internal error","code":500}]`) {
+		t.Errorf("unexpected json: %s", b)
+	}
+
+	if stack := goerr.Stack(err); !strings.Contains(stack, "error from database") {
+		t.Errorf("stack do not contain the masked layers: %s", stack)
+	}
+	if !errors.Is(err, goerr.KindInternal) {
+		t.Errorf("masked error should still match its kind")
+	}
+}
+
+func TestHide(t *testing.T) {
+	repository := func() error {
+		return goerr.Hide(goerr.New(errors.New("pq: relation orders does not exist"), "select from orders failed"))
+	}
+	err := goerr.New(repository(), http.StatusNotFound, "order not found")
+	err = goerr.Hide(goerr.New(err, "controller failed"))
+
+	if got := goerr.PublicMessage(err); got != "order not found" {
+		t.Errorf("Want: %s, Got: %s", "order not found", got)
+	}
+
+	var got struct {
+		Message string
+		Code    int
+		Layers  []struct{ Message string }
+	}
+	b, _ := json.Marshal(err)
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+
+	if got.Message != "order not found" || got.Code != http.StatusNotFound {
+		t.Errorf("unexpected json: %s", b)
+	}
+	if len(got.Layers) != 1 || strings.Contains(string(b), "pq: relation orders does not exist") {
+		t.Errorf("expected the foreign cause to be hidden, Got: %s", b)
+	}
+}
+
+func TestSetExposeForeign(t *testing.T) {
+	goerr.SetExposeForeign(true)
+	defer goerr.SetExposeForeign(false)
+
+	err := goerr.New(errors.New("pq: relation orders does not exist"), http.StatusNotFound, "order not found")
+	var got struct{ Layers []struct{ Message string } }
+	b, _ := json.Marshal(err)
+	json.Unmarshal(b, &got)
+	if len(got.Layers) != 2 || got.Layers[1].Message != "pq: relation orders does not exist" {
+		t.Errorf("Want: the foreign cause exposed, Got: %s", b)
+	}
+}
+
+func TestMarshalJSONLeavesOutLocations(t *testing.T) {
+	b, _ := json.Marshal(goerr.New(nil, "order not found"))
+	for _, key := range []string{`"file"`, `"line"`, `"function"`, `"goroutine"`} {
+		if strings.Contains(string(b), key) {
+			t.Errorf("Want: no %s, Got: %s", key, b)
+		}
+	}
+}
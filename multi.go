@@ -0,0 +1,66 @@
+package goerr
+
+import "strings"
+
+// causes are the independent causes of a layer created by NewMulti.
+type causes []error
+
+// Error returns the messages of the causes separated by semicolons.
+func (c causes) Error() string {
+	msgs := make([]string, len(c))
+	for i, err := range c {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the causes, so that errors.Is and errors.As match any of
+// them.
+func (c causes) Unwrap() []error {
+	return c
+}
+
+// NewMulti returns a new layer with the given message and code caused by
+// several independent failures, like all replicas of a store failing. Stack
+// renders the chains of the causes as branches of a tree below the layer,
+// errors.Is and errors.As match any of them, and without a code Code
+// returns the first code of the causes. Nil causes are left out, and
+// NewMulti returns nil if all causes are nil.
+//
+//	return goerr.NewMulti("all replicas failed", http.StatusServiceUnavailable, errA, errB, errC)
+func NewMulti(message string, code int, errs ...error) error {
+	e := newMulti(message, code, errs, 1)
+	if e == nil {
+		return nil
+	}
+	notify(e)
+	return e
+}
+
+// newMulti creates the layer of NewMulti, or returns nil if all errs are nil.
+// skip is the same as for newError. Hooks are not notified.
+func newMulti(message string, code int, errs []error, skip int) *Error {
+	var c causes
+	for _, err := range errs {
+		if err != nil {
+			c = append(c, err)
+		}
+	}
+	if len(c) == 0 {
+		return nil
+	}
+	return newError(c, []any{code, "%s", message}, skip+1)
+}
+
+// Causes returns the causes of the outermost layer of err created by
+// NewMulti, or nil if there is none.
+func Causes(err error) []error {
+	e := find(err, func(e *Error) bool {
+		_, ok := e.err.(causes)
+		return ok
+	})
+	if e == nil {
+		return nil
+	}
+	return append([]error(nil), e.err.(causes)...)
+}
@@ -0,0 +1,119 @@
+package goerr_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestNewMulti(t *testing.T) {
+	errA := goerr.New(goerr.New(context.DeadlineExceeded, "dial replica a"), "replica a failed")
+	errB := goerr.New(nil, http.StatusServiceUnavailable, "replica b failed")
+	errC := os.ErrNotExist
+	err := goerr.NewMulti("all replicas failed", 0, errA, nil, errB, errC)
+
+	if err.Error() != "all replicas failed" {
+		t.Errorf("Want: all replicas failed, Got: %s", err.Error())
+	}
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected errors.Is to match the causes")
+	}
+	var e *goerr.Error
+	if !errors.As(err, &e) {
+		t.Errorf("expected errors.As to match")
+	}
+	if got := goerr.Code(err); got != http.StatusGatewayTimeout {
+		t.Errorf("code. Want: the first code of the causes (504), Got: %d", got)
+	}
+	if got := goerr.Causes(goerr.New(err, "read failed")); len(got) != 3 || got[1] != errB {
+		t.Errorf("Want: the 3 causes, Got: %v", got)
+	}
+
+	lines := strings.Split(goerr.Stack(err), "\n")[1:]
+	want := []string{
+		"all replicas failed [",
+		"\treplica a failed [",
+		"\t\tdial replica a [",
+		"\t\t\tcontext deadline exceeded",
+		"\treplica b failed (503) [",
+		"\tfile does not exist",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("nr. of lines. Want: %d, Got: %d\n%s", len(want), len(lines), goerr.Stack(err))
+	}
+	for i := range want {
+		if !strings.HasPrefix(lines[i], want[i]) {
+			t.Errorf("line %d. Want prefix: %q, Got: %q", i, want[i], lines[i])
+		}
+	}
+}
+
+func TestNewMultiCode(t *testing.T) {
+	err := goerr.NewMulti("100% of replicas failed", http.StatusServiceUnavailable, errors.New("a"), errors.New("b"))
+	if goerr.Code(err) != http.StatusServiceUnavailable || err.Error() != "100% of replicas failed" {
+		t.Errorf("Want: 100%% of replicas failed (503), Got: %s (%d)", err.Error(), goerr.Code(err))
+	}
+	if goerr.NewMulti("failed", 0, nil, nil) != nil {
+		t.Errorf("Want: nil without causes")
+	}
+	if goerr.Causes(goerr.New(nil, "failed")) != nil {
+		t.Errorf("Want: no causes")
+	}
+}
+
+func TestTranslateMulti(t *testing.T) {
+	err := goerr.Translate(goerr.NewMulti("sync failed", 0, errors.New("a"), errThrottled))
+	if got := goerr.KindOf(err); got != goerr.KindTooManyRequests {
+		t.Errorf("expected a translation matching a cause. Want: %s, Got: %s", goerr.KindTooManyRequests, got)
+	}
+}
+
+func TestNewMultiListStacks(t *testing.T) {
+	errA := goerr.New(goerr.New(nil, "conn refused"), "replica a failed")
+	err := goerr.New(goerr.NewMulti("read failed", http.StatusServiceUnavailable, errA, errors.New("replica b down")), "handler failed")
+
+	stacks := goerr.ListStacks(err)
+	want := []string{"handler failed [", "read failed (503) [", "replica a failed [", "conn refused [", "replica b down"}
+	if len(stacks) != len(want) {
+		t.Fatalf("Want: one entry per layer %v, Got: %q", want, stacks)
+	}
+	for i := range want {
+		if !strings.HasPrefix(stacks[i], want[i]) || strings.Contains(stacks[i], "\n") {
+			t.Errorf("entry %d. Want prefix: %q, Got: %q", i, want[i], stacks[i])
+		}
+	}
+	if entries := goerr.ListEntries(err); len(entries) != len(want) || entries[4].Depth != 2 {
+		t.Errorf("Want: %d entries, the last at depth 2, Got: %v", len(want), entries)
+	}
+}
+
+func TestNewMultiLimits(t *testing.T) {
+	defer func(depth int) { goerr.MaxChainDepth = depth }(goerr.MaxChainDepth)
+	goerr.MaxChainDepth = 4
+
+	var errs []error
+	for i := 0; i < 3; i++ {
+		errs = append(errs, goerr.New(goerr.New(nil, "conn refused"), "replica failed"))
+	}
+	err := goerr.NewMulti("read failed", 0, errs...)
+
+	stacks := goerr.ListStacks(err)
+	if want := "... chain truncated after 4 layers"; len(stacks) != 5 || stacks[4] != want {
+		t.Errorf("expected the layers of all causes to count. Want: 4 entries and %q, Got: %q", want, stacks)
+	}
+	if got := strings.Count(goerr.Stack(err), "conn refused"); got != 1 {
+		t.Errorf("Want: 1 cause rendered in full, Got: %d\n%s", got, goerr.Stack(err))
+	}
+
+	goerr.MaxChainDepth = 100
+	defer func(n int) { goerr.MaxStackBytes = n }(goerr.MaxStackBytes)
+	goerr.MaxStackBytes = 200
+	if got := goerr.Stack(err); len(got) > 300 || !strings.Contains(got, "more frames truncated") {
+		t.Errorf("expected the causes to share the byte limit, Got: %d bytes\n%s", len(got), got)
+	}
+}
@@ -0,0 +1,46 @@
+package goerr
+
+// Must returns v if err is nil, and otherwise panics with err, see Must0. It
+// is meant for main and init paths, where a failure is fatal.
+//
+//	var tmpl = goerr.Must(template.ParseFS(files, "*.tmpl"))
+func Must[T any](v T, err error) T {
+	if err != nil {
+		if _, ok := err.(*Error); !ok {
+			err = layer(err)
+		}
+		panic(&mustError{err: err})
+	}
+	return v
+}
+
+// Must0 panics with err if it is not nil. The panic value is an error
+// wrapping err whose message is the rendered stack of err, so the runtime
+// prints the whole chain when the program crashes, and a recover can still
+// inspect err with errors.Is and errors.As. An error that is not a goerr is
+// first wrapped in a layer attributed to the caller of Must0.
+//
+//	goerr.Must0(db.Ping())
+func Must0(err error) {
+	if err != nil {
+		if _, ok := err.(*Error); !ok {
+			err = layer(err)
+		}
+		panic(&mustError{err: err})
+	}
+}
+
+// mustError is the panic value of Must and Must0.
+type mustError struct {
+	err error
+}
+
+// Error returns the stack of the error.
+func (m *mustError) Error() string {
+	return Stack(m.err)
+}
+
+// Unwrap returns the error.
+func (m *mustError) Unwrap() error {
+	return m.err
+}
@@ -0,0 +1,54 @@
+package goerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+// recovered returns the value fn panics with.
+func recovered(fn func()) (v any) {
+	defer func() { v = recover() }()
+	fn()
+	return nil
+}
+
+func TestMust(t *testing.T) {
+	if want, got := 42, goerr.Must(42, nil); got != want {
+		t.Errorf("Want: %d, Got: %d", want, got)
+	}
+
+	cause := goerr.New(errors.New("connection refused"), "load config failed")
+	v := recovered(func() { goerr.Must(0, cause) })
+	err, ok := v.(error)
+	if !ok {
+		t.Fatalf("expected an error panic value, Got: %v", v)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("expected the panic value to wrap the error")
+	}
+	if want, got := goerr.Stack(cause), err.Error(); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+}
+
+func TestMust0(t *testing.T) {
+	if v := recovered(func() { goerr.Must0(nil) }); v != nil {
+		t.Errorf("expected no panic, Got: %v", v)
+	}
+
+	cause := errors.New("connection refused")
+	v := recovered(func() { goerr.Must0(cause) })
+	err, ok := v.(error)
+	if !ok {
+		t.Fatalf("expected an error panic value, Got: %v", v)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("expected the panic value to wrap the error")
+	}
+	if stack := err.Error(); !strings.Contains(stack, "must_test.go") {
+		t.Errorf("expected the stack to point to the caller of Must0, Got: %s", stack)
+	}
+}
@@ -0,0 +1,106 @@
+package goerr
+
+import "fmt"
+
+// An Option sets an attribute of the layer created by New, NewSkip, NewCtx
+// or Mask, or by NewWithArgs, which takes only options. Wrap and the other
+// constructors taking a plain message take none. Unlike the positional
+// message arguments, whose meaning depends on their order and type, options
+// are typed, so a misplaced one does not compile or is still applied as
+// intended. They can be given anywhere among the message arguments, and are
+// applied in order after them, so a single call can set what otherwise
+// takes a chain of helpers, each copying the layer:
+//
+//	return goerr.New(err,
+//		goerr.Msg("order %s conflicts", id),
+//		goerr.Coded(http.StatusConflict),
+//		goerr.OfKind(goerr.KindConflict),
+//		goerr.F("order_id", id),
+//		goerr.Public("the order was changed, try again"))
+type Option func(e *Error)
+
+// Msg sets the message of the layer, formatted like fmt.Sprintf, overriding
+// a message given positionally.
+func Msg(format string, args ...any) Option {
+	return func(e *Error) {
+		if strictMode.Load() {
+			checkFormat(format, args)
+		}
+		e.message = fmt.Sprintf(format, args...)
+	}
+}
+
+// Coded sets the code of the layer, overriding a code given as the first
+// message argument.
+func Coded(code int) Option {
+	return func(e *Error) {
+		e.code = code
+	}
+}
+
+// OfKind sets the kind of the layer, see WithKind.
+func OfKind(kind Kind) Option {
+	return func(e *Error) {
+		e.kind = kind
+	}
+}
+
+// OfSeverity sets the severity of the layer, see WithSeverity.
+func OfSeverity(level SeverityLevel) Option {
+	return func(e *Error) {
+		e.severity = level
+	}
+}
+
+// F sets the field key of the layer to value, see WithField.
+func F(key string, value any) Option {
+	return func(e *Error) {
+		if e.fields == nil {
+			e.fields = map[string]any{}
+		}
+		e.fields[key] = value
+	}
+}
+
+// Public sets the message shown by public renderers, like the JSON marshaler
+// and PublicMessage, in place of the message of the layer, which Stack still
+// renders. Like a layer created by Mask, the layer then hides the layers it
+// wraps from public renderers.
+func Public(message string) Option {
+	return func(e *Error) {
+		e.public = message
+		e.masks = true
+	}
+}
+
+// splitOptions splits the message arguments into the positional ones and
+// the options, which can be anywhere among them.
+func splitOptions(message []any) (args, opts []any) {
+	n := len(message)
+	for n > 0 {
+		if _, ok := message[n-1].(Option); !ok {
+			break
+		}
+		n--
+	}
+	args, opts = message[:n], message[n:]
+
+	for i, arg := range args {
+		if _, ok := arg.(Option); !ok {
+			continue
+		}
+		// an option before positional arguments, copy both so message is
+		// not modified
+		positional := append([]any(nil), args[:i]...)
+		var leading []any
+		for _, arg := range args[i:] {
+			if _, ok := arg.(Option); ok {
+				leading = append(leading, arg)
+			} else {
+				positional = append(positional, arg)
+			}
+		}
+		return positional, append(leading, opts...)
+	}
+	return args, opts
+}
@@ -0,0 +1,105 @@
+package goerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestOptions(t *testing.T) {
+	err := goerr.New(errors.New("pq: serialization failure"), "order %s conflicts", "o-1",
+		goerr.Coded(http.StatusConflict),
+		goerr.OfKind(goerr.KindConflict),
+		goerr.F("order_id", "o-1"),
+		goerr.F("attempt", 2),
+		goerr.Public("the order was changed, try again"))
+
+	if want, got := "order o-1 conflicts", err.Error(); got != want {
+		t.Errorf("message. Want: %s, Got: %s", want, got)
+	}
+	if want, got := http.StatusConflict, goerr.Code(err); got != want {
+		t.Errorf("code. Want: %d, Got: %d", want, got)
+	}
+	if want, got := goerr.KindConflict, goerr.KindOf(err); got != want {
+		t.Errorf("kind. Want: %s, Got: %s", want, got)
+	}
+	if want, got := (map[string]any{"order_id": "o-1", "attempt": 2}), goerr.Fields(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("fields. Want: %v, Got: %v", want, got)
+	}
+	if want, got := "the order was changed, try again", goerr.PublicMessage(err); got != want {
+		t.Errorf("public message. Want: %s, Got: %s", want, got)
+	}
+
+	b, _ := json.Marshal(err)
+	if strings.Contains(string(b), "conflicts") || strings.Contains(string(b), "serialization") {
+		t.Errorf("json contains the internal messages: %s", b)
+	}
+	if stack := goerr.Stack(err); !strings.Contains(stack, "order o-1 conflicts") {
+		t.Errorf("stack does not contain the message: %s", stack)
+	}
+}
+
+func TestOptionsOverridePositionalCode(t *testing.T) {
+	err := goerr.New(nil, http.StatusBadRequest, "invalid", goerr.Coded(http.StatusUnprocessableEntity))
+
+	if want, got := http.StatusUnprocessableEntity, goerr.Code(err); got != want {
+		t.Errorf("Want: %d, Got: %d", want, got)
+	}
+	if want, got := "invalid", err.Error(); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+}
+
+func TestOptionsOnly(t *testing.T) {
+	err := goerr.New(errors.New("timeout"), goerr.F("host", "db-1"))
+
+	if want, got := "timeout", err.Error(); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+	if want, got := "db-1", goerr.Fields(err)["host"]; got != want {
+		t.Errorf("Want: %s, Got: %v", want, got)
+	}
+}
+
+func TestOptionsTyped(t *testing.T) {
+	err := goerr.New(errors.New("no rows"),
+		goerr.Msg("order %s not found", "o-1"),
+		goerr.Coded(http.StatusNotFound),
+		goerr.OfSeverity(goerr.SeverityWarning))
+
+	if want, got := "order o-1 not found", err.Error(); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+	if want, got := http.StatusNotFound, goerr.Code(err); got != want {
+		t.Errorf("Want: %d, Got: %d", want, got)
+	}
+	if want, got := goerr.SeverityWarning, goerr.Severity(err); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+	if want, got := "order o-2 not found", goerr.New(nil, "placeholder", goerr.Msg("order %s not found", "o-2")).Error(); got != want {
+		t.Errorf("expected Msg to override the positional message. Want: %s, Got: %s", want, got)
+	}
+}
+
+func TestOptionsAnywhere(t *testing.T) {
+	message := []any{goerr.Coded(http.StatusConflict), "order %s conflicts", goerr.F("attempt", 2), "o-1"}
+	err := goerr.New(nil, message...)
+
+	if want, got := "order o-1 conflicts", err.Error(); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+	if want, got := http.StatusConflict, goerr.Code(err); got != want {
+		t.Errorf("Want: %d, Got: %d", want, got)
+	}
+	if want, got := 2, goerr.Fields(err)["attempt"]; got != want {
+		t.Errorf("Want: %d, Got: %v", want, got)
+	}
+	if _, ok := message[1].(string); !ok {
+		t.Errorf("expected the arguments not to be modified, Got: %v", message)
+	}
+}
@@ -0,0 +1,209 @@
+package goerr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseStack parses the text of a stack rendered by Stack, e.g. from the logs
+// of an older version of a service, back into one Frame per line of a
+// layer, outermost first. It recovers the message, code, severity, fields,
+// goroutine, deadline, location and depth of every layer. Field values are
+// parsed as strings, and the kind of a layer, which Stack does not render,
+// is left empty, and so is the message of a layer created by Trace. A line
+// collapsing repeated layers gives a single frame. The lines written after
+// the line of a layer, like warnings, the call stacks of layers carried
+// across goroutines, source code, truncation markers and the build
+// metadata, are skipped. Stacks rendered with a FrameFormatter are not
+// supported.
+//
+// Since messages can contain any text, a message ending in what looks like
+// a code, a severity or fields is parsed as such. ParseStack returns an
+// error if the indentation of the lines is not the one of a stack.
+func ParseStack(s string) ([]Frame, error) {
+	s = strings.TrimPrefix(s, "\n")
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var frames []Frame
+	for i, line := range strings.Split(s, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		rest := strings.TrimLeft(line, "\t")
+		depth := len(line) - len(rest)
+		if rest == "" || isAuxiliaryLine(rest) {
+			continue
+		}
+		if len(frames) == 0 && depth != 0 {
+			return nil, fmt.Errorf("goerr: line %d: first layer indented by %d", i+1, depth)
+		}
+		if len(frames) > 0 && depth > frames[len(frames)-1].Depth+1 {
+			return nil, fmt.Errorf("goerr: line %d: layer indented by %d below a layer at depth %d", i+1, depth, frames[len(frames)-1].Depth)
+		}
+
+		f := parseLine(rest)
+		f.Depth = depth
+		frames = append(frames, f)
+	}
+	return frames, nil
+}
+
+// isAuxiliaryLine reports whether line, without its indentation, is written
+// after the line of a layer rather than being the line of a layer.
+func isAuxiliaryLine(line string) bool {
+	switch {
+	case strings.HasPrefix(line, "warning: "), strings.HasPrefix(line, "... "), strings.HasPrefix(line, "build: "):
+		return true
+	case strings.HasPrefix(line, "at ") && strings.HasSuffix(line, ")") && !strings.Contains(line, " ["):
+		// a frame of a layer carried across goroutines, see writeCarried
+		_, _, _, ok := parseLocation(line[3:])
+		return ok
+	}
+	// source code, like ">   42 | return err"
+	if len(line) >= 9 && (line[0] == ' ' || line[0] == '>') {
+		if bar := strings.Index(line, " | "); bar > 1 {
+			_, err := strconv.Atoi(strings.TrimLeft(line[1:bar], " "))
+			return err == nil
+		}
+	}
+	return false
+}
+
+// parseLine parses the line of a layer, see writeLine, from its end.
+func parseLine(line string) Frame {
+	var f Frame
+
+	// the time elapsed and the number of repeats are written after the line
+	if i := strings.LastIndex(line, " +"); i >= 0 {
+		if _, err := time.ParseDuration(line[i+2:]); err == nil {
+			line = line[:i]
+		}
+	}
+	if i := strings.LastIndex(line, " x"); i >= 0 {
+		if n, err := strconv.Atoi(line[i+2:]); err == nil && n > 1 {
+			line = line[:i]
+		}
+	}
+
+	if strings.HasPrefix(line, traceMarker) {
+		// a layer created by Trace, which has no message of its own
+		if rest := strings.TrimPrefix(line, traceMarker); strings.HasPrefix(rest, " at ") {
+			if file, num, fn, ok := parseLocation(rest[len(" at "):]); ok {
+				f.File, f.Line, f.Function = file, num, fn
+			}
+		}
+		return f
+	}
+
+	if strings.HasSuffix(line, ")]") {
+		if i := strings.LastIndex(line, " ["); i >= 0 {
+			if file, num, fn, ok := parseLocation(line[i+2 : len(line)-1]); ok {
+				f.File, f.Line, f.Function = file, num, fn
+				line = line[:i]
+			}
+		}
+	}
+
+	if t, i := parseDeadline(line); i >= 0 {
+		f.Deadline = t
+		line = line[:i]
+	}
+	if strings.HasSuffix(line, ">") {
+		if i := strings.LastIndex(line, " <goroutine "); i >= 0 {
+			if id, err := strconv.ParseUint(line[i+len(" <goroutine "):len(line)-1], 10, 64); err == nil {
+				f.Goroutine = id
+				line = line[:i]
+			}
+		}
+	}
+
+	// fields are the trailing key=value tokens, after the code and severity
+	start := 0
+	if i := strings.LastIndex(line, "} "); i >= 0 && parseSeverity(line[:i+1]) != 0 {
+		start = i + 1
+	} else if i := strings.LastIndex(line, ") "); i >= 0 && parseCode(line[:i+1]) != 0 {
+		start = i + 1
+	}
+	end := len(line)
+	for {
+		i := strings.LastIndexByte(line[:end], ' ')
+		if i < start {
+			break
+		}
+		k, v, ok := strings.Cut(line[i+1:end], "=")
+		if !ok || k == "" {
+			break
+		}
+		if f.Fields == nil {
+			f.Fields = map[string]any{}
+		}
+		f.Fields[k] = v
+		end = i
+	}
+	line = line[:end]
+
+	if s := parseSeverity(line); s != 0 {
+		f.Severity = s
+		line = line[:strings.LastIndex(line, " {")]
+	}
+	if c := parseCode(line); c != 0 {
+		f.Code = c
+		line = line[:strings.LastIndex(line, " (")]
+	}
+	f.Message = line
+	return f
+}
+
+// parseLocation parses "file:line (function)".
+func parseLocation(s string) (file string, line int, fn string, ok bool) {
+	i := strings.Index(s, " (")
+	for i >= 0 {
+		colon := strings.LastIndexByte(s[:i], ':')
+		if colon >= 0 {
+			if n, err := strconv.Atoi(s[colon+1 : i]); err == nil && n >= 0 {
+				return s[:colon], n, s[i+2 : len(s)-1], true
+			}
+		}
+		next := strings.Index(s[i+2:], " (")
+		if next < 0 {
+			break
+		}
+		i += 2 + next
+	}
+	return "", 0, "", false
+}
+
+// parseSeverity returns the severity line ends in, like " {critical}", or 0.
+func parseSeverity(line string) SeverityLevel {
+	if !strings.HasSuffix(line, "}") {
+		return 0
+	}
+	i := strings.LastIndex(line, " {")
+	if i < 0 {
+		return 0
+	}
+	for s := SeverityWarning; s <= SeverityCritical; s++ {
+		if line[i+2:len(line)-1] == s.String() {
+			return s
+		}
+	}
+	return 0
+}
+
+// parseCode returns the code line ends in, like " (404)", or 0.
+func parseCode(line string) int {
+	if !strings.HasSuffix(line, ")") {
+		return 0
+	}
+	i := strings.LastIndex(line, " (")
+	if i < 0 {
+		return 0
+	}
+	c, err := strconv.Atoi(line[i+2 : len(line)-1])
+	if err != nil {
+		return 0
+	}
+	return c
+}
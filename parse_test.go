@@ -0,0 +1,92 @@
+package goerr_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestParseStack(t *testing.T) {
+	inner := goerr.New(errors.New("connection refused"), 503, "query failed")
+	inner = goerr.WithSeverity(goerr.WithField(inner, "table", "orders"), goerr.SeverityCritical)
+	err := goerr.New(inner, "load order failed: db (primary)")
+
+	frames, perr := goerr.ParseStack(goerr.Stack(err))
+	if perr != nil {
+		t.Fatal(perr)
+	}
+
+	var want []goerr.Frame
+	goerr.Walk(err, func(l goerr.Layer) bool {
+		f := l.Frame
+		f.Kind = ""
+		for k, v := range f.Fields {
+			f.Fields[k] = v.(string)
+		}
+		want = append(want, f)
+		return true
+	})
+	if !reflect.DeepEqual(frames, want) {
+		t.Errorf("Want: %+v, Got: %+v", want, frames)
+	}
+}
+
+func TestParseStackSkipsAuxiliaryLines(t *testing.T) {
+	stack := strings.Join([]string{
+		"",
+		"sync failed (500) [app/sync.go:20 (app.Sync)] x3 +1.5s",
+		"\twarning: cache stale",
+		"\tat app/worker.go:12 (app.work)",
+		"\tat capacity (503)",
+		"\t\t>   12 | return err",
+		"\t\t... 2 more frames truncated",
+	}, "\n")
+
+	frames, err := goerr.ParseStack(stack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []goerr.Frame{
+		{Message: "sync failed", Code: 500, File: "app/sync.go", Line: 20, Function: "app.Sync"},
+		{Message: "at capacity", Code: 503, Depth: 1},
+	}
+	if !reflect.DeepEqual(frames, want) {
+		t.Errorf("Want: %+v, Got: %+v", want, frames)
+	}
+}
+
+func TestParseStackInvalid(t *testing.T) {
+	for _, s := range []string{"\tindented first layer", "\nouter\n\t\t\tinner"} {
+		if _, err := goerr.ParseStack(s); err == nil {
+			t.Errorf("expected an error for %q", s)
+		}
+	}
+	if frames, err := goerr.ParseStack(""); frames != nil || err != nil {
+		t.Errorf("Want: nil, nil, Got: %v, %v", frames, err)
+	}
+}
+
+func FuzzParseStack(f *testing.F) {
+	f.Add(goerr.Stack(goerr.New(goerr.New(errors.New("timeout"), 504, "call failed"), "handler failed")))
+	f.Add(goerr.Stack(goerr.WithField(goerr.New(nil, "failed"), "id", 7)))
+	f.Add("a (1) {error} k=v <goroutine 3> [f.go:1 (m.f)] x2 +1s")
+	f.Add("\n\tat x:1 (y)\n) ] [ (:")
+
+	f.Fuzz(func(t *testing.T, s string) {
+		frames, err := goerr.ParseStack(s)
+		if err != nil {
+			return
+		}
+		for i, fr := range frames {
+			if fr.Depth < 0 || fr.Line < 0 {
+				t.Errorf("frame %d has a negative depth or line: %+v", i, fr)
+			}
+			if i > 0 && fr.Depth > frames[i-1].Depth+1 {
+				t.Errorf("frame %d is indented by more than one level: %+v", i, fr)
+			}
+		}
+	})
+}
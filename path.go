@@ -0,0 +1,112 @@
+package goerr
+
+import (
+	"go/build"
+	"path"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// PathMode controls how file paths are rendered in stack lines.
+type PathMode int32
+
+const (
+	// PathFull renders the absolute path of the file. This is the default.
+	PathFull PathMode = iota
+	// PathTrimGOPATH removes the GOPATH (including the module cache) or GOROOT
+	// prefix from paths inside them. Other paths are rendered in full.
+	PathTrimGOPATH
+	// PathModuleRelative renders the path relative to the root of the module
+	// containing the file, e.g. samplesrc/samples.go. Files of packages not
+	// belonging to a known module are rendered with their import path.
+	PathModuleRelative
+	// PathBasename renders only the name of the file, e.g. samples.go.
+	PathBasename
+)
+
+var pathMode atomic.Int32
+
+// SetPathMode sets how file paths are rendered by Stack and ListStacks.
+// Paths returned by Error.File and StackFrame are never changed.
+func SetPathMode(m PathMode) {
+	pathMode.Store(int32(m))
+}
+
+// formatPath renders the file of frame in the PathMode of f, or the
+// configured one if f is nil.
+func formatPath(f *Factory, frame *StackFrame) string {
+	mode := PathMode(pathMode.Load())
+	if f != nil {
+		mode = f.opts.PathMode
+	}
+
+	switch mode {
+	case PathTrimGOPATH:
+		return trimGOPATH(frame.File)
+	case PathModuleRelative:
+		return moduleRelative(frame)
+	case PathBasename:
+		return path.Base(filepath.ToSlash(frame.File))
+	}
+	return frame.File
+}
+
+func trimGOPATH(file string) string {
+	file = filepath.ToSlash(file)
+
+	var prefixes []string
+	for _, gopath := range filepath.SplitList(build.Default.GOPATH) {
+		gopath = filepath.ToSlash(gopath)
+		prefixes = append(prefixes, gopath+"/pkg/mod/", gopath+"/src/")
+	}
+	if build.Default.GOROOT != "" {
+		prefixes = append(prefixes, filepath.ToSlash(build.Default.GOROOT)+"/src/")
+	}
+
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(file, prefix) {
+			return file[len(prefix):]
+		}
+	}
+	return file
+}
+
+var (
+	modulesOnce sync.Once
+	modules     []string
+)
+
+// moduleRelative renders the file of frame relative to the root of the
+// module that contains the package of the frame, as recorded in the build
+// information of the binary.
+func moduleRelative(frame *StackFrame) string {
+	modulesOnce.Do(func() {
+		info, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+		modules = append(modules, info.Main.Path)
+		for _, dep := range info.Deps {
+			modules = append(modules, dep.Path)
+		}
+	})
+
+	base := path.Base(filepath.ToSlash(frame.File))
+	if frame.Package == "" {
+		return base
+	}
+
+	module := ""
+	for _, m := range modules {
+		if m != "" && len(m) > len(module) && (frame.Package == m || strings.HasPrefix(frame.Package, m+"/")) {
+			module = m
+		}
+	}
+	if module == "" {
+		return frame.Package + "/" + base
+	}
+	return path.Join(strings.TrimPrefix(strings.TrimPrefix(frame.Package, module), "/"), base)
+}
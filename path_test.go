@@ -0,0 +1,32 @@
+package goerr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+func TestSetPathMode(t *testing.T) {
+	defer goerr.SetPathMode(goerr.PathFull)
+
+	err := samplesrc.Repository()
+
+	tests := []struct {
+		mode goerr.PathMode
+		want string
+	}{
+		{goerr.PathFull, "/goerr/samplesrc/samples.go:27 ("},
+		{goerr.PathModuleRelative, " [samplesrc/samples.go:27 ("},
+		{goerr.PathBasename, " [samples.go:27 ("},
+	}
+	for _, tt := range tests {
+		goerr.SetPathMode(tt.mode)
+
+		got := goerr.Stack(err)
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("mode %d. Want: %s; Got: %s", tt.mode, tt.want, got)
+		}
+	}
+}
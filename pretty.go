@@ -0,0 +1,119 @@
+package goerr
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// ANSI escape sequences used by Pretty.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiDim    = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+	ansiWhite  = "\x1b[97m"
+)
+
+var prettyMode atomic.Bool
+
+func init() {
+	prettyMode.Store(os.Getenv("GOERR_PRETTY") == "1")
+}
+
+// SetPretty turns pretty mode on or off, in which the logging integrations
+// render errors with Pretty. It is on if the environment variable
+// GOERR_PRETTY is set to 1 when the program starts, which is meant for local
+// development terminals.
+func SetPretty(on bool) {
+	prettyMode.Store(on)
+}
+
+// PrettyMode reports whether pretty mode is on, see SetPretty.
+func PrettyMode() bool {
+	return prettyMode.Load()
+}
+
+// Pretty renders the layers of err for humans in a terminal: one layer per
+// line, indented by depth, with messages in white, codes highlighted (5xx in
+// red, others in yellow), fields and locations dimmed, and the locations
+// aligned in a column.
+func Pretty(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	type line struct {
+		head  string // message, code and fields with colors
+		width int    // width of head without colors
+		loc   string
+	}
+
+	var lines []line
+	width := 0
+	r := walk(err, func(depth int, err error) bool {
+		f := newFrame(depth, err)
+
+		var b strings.Builder
+		w := 2*depth + utf8.RuneCountInString(f.Message)
+		b.WriteString(strings.Repeat("  ", depth))
+		b.WriteString(ansiWhite + f.Message + ansiReset)
+		if f.Code != 0 {
+			code := strconv.Itoa(f.Code)
+			color := ansiYellow
+			if f.Code >= 500 {
+				color = ansiRed
+			}
+			b.WriteString(" " + ansiBold + color + code + ansiReset)
+			w += 1 + len(code)
+		}
+		if len(f.Fields) > 0 {
+			var fb strings.Builder
+			writeSortedFields(&fb, f.Fields)
+			b.WriteString(ansiDim + fb.String() + ansiReset)
+			w += utf8.RuneCountInString(fb.String())
+		}
+
+		l := line{head: b.String(), width: w}
+		if f.File != "" {
+			l.loc = ansiDim + f.File + ":" + strconv.Itoa(f.Line) + ansiReset + " " + ansiCyan + f.Function + ansiReset
+		}
+		if w > width {
+			width = w
+		}
+		lines = append(lines, l)
+		return true
+	})
+
+	var b strings.Builder
+	for i, l := range lines {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(l.head)
+		if l.loc != "" {
+			b.WriteString(strings.Repeat(" ", width-l.width+2))
+			b.WriteString(l.loc)
+		}
+	}
+	if r != walkDone {
+		b.WriteString("\n" + ansiDim + r.marker() + ansiReset)
+	}
+	return b.String()
+}
+
+// writeSortedFields writes fields as " key=value" pairs sorted by key, like
+// the stack lines do.
+func writeSortedFields(b *strings.Builder, fields map[string]any) {
+	for _, k := range sortedKeys(fields) {
+		b.WriteByte(' ')
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(fmt.Sprint(fields[k]))
+	}
+}
@@ -0,0 +1,61 @@
+package goerr_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+var ansi = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestPretty(t *testing.T) {
+	err := goerr.WithField(goerr.New(samplesrc.Service(), 503, "controller failed"), "order_id", 42)
+
+	got := goerr.Pretty(err)
+	t.Log("\n" + got)
+
+	if !strings.Contains(got, "\x1b[1m\x1b[31m503\x1b[0m") {
+		t.Errorf("expected a highlighted code, Got: %q", got)
+	}
+
+	lines := strings.Split(ansi.ReplaceAllString(got, ""), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Nr. of lines. Want: %d, Got: %d", 3, len(lines))
+	}
+	wants := []string{
+		`^controller failed 503 order_id=42  +/.*pretty_test.go:\d+ goerr_test.TestPretty$`,
+		`^  service failed +/.*samplesrc/samples.go:20 samplesrc.Service$`,
+		`^    error from database +/.*samplesrc/samples.go:27 samplesrc.Repository$`,
+	}
+	column := -1
+	for i, want := range wants {
+		if !regexp.MustCompile(want).MatchString(lines[i]) {
+			t.Errorf("line %d. Want: %s, Got: %s", i, want, lines[i])
+		}
+		if c := strings.Index(lines[i], " /"); column == -1 {
+			column = c
+		} else if c != column {
+			t.Errorf("line %d: expected aligned locations, Got: %s", i, lines[i])
+		}
+	}
+
+	if goerr.Pretty(nil) != "" {
+		t.Errorf("expected an empty string for nil")
+	}
+}
+
+func TestPrettyMode(t *testing.T) {
+	defer goerr.SetPretty(goerr.PrettyMode())
+
+	goerr.SetPretty(true)
+	if !goerr.PrettyMode() {
+		t.Errorf("expected pretty mode")
+	}
+	goerr.SetPretty(false)
+	if goerr.PrettyMode() {
+		t.Errorf("expected no pretty mode")
+	}
+}
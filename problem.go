@@ -0,0 +1,192 @@
+package goerr
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ProblemTypeBase is the URI prefix of the type of problems. If it is set,
+// the type of a problem is the prefix followed by the kind of the error, e.g.
+// https://errors.example.com/not_found, otherwise it is "about:blank".
+var ProblemTypeBase = ""
+
+// Problem is an RFC 7807 problem details document.
+type Problem struct {
+	Type     string
+	Title    string
+	Status   int
+	Detail   string
+	Instance string
+	// Extensions are additional members of the document.
+	Extensions map[string]any
+}
+
+// StackExtension is the extension of a problem holding the stack of the
+// error, see ExposeStacks.
+const StackExtension = "stack"
+
+// A ProblemOption configures ToProblem and WriteProblem.
+type ProblemOption func(*problemOptions)
+
+type problemOptions struct {
+	stacks bool
+}
+
+// ExposeStacks adds the stack lines of all layers of the error, including
+// the ones masked or hidden from public renderers, as the StackExtension
+// extension, see ListStacks. It is meant for internal debugging endpoints,
+// never for customer facing APIs.
+func ExposeStacks() ProblemOption {
+	return func(o *problemOptions) {
+		o.stacks = true
+	}
+}
+
+// ToProblem returns the problem details of err, built from the layers that
+// are visible to public renderers like the JSON marshaler: the status is
+// ProblemStatus(err), the title is the text of the status,
+// the detail is the message of err, and the fields, string code, details and
+// warnings of err are extensions. The instance is left empty.
+func ToProblem(err error, opts ...ProblemOption) Problem {
+	var o problemOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	j := newJSONError(err)
+
+	p := Problem{
+		Type:   "about:blank",
+		Status: problemStatus(err, j.Code),
+		Detail: j.Message,
+	}
+	p.Title = http.StatusText(p.Status)
+	if kind := kindOfCode(p.Status); ProblemTypeBase != "" && kind != "" {
+		p.Type = ProblemTypeBase + string(kind)
+	}
+
+	for _, l := range j.Layers {
+		for k, v := range l.Fields {
+			if _, ok := p.Extensions[k]; ok {
+				continue
+			}
+			if p.Extensions == nil {
+				p.Extensions = map[string]any{}
+			}
+			p.Extensions[k] = v
+		}
+	}
+	if j.CodeString != "" {
+		if p.Extensions == nil {
+			p.Extensions = map[string]any{}
+		}
+		p.Extensions["code_string"] = j.CodeString
+	}
+	if j.Codes != nil {
+		if p.Extensions == nil {
+			p.Extensions = map[string]any{}
+		}
+		p.Extensions["codes"] = j.Codes
+	}
+	if j.Details != nil {
+		if p.Extensions == nil {
+			p.Extensions = map[string]any{}
+		}
+		p.Extensions["details"] = j.Details
+	}
+	if j.Warnings != nil {
+		if p.Extensions == nil {
+			p.Extensions = map[string]any{}
+		}
+		p.Extensions["warnings"] = j.Warnings
+	}
+	if o.stacks {
+		if p.Extensions == nil {
+			p.Extensions = map[string]any{}
+		}
+		p.Extensions[StackExtension] = ListStacks(err)
+	}
+	return p
+}
+
+// ProblemStatus returns the HTTP status answering err: Code(err), or, if a
+// layer created by Mask replaces the chain for public renderers, the code of
+// the layers visible to them, so a masked code does not leak. Codes that are
+// not client or server error statuses, like business codes or none, are
+// answered with 500.
+func ProblemStatus(err error) int {
+	return problemStatus(err, newJSONHead(err).Code)
+}
+
+// problemStatus implements ProblemStatus, with public the code of the layers
+// of err visible to public renderers.
+func problemStatus(err error, public int) int {
+	status := Code(err)
+	if masked(err) {
+		status = public
+	}
+	if status < 400 || status > 599 {
+		return http.StatusInternalServerError
+	}
+	return status
+}
+
+// masked reports whether a layer of err created by Mask replaces the layers
+// below it for public renderers.
+func masked(err error) bool {
+	masks := false
+	walkPublic(err, func(err error) bool {
+		e, ok := err.(*Error)
+		masks = ok && e.masks
+		return true
+	})
+	return masks
+}
+
+// MarshalJSON renders the problem as an application/problem+json document,
+// with the extensions as top level members. Extensions named like a standard
+// member are left out.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	doc := make(map[string]any, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		doc[k] = v
+	}
+	doc["type"] = p.Type
+	doc["title"] = p.Title
+	doc["status"] = p.Status
+	if p.Detail != "" {
+		doc["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		doc["instance"] = p.Instance
+	}
+	return json.Marshal(doc)
+}
+
+// WriteProblem writes the problem details of err as the response to r, with
+// the path of r as the instance.
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error, opts ...ProblemOption) {
+	p := ToProblem(err, opts...)
+	p.Instance = r.URL.Path
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// ProblemHandler returns a handler calling fn and responding with the
+// problem details of the error it returns, if any.
+//
+//	mux.Handle("/orders", goerr.ProblemHandler(func(w http.ResponseWriter, r *http.Request) error {
+//		order, err := svc.Order(r.Context(), r.URL.Query().Get("id"))
+//		if err != nil {
+//			return err
+//		}
+//		return json.NewEncoder(w).Encode(order)
+//	}))
+func ProblemHandler(fn func(w http.ResponseWriter, r *http.Request) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := fn(w, r); err != nil {
+			WriteProblem(w, r, err)
+		}
+	})
+}
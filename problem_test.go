@@ -0,0 +1,127 @@
+package goerr_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestToProblem(t *testing.T) {
+	defer func(base string) { goerr.ProblemTypeBase = base }(goerr.ProblemTypeBase)
+	goerr.ProblemTypeBase = "https://errors.example.com/"
+
+	err := goerr.WithField(goerr.New(errors.New("no rows"), http.StatusNotFound, "order not found"), "order_id", 42)
+	err = goerr.WithDetails(err, []string{"check the id"})
+
+	p := goerr.ToProblem(err)
+	if p.Type != "https://errors.example.com/not_found" || p.Title != "Not Found" || p.Status != http.StatusNotFound || p.Detail != "order not found" {
+		t.Errorf("unexpected problem: %+v", p)
+	}
+
+	b, _ := json.Marshal(p)
+	var doc map[string]any
+	json.Unmarshal(b, &doc)
+	wants := map[string]any{
+		"type":     "https://errors.example.com/not_found",
+		"title":    "Not Found",
+		"status":   float64(404),
+		"detail":   "order not found",
+		"order_id": float64(42),
+	}
+	for k, v := range wants {
+		if doc[k] != v {
+			t.Errorf("%s. Want: %v, Got: %v", k, v, doc[k])
+		}
+	}
+	if details, ok := doc["details"].([]any); !ok || len(details) != 1 {
+		t.Errorf("unexpected details: %v", doc["details"])
+	}
+}
+
+func TestToProblemMasked(t *testing.T) {
+	err := goerr.Mask(goerr.WithField(goerr.New(nil, http.StatusNotFound, "no rows"), "query", "select"), "internal error")
+
+	p := goerr.ToProblem(err)
+	if p.Type != "about:blank" || p.Status != http.StatusInternalServerError || p.Detail != "internal error" || p.Extensions != nil {
+		t.Errorf("unexpected problem: %+v", p)
+	}
+}
+
+func TestProblemStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{goerr.New(sql.ErrNoRows, "order not found"), http.StatusNotFound},
+		{goerr.WithKind(goerr.New(nil, "order exists"), goerr.KindConflict), http.StatusConflict},
+		{goerr.New(goerr.New(nil, 429, "slow down"), "quote failed"), http.StatusTooManyRequests},
+		{goerr.New(nil, 1042, "business rule"), http.StatusInternalServerError},
+		{goerr.New(nil, http.StatusFound, "moved"), http.StatusInternalServerError},
+		{goerr.Mask(goerr.New(nil, http.StatusNotFound, "no rows"), "internal error"), http.StatusInternalServerError},
+		{goerr.Mask(goerr.New(nil, http.StatusNotFound, "no rows"), http.StatusBadGateway, "upstream failed"), http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		if got := goerr.ToProblem(tt.err).Status; got != tt.want {
+			t.Errorf("%v. Want: %d, Got: %d", tt.err, tt.want, got)
+		}
+		if got := goerr.ProblemStatus(tt.err); got != tt.want {
+			t.Errorf("%v. Want: %d, Got: %d", tt.err, tt.want, got)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	goerr.WriteProblem(rec, httptest.NewRequest(http.MethodGet, "/rules", nil), goerr.New(nil, 1042, "business rule"))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Want: 500, Got: %d", rec.Code)
+	}
+}
+
+func TestProblemHandler(t *testing.T) {
+	h := goerr.ProblemHandler(func(w http.ResponseWriter, r *http.Request) error {
+		if r.URL.Path == "/ok" {
+			w.Write([]byte("ok"))
+			return nil
+		}
+		return goerr.Conflict(nil, "order exists")
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders", nil))
+
+	if rec.Code != http.StatusConflict || rec.Header().Get("Content-Type") != "application/problem+json" {
+		t.Errorf("unexpected response: %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	var doc map[string]any
+	json.Unmarshal(rec.Body.Bytes(), &doc)
+	if doc["instance"] != "/orders" || doc["detail"] != "order exists" {
+		t.Errorf("unexpected document: %s", rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ok", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+		t.Errorf("unexpected response: %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestToProblemExposeStacks(t *testing.T) {
+	err := goerr.Mask(goerr.New(errors.New("no rows"), "select failed"), http.StatusInternalServerError, "internal error")
+
+	if _, ok := goerr.ToProblem(err).Extensions[goerr.StackExtension]; ok {
+		t.Errorf("expected no stack by default")
+	}
+
+	p := goerr.ToProblem(err, goerr.ExposeStacks())
+	if want, got := goerr.ListStacks(err), p.Extensions[goerr.StackExtension]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Want: %v, Got: %v", want, got)
+	}
+	if want, got := "internal error", p.Detail; got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+}
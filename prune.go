@@ -0,0 +1,79 @@
+package goerr
+
+// Prune returns a copy of err without the goerr layers for which drop
+// returns true, e.g. the layers of generated code or of noisy middleware.
+// drop is given the Frame of every layer, as Walk and FrameFormatter see
+// it. The code, kind, severity, fields and other attributes of a removed
+// layer are moved to the nearest kept layer above it where that has none,
+// or, if no layer above it is kept, set on the nearest kept layer below it,
+// so Code, Severity, Fields and the like return the same for the pruned
+// chain, see Compact. Layers created by Mask or hidden with Hide are never
+// removed, so public renderers show the same for the pruned chain. If drop
+// returns true for every layer, the innermost one is kept. err is returned
+// unchanged if no layer is removed.
+//
+// Only the goerr layers above the first error of the chain that is not a
+// goerr layer are pruned; that error, usually the root cause, and the ones
+// it wraps are kept.
+//
+//	err = goerr.Prune(err, func(f goerr.Frame) bool {
+//		return strings.HasSuffix(f.File, ".pb.go")
+//	})
+func Prune(err error, drop func(Frame) bool) error {
+	var layers []*Error
+	var tail error
+	for next := err; next != nil && len(layers) < MaxChainDepth; {
+		e, ok := next.(*Error)
+		if !ok {
+			tail = next
+			break
+		}
+		layers = append(layers, e)
+		next = e.err
+	}
+	if len(layers) == MaxChainDepth {
+		tail = layers[len(layers)-1].err
+	}
+
+	var kept []*Error
+	var pending Error
+	dropped := false
+	for depth, e := range layers {
+		if !e.masks && !e.hidden && drop(newFrame(depth, e)) && (len(kept) > 0 || depth < len(layers)-1) {
+			dropped = true
+			if len(kept) > 0 {
+				kept[len(kept)-1].absorb(e)
+			} else {
+				pending.absorb(e)
+			}
+			continue
+		}
+
+		c := *e
+		if len(kept) == 0 {
+			c.override(&pending)
+		}
+		kept = append(kept, &c)
+	}
+	if !dropped {
+		return err
+	}
+
+	for i := 0; i < len(kept)-1; i++ {
+		kept[i].err = kept[i+1]
+	}
+	kept[len(kept)-1].err = tail
+	return kept[0]
+}
+
+// override sets the attributes of o on e where o has them, as if o were the
+// layer wrapping e, see absorb. A kind of o implying a code clears the code
+// of e.
+func (e *Error) override(o *Error) {
+	m := *o
+	m.absorb(e)
+	e.code, e.codeStr, e.kind, e.severity = m.code, m.codeStr, m.kind, m.severity
+	e.def, e.details, e.retry, e.exitCode = m.def, m.details, m.retry, m.exitCode
+	e.weight, e.weighted = m.weight, m.weighted
+	e.fields, e.codes, e.warnings = m.fields, m.codes, m.warnings
+}
@@ -0,0 +1,102 @@
+package goerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestPrune(t *testing.T) {
+	root := errors.New("connection reset")
+	err := goerr.New(root, "query failed")
+	err = goerr.New(err, http.StatusBadGateway, "middleware: request failed")
+	err = goerr.New(err, "load order failed")
+	err = goerr.New(err, "middleware: recovered")
+
+	pruned := goerr.Prune(err, func(f goerr.Frame) bool {
+		return strings.HasPrefix(f.Message, "middleware:")
+	})
+
+	want := []string{"load order failed", "query failed", "connection reset"}
+	if got := goerr.ListErrors(pruned); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Want: %v, Got: %v", want, got)
+	}
+	if goerr.Code(pruned) != http.StatusBadGateway {
+		t.Errorf("Want: %d, Got: %d", http.StatusBadGateway, goerr.Code(pruned))
+	}
+	if !errors.Is(pruned, root) {
+		t.Errorf("expected the root cause to be kept")
+	}
+	if len(goerr.ListErrors(err)) != 5 {
+		t.Errorf("expected err to be unchanged, Got: %v", goerr.ListErrors(err))
+	}
+}
+
+func TestPruneOutermost(t *testing.T) {
+	err := goerr.New(goerr.New(nil, http.StatusNotFound, "no rows"), "generated")
+	err = goerr.WithSeverity(goerr.WithKind(goerr.New(err, "middleware"), goerr.KindConflict), goerr.SeverityWarning)
+
+	pruned := goerr.Prune(err, func(f goerr.Frame) bool {
+		return f.Message != "no rows"
+	})
+
+	if got := goerr.ListErrors(pruned); len(got) != 1 || got[0] != "no rows" {
+		t.Errorf("Want: [no rows], Got: %v", got)
+	}
+	if goerr.Code(pruned) != goerr.Code(err) || goerr.KindOf(pruned) != goerr.KindConflict {
+		t.Errorf("Want: %d conflict, Got: %d %s", goerr.Code(err), goerr.Code(pruned), goerr.KindOf(pruned))
+	}
+	if goerr.Severity(pruned) != goerr.SeverityWarning {
+		t.Errorf("Want: warning, Got: %s", goerr.Severity(pruned))
+	}
+}
+
+func TestPruneEverything(t *testing.T) {
+	err := goerr.New(goerr.New(errors.New("eof"), "inner"), http.StatusBadRequest, "outer")
+
+	pruned := goerr.Prune(err, func(goerr.Frame) bool { return true })
+
+	want := []string{"inner", "eof"}
+	if got := goerr.ListErrors(pruned); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Want: %v, Got: %v", want, got)
+	}
+	if goerr.Code(pruned) != http.StatusBadRequest {
+		t.Errorf("Want: %d, Got: %d", http.StatusBadRequest, goerr.Code(pruned))
+	}
+
+	if goerr.Prune(err, func(goerr.Frame) bool { return false }) != err {
+		t.Errorf("expected err to be returned unchanged")
+	}
+	if goerr.Prune(nil, func(goerr.Frame) bool { return true }) != nil {
+		t.Errorf("expected nil for nil")
+	}
+}
+
+func TestPruneKeepsPublicOutput(t *testing.T) {
+	err := goerr.New(errors.New("pq: password auth failed for user admin"), "connect failed")
+	err = goerr.Mask(err, http.StatusServiceUnavailable, "middleware: try again later")
+	err = goerr.Hide(goerr.New(err, "middleware: retried"))
+	err = goerr.WithField(goerr.New(err, "middleware: traced"), "trace_id", "abc")
+
+	pruned := goerr.Prune(err, func(f goerr.Frame) bool {
+		return strings.HasPrefix(f.Message, "middleware:")
+	})
+
+	want := []string{"middleware: retried", "middleware: try again later", "connect failed", "pq: password auth failed for user admin"}
+	if got := goerr.ListErrors(pruned); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Want: %v, Got: %v", want, got)
+	}
+	if got := goerr.PublicMessage(pruned); got != "middleware: try again later" {
+		t.Errorf("Want: middleware: try again later, Got: %s", got)
+	}
+	if b, _ := json.Marshal(pruned); strings.Contains(string(b), "connect failed") || strings.Contains(string(b), "password") {
+		t.Errorf("expected the masked layers to stay masked, Got: %s", b)
+	}
+	if got := goerr.Fields(pruned)["trace_id"]; got != "abc" {
+		t.Errorf("Want: abc, Got: %v", got)
+	}
+}
@@ -0,0 +1,124 @@
+package goerr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces sensitive values in rendered output.
+const Redacted = "[REDACTED]"
+
+// A Redactor masks sensitive data before errors are rendered.
+type Redactor interface {
+	// RedactMessage returns msg with sensitive values masked.
+	RedactMessage(msg string) string
+	// RedactField returns the value to render for the field key.
+	RedactField(key string, value any) any
+}
+
+var (
+	redactorsMu sync.RWMutex
+	redactors   []Redactor
+)
+
+// AddRedactor registers a redactor applied to the messages and fields of
+// errors whenever they are rendered by Stack, ListStacks, ListErrors and
+// Fields. The original values remain available through Error().
+func AddRedactor(r Redactor) {
+	redactorsMu.Lock()
+	defer redactorsMu.Unlock()
+	redactors = append(redactors, r)
+}
+
+// redactMessage applies the redactors of f, or the registered ones if f is
+// nil, to msg.
+func redactMessage(f *Factory, msg string) string {
+	if f != nil {
+		for _, r := range f.opts.Redactors {
+			msg = r.RedactMessage(msg)
+		}
+		return msg
+	}
+
+	redactorsMu.RLock()
+	defer redactorsMu.RUnlock()
+	for _, r := range redactors {
+		msg = r.RedactMessage(msg)
+	}
+	return msg
+}
+
+// redactField applies the redactors of f, or the registered ones if f is nil,
+// to the value of the field key.
+func redactField(f *Factory, key string, value any) any {
+	if f != nil {
+		for _, r := range f.opts.Redactors {
+			value = r.RedactField(key, value)
+		}
+		return value
+	}
+
+	redactorsMu.RLock()
+	defer redactorsMu.RUnlock()
+	for _, r := range redactors {
+		value = r.RedactField(key, value)
+	}
+	return value
+}
+
+type keyRedactor struct {
+	keys    map[string]bool
+	pattern *regexp.Regexp
+}
+
+// KeyRedactor returns a redactor that masks the values of fields with one of
+// the given keys, compared case insensitively. In messages it masks values
+// that follow one of the keys, as in "password=secret" or "pan: ABCDE1234F".
+func KeyRedactor(keys ...string) Redactor {
+	r := &keyRedactor{keys: map[string]bool{}}
+	quoted := make([]string, len(keys))
+	for i, k := range keys {
+		r.keys[strings.ToLower(k)] = true
+		quoted[i] = regexp.QuoteMeta(k)
+	}
+	r.pattern = regexp.MustCompile(`(?i)\b(` + strings.Join(quoted, "|") + `)(\s*[=:]\s*)("[^"]*"|[^\s,;]+)`)
+	return r
+}
+
+func (r *keyRedactor) RedactMessage(msg string) string {
+	return r.pattern.ReplaceAllString(msg, "${1}${2}"+Redacted)
+}
+
+func (r *keyRedactor) RedactField(key string, value any) any {
+	if r.keys[strings.ToLower(key)] {
+		return Redacted
+	}
+	return value
+}
+
+type regexRedactor struct {
+	pattern *regexp.Regexp
+}
+
+// RegexRedactor returns a redactor that masks every match of pattern in
+// messages and in the rendered values of fields.
+func RegexRedactor(pattern *regexp.Regexp) Redactor {
+	return &regexRedactor{pattern: pattern}
+}
+
+func (r *regexRedactor) RedactMessage(msg string) string {
+	return r.pattern.ReplaceAllString(msg, Redacted)
+}
+
+func (r *regexRedactor) RedactField(_ string, value any) any {
+	s, ok := value.(string)
+	if !ok {
+		s = fmt.Sprint(value)
+	}
+	if !r.pattern.MatchString(s) {
+		return value
+	}
+	return r.pattern.ReplaceAllString(s, Redacted)
+}
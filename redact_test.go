@@ -0,0 +1,45 @@
+package goerr_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestRedaction(t *testing.T) {
+	t.Cleanup(func() { goerr.Configure(goerr.Options{}) })
+	goerr.AddRedactor(goerr.KeyRedactor("password", "pan", "aadhaar"))
+	goerr.AddRedactor(goerr.RegexRedactor(regexp.MustCompile(`\b[A-Z]{5}[0-9]{4}[A-Z]\b`)))
+
+	login := func() error {
+		err := errors.New("login failed for password=hunter2")
+		return goerr.WithFields(goerr.New(err, 401, "kyc check failed for ABCDE1234F"), map[string]any{
+			"pan":  "ABCDE1234F",
+			"user": "bob",
+			"note": "pan on file is ABCDE1234F",
+		})
+	}
+	err := login()
+
+	stack := goerr.Stack(err)
+	for _, secret := range []string{"hunter2", "ABCDE1234F"} {
+		if strings.Contains(stack, secret) {
+			t.Errorf("stack contains %s: %s", secret, stack)
+		}
+	}
+	if !strings.Contains(stack, "login failed for password=[REDACTED]") {
+		t.Errorf("stack do not contain the redacted message: %s", stack)
+	}
+
+	fields := goerr.Fields(err)
+	if fields["pan"] != goerr.Redacted || fields["note"] != "pan on file is [REDACTED]" || fields["user"] != "bob" {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+
+	if got := err.Error(); got != "kyc check failed for ABCDE1234F" {
+		t.Errorf("Error() should not be redacted. Got: %s", got)
+	}
+}
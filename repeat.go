@@ -0,0 +1,70 @@
+package goerr
+
+import (
+	"sync"
+	"time"
+)
+
+var repeats = struct {
+	sync.Mutex
+	window time.Duration
+	seen   map[string]*repeat
+	purged time.Time
+}{}
+
+type repeat struct {
+	first time.Time
+	count int
+}
+
+// SuppressRepeats turns on the suppression of repeated stacks in the logging
+// integrations: within window after the full stack of an error was logged,
+// errors with the same fingerprint are logged with a counter instead of
+// their stack, which reduces the log volume a lot during outages. A window of
+// 0 turns suppression off, which is the default.
+func SuppressRepeats(window time.Duration) {
+	repeats.Lock()
+	defer repeats.Unlock()
+	repeats.window = window
+	repeats.seen = nil
+}
+
+// Repeated records that err is about to be logged and reports whether its
+// stack should be suppressed, because an error with the same fingerprint was
+// logged in full within the window set by SuppressRepeats. count is the
+// number of occurrences of the fingerprint in the current window, including
+// this one. It is meant for logging integrations; without suppression it
+// always returns 1 and false.
+func Repeated(err error) (count int, suppress bool) {
+	if err == nil {
+		return 0, false
+	}
+
+	repeats.Lock()
+	defer repeats.Unlock()
+	if repeats.window <= 0 {
+		return 1, false
+	}
+
+	t := now()
+	if repeats.seen == nil {
+		repeats.seen = map[string]*repeat{}
+	}
+	if t.Sub(repeats.purged) >= repeats.window {
+		for fp, r := range repeats.seen {
+			if t.Sub(r.first) >= repeats.window {
+				delete(repeats.seen, fp)
+			}
+		}
+		repeats.purged = t
+	}
+
+	fp := Fingerprint(err)
+	r, ok := repeats.seen[fp]
+	if !ok || t.Sub(r.first) >= repeats.window {
+		repeats.seen[fp] = &repeat{first: t, count: 1}
+		return 1, false
+	}
+	r.count++
+	return r.count, true
+}
@@ -0,0 +1,33 @@
+package goerr_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+func TestRepeated(t *testing.T) {
+	if count, suppress := goerr.Repeated(samplesrc.Service()); count != 1 || suppress {
+		t.Errorf("expected no suppression by default, Got: %d %v", count, suppress)
+	}
+
+	goerr.SuppressRepeats(50 * time.Millisecond)
+	defer goerr.SuppressRepeats(0)
+
+	for i := 1; i <= 3; i++ {
+		count, suppress := goerr.Repeated(samplesrc.Service())
+		if count != i || suppress != (i > 1) {
+			t.Errorf("occurrence %d. Want: %d %v, Got: %d %v", i, i, i > 1, count, suppress)
+		}
+	}
+	if count, suppress := goerr.Repeated(samplesrc.Repository()); count != 1 || suppress {
+		t.Errorf("expected other errors to be logged in full, Got: %d %v", count, suppress)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if count, suppress := goerr.Repeated(samplesrc.Service()); count != 1 || suppress {
+		t.Errorf("expected a full log after the window, Got: %d %v", count, suppress)
+	}
+}
@@ -0,0 +1,236 @@
+package goerr
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Defaults of ReporterOptions.
+const (
+	DefaultReportQueueSize     = 1024
+	DefaultReportBatchSize     = 100
+	DefaultReportFlushInterval = time.Second
+)
+
+// A Sink receives the errors reported to a Reporter, in batches, e.g. to
+// send them to Sentry, a Slack webhook or a Kafka topic. Send is called from
+// the goroutine of the reporter, one batch at a time, and must not keep errs
+// after it returns.
+type Sink interface {
+	Send(errs []error) error
+}
+
+// SinkFunc adapts a function to a Sink.
+type SinkFunc func(errs []error) error
+
+// Send calls f.
+func (f SinkFunc) Send(errs []error) error {
+	return f(errs)
+}
+
+// ReporterOptions configure a Reporter.
+type ReporterOptions struct {
+	// QueueSize is the number of errors waiting to be sent, by default
+	// DefaultReportQueueSize. Errors reported while the queue is full are
+	// dropped.
+	QueueSize int
+	// BatchSize is the largest number of errors sent to the sinks at once,
+	// by default DefaultReportBatchSize.
+	BatchSize int
+	// FlushInterval is the longest time an error waits for its batch to
+	// fill, by default DefaultReportFlushInterval.
+	FlushInterval time.Duration
+	// RateLimit is the largest number of errors sent per second. Errors
+	// beyond it are dropped. 0 means no limit.
+	RateLimit int
+	// OnSinkError is called with the error of a sink failing to send a
+	// batch, if set.
+	OnSinkError func(err error)
+}
+
+// ReportStatistics describe the errors reported to a Reporter.
+type ReportStatistics struct {
+	// Reported is the number of errors queued.
+	Reported int64
+	// Sent is the number of errors passed to the sinks.
+	Sent int64
+	// Dropped is the number of errors dropped because the queue was full.
+	Dropped int64
+	// Limited is the number of errors dropped by the rate limit.
+	Limited int64
+	// Failed is the number of batches a sink failed to send.
+	Failed int64
+}
+
+// A Reporter sends errors to sinks in the background, so that services can
+// surface critical errors beyond their logs without blocking request paths.
+type Reporter struct {
+	opts  ReporterOptions
+	sinks []Sink
+	queue chan error
+	done  chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+
+	reported, sent, dropped, limited, failed atomic.Int64
+
+	// the rate limit window, only used by the goroutine of the reporter
+	window time.Time
+	inWin  int
+}
+
+// NewReporter returns a reporter sending to sinks, and starts its goroutine.
+// Close stops it.
+func NewReporter(opts ReporterOptions, sinks ...Sink) *Reporter {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultReportQueueSize
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultReportBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = DefaultReportFlushInterval
+	}
+
+	r := &Reporter{
+		opts:  opts,
+		sinks: sinks,
+		queue: make(chan error, opts.QueueSize),
+		done:  make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Report queues err to be sent to the sinks without blocking. It returns
+// false if err is nil, the queue is full or r is closed.
+func (r *Reporter) Report(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return false
+	}
+	select {
+	case r.queue <- err:
+		r.reported.Add(1)
+		return true
+	default:
+		r.dropped.Add(1)
+		return false
+	}
+}
+
+// Close sends the queued errors and stops r. Errors reported afterwards are
+// ignored.
+func (r *Reporter) Close() {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.queue)
+	}
+	r.mu.Unlock()
+	<-r.done
+}
+
+// Stats returns the statistics of r, e.g. to export the dropped errors as a
+// metric.
+func (r *Reporter) Stats() ReportStatistics {
+	return ReportStatistics{
+		Reported: r.reported.Load(),
+		Sent:     r.sent.Load(),
+		Dropped:  r.dropped.Load(),
+		Limited:  r.limited.Load(),
+		Failed:   r.failed.Load(),
+	}
+}
+
+// run batches the queued errors until the queue is closed.
+func (r *Reporter) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.opts.FlushInterval)
+	defer ticker.Stop()
+
+	var batch []error
+	for {
+		select {
+		case err, ok := <-r.queue:
+			if !ok {
+				r.flush(batch)
+				return
+			}
+			if !r.allow() {
+				r.limited.Add(1)
+				continue
+			}
+			batch = append(batch, err)
+			if len(batch) >= r.opts.BatchSize {
+				r.flush(batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			r.flush(batch)
+			batch = nil
+		}
+	}
+}
+
+// allow reports whether the rate limit lets one more error through.
+func (r *Reporter) allow() bool {
+	if r.opts.RateLimit <= 0 {
+		return true
+	}
+	if t := time.Now(); t.Sub(r.window) >= time.Second {
+		r.window, r.inWin = t, 0
+	}
+	if r.inWin >= r.opts.RateLimit {
+		return false
+	}
+	r.inWin++
+	return true
+}
+
+// flush sends batch to every sink.
+func (r *Reporter) flush(batch []error) {
+	if len(batch) == 0 {
+		return
+	}
+	for _, s := range r.sinks {
+		if err := s.Send(batch); err != nil {
+			r.failed.Add(1)
+			if r.opts.OnSinkError != nil {
+				r.opts.OnSinkError(err)
+			}
+		}
+	}
+	r.sent.Add(int64(len(batch)))
+}
+
+var reporter atomic.Pointer[Reporter]
+
+// SetReporter sets the reporter used by Report. Passing nil removes it.
+func SetReporter(r *Reporter) {
+	reporter.Store(r)
+}
+
+// Report queues err on the reporter set with SetReporter, see
+// Reporter.Report. It returns false if no reporter is set.
+//
+//	goerr.SetReporter(goerr.NewReporter(goerr.ReporterOptions{RateLimit: 10}, slack))
+//	...
+//	if goerr.Severity(err) == goerr.SeverityCritical {
+//		goerr.Report(err)
+//	}
+func Report(err error) bool {
+	r := reporter.Load()
+	if r == nil {
+		return false
+	}
+	return r.Report(err)
+}
@@ -0,0 +1,117 @@
+package goerr_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+// collector is a sink recording the batches it receives.
+type collector struct {
+	mu      sync.Mutex
+	batches [][]error
+}
+
+func (c *collector) Send(errs []error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.batches = append(c.batches, append([]error(nil), errs...))
+	return nil
+}
+
+func TestReporter(t *testing.T) {
+	var c collector
+	var sinkErrs []error
+	failing := goerr.SinkFunc(func([]error) error { return errors.New("webhook down") })
+	r := goerr.NewReporter(goerr.ReporterOptions{
+		BatchSize:   2,
+		OnSinkError: func(err error) { sinkErrs = append(sinkErrs, err) },
+	}, &c, failing)
+
+	for i := 0; i < 5; i++ {
+		if !r.Report(goerr.New(nil, "failed")) {
+			t.Errorf("expected the error to be queued")
+		}
+	}
+	if r.Report(nil) {
+		t.Errorf("expected a nil error to be ignored")
+	}
+	r.Close()
+	if r.Report(goerr.New(nil, "failed")) {
+		t.Errorf("expected errors reported after Close to be ignored")
+	}
+
+	var sizes []int
+	for _, b := range c.batches {
+		sizes = append(sizes, len(b))
+	}
+	if len(sizes) != 3 || sizes[0] != 2 || sizes[1] != 2 || sizes[2] != 1 {
+		t.Errorf("batch sizes. Want: [2 2 1], Got: %v", sizes)
+	}
+	if want, got := (goerr.ReportStatistics{Reported: 5, Sent: 5, Failed: 3}), r.Stats(); got != want {
+		t.Errorf("Want: %+v, Got: %+v", want, got)
+	}
+	if len(sinkErrs) != 3 {
+		t.Errorf("sink errors. Want: 3, Got: %d", len(sinkErrs))
+	}
+}
+
+func TestReporterDrops(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	blocking := goerr.SinkFunc(func([]error) error {
+		once.Do(func() { close(started) })
+		<-release
+		return nil
+	})
+	r := goerr.NewReporter(goerr.ReporterOptions{QueueSize: 1, BatchSize: 1}, blocking)
+
+	r.Report(goerr.New(nil, "first"))
+	<-started
+	if !r.Report(goerr.New(nil, "second")) {
+		t.Errorf("expected the error to be queued")
+	}
+	if r.Report(goerr.New(nil, "third")) {
+		t.Errorf("expected the error to be dropped")
+	}
+	close(release)
+	r.Close()
+
+	if want, got := (goerr.ReportStatistics{Reported: 2, Sent: 2, Dropped: 1}), r.Stats(); got != want {
+		t.Errorf("Want: %+v, Got: %+v", want, got)
+	}
+}
+
+func TestReporterRateLimit(t *testing.T) {
+	var c collector
+	r := goerr.NewReporter(goerr.ReporterOptions{RateLimit: 2}, &c)
+	for i := 0; i < 5; i++ {
+		r.Report(goerr.New(nil, "failed"))
+	}
+	r.Close()
+
+	if want, got := (goerr.ReportStatistics{Reported: 5, Sent: 2, Limited: 3}), r.Stats(); got != want {
+		t.Errorf("Want: %+v, Got: %+v", want, got)
+	}
+}
+
+func TestReport(t *testing.T) {
+	if goerr.Report(goerr.New(nil, "failed")) {
+		t.Errorf("expected false without a reporter")
+	}
+
+	var c collector
+	r := goerr.NewReporter(goerr.ReporterOptions{}, &c)
+	goerr.SetReporter(r)
+	defer goerr.SetReporter(nil)
+
+	err := goerr.New(nil, "failed")
+	goerr.Report(err)
+	r.Close()
+
+	if len(c.batches) != 1 || c.batches[0][0] != err {
+		t.Errorf("expected the error to be sent, Got: %v", c.batches)
+	}
+}
@@ -0,0 +1,175 @@
+package goerr
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// Fields set by FromHTTPResponse.
+const (
+	HTTPMethodField = "http_method"
+	HTTPURLField    = "http_url"
+	HTTPStatusField = "http_status"
+	HTTPBodyField   = "http_body"
+	// HTTPHeaderField is the prefix of the fields of the headers included with
+	// IncludeHeaders, followed by the lower case header name.
+	HTTPHeaderField = "http_header."
+)
+
+// DefaultBodyLimit is the number of bytes of a response body kept by
+// FromHTTPResponse unless set with BodyLimit.
+const DefaultBodyLimit = 512
+
+// A ResponseOption configures FromHTTPResponse.
+type ResponseOption func(*responseOptions)
+
+type responseOptions struct {
+	bodyLimit int
+	headers   []string
+	redacted  map[string]bool
+	decoder   BodyDecoder
+}
+
+// BodyLimit sets the number of bytes of the response body kept in the
+// error. A limit of 0 leaves the body out.
+func BodyLimit(n int) ResponseOption {
+	return func(o *responseOptions) {
+		o.bodyLimit = n
+	}
+}
+
+// IncludeHeaders adds the given response headers to the error as fields.
+func IncludeHeaders(names ...string) ResponseOption {
+	return func(o *responseOptions) {
+		o.headers = append(o.headers, names...)
+	}
+}
+
+// RedactHeaders replaces the values of the given headers by Redacted, in
+// addition to Authorization, Cookie and Set-Cookie which are always redacted.
+func RedactHeaders(names ...string) ResponseOption {
+	return func(o *responseOptions) {
+		for _, name := range names {
+			o.redacted[textproto.CanonicalMIMEHeaderKey(name)] = true
+		}
+	}
+}
+
+// FromHTTPResponse returns an error describing the failed response of an
+// outbound call, with the status code of resp as its code and the method,
+// URL, status and the beginning of the body as fields. The URL is kept
+// without its query and user info, as they may hold credentials. The body
+// of resp can still be read afterwards. FromHTTPResponse returns nil if resp
+// is nil or its status is below 400.
+//
+//	resp, err := client.Do(req)
+//	if err != nil {
+//		return goerr.New(err, "call payments failed")
+//	}
+//	defer resp.Body.Close()
+//	if err := goerr.FromHTTPResponse(resp); err != nil {
+//		return err
+//	}
+func FromHTTPResponse(resp *http.Response, opts ...ResponseOption) error {
+	if resp == nil || resp.StatusCode < 400 {
+		return nil
+	}
+
+	return fromHTTPResponse(nil, resp, nil, opts)
+}
+
+// fromHTTPResponse implements FromHTTPResponse and FromAPIResponse, decoding
+// the body with decoder unless an option sets another one, and giving the
+// layer the settings of f, or the global ones if f is nil.
+func fromHTTPResponse(f *Factory, resp *http.Response, decoder BodyDecoder, opts []ResponseOption) error {
+	o := responseOptions{
+		bodyLimit: DefaultBodyLimit,
+		redacted:  map[string]bool{"Authorization": true, "Cookie": true, "Set-Cookie": true},
+		decoder:   decoder,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	method, url := "", ""
+	if resp.Request != nil {
+		method = resp.Request.Method
+		if resp.Request.URL != nil {
+			u := *resp.Request.URL
+			u.User, u.RawQuery, u.ForceQuery = nil, "", false
+			url = u.String()
+		}
+	}
+
+	fields := map[string]any{
+		HTTPStatusField: resp.StatusCode,
+	}
+	if method != "" {
+		fields[HTTPMethodField] = method
+	}
+	if url != "" {
+		fields[HTTPURLField] = url
+	}
+	if body := peekBody(resp, o.bodyLimit); body != "" {
+		fields[HTTPBodyField] = body
+	}
+	for _, name := range o.headers {
+		name = textproto.CanonicalMIMEHeaderKey(name)
+		value := resp.Header.Get(name)
+		if value == "" {
+			continue
+		}
+		if o.redacted[name] {
+			value = Redacted
+		}
+		fields[HTTPHeaderField+strings.ToLower(name)] = value
+	}
+
+	var upstream APIError
+	decoded := false
+	if o.decoder != nil {
+		if body := peekBody(resp, MaxDecodedBody); body != "" {
+			upstream, decoded = o.decoder([]byte(body))
+		}
+	}
+	if decoded {
+		if upstream.Code != "" {
+			fields[UpstreamCodeField] = upstream.Code
+		}
+		if upstream.Message != "" {
+			fields[UpstreamMessageField] = upstream.Message
+		}
+		for k, v := range upstream.Fields {
+			fields[k] = v
+		}
+	}
+
+	msg := strings.TrimSpace(method + " " + url + ": " + resp.Status)
+	if upstream.Message != "" {
+		msg += ": " + upstream.Message
+	}
+	e := newError(nil, []any{resp.StatusCode, "%s", strings.TrimPrefix(msg, ": ")}, 2)
+	e.fields = fields
+	e.codeStr = upstream.Code
+	return f.finish(e)
+}
+
+// peekBody returns up to limit bytes of the body of resp, putting them back
+// so the body can still be read in full.
+func peekBody(resp *http.Response, limit int) string {
+	if resp.Body == nil || resp.Body == http.NoBody || limit <= 0 {
+		return ""
+	}
+
+	buf := make([]byte, limit)
+	n, _ := io.ReadFull(resp.Body, buf)
+	buf = buf[:n]
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(buf), resp.Body), resp.Body}
+	return strings.TrimSpace(string(buf))
+}
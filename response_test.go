@@ -0,0 +1,88 @@
+package goerr_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestFromHTTPResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "abc")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		io.WriteString(w, `{"error":"maintenance"}`+strings.Repeat(" ", 100))
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/orders?token=secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	err = goerr.FromHTTPResponse(resp, goerr.BodyLimit(30), goerr.IncludeHeaders("x-request-id", "Set-Cookie"))
+
+	want := "GET " + srv.URL + "/orders: 503 Service Unavailable"
+	if got := err.Error(); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+	if got := goerr.Code(err); got != http.StatusServiceUnavailable {
+		t.Errorf("Want: %d, Got: %d", http.StatusServiceUnavailable, got)
+	}
+
+	fields := goerr.Fields(err)
+	wants := map[string]any{
+		goerr.HTTPMethodField:                  "GET",
+		goerr.HTTPURLField:                     srv.URL + "/orders",
+		goerr.HTTPStatusField:                  http.StatusServiceUnavailable,
+		goerr.HTTPBodyField:                    `{"error":"maintenance"}`,
+		goerr.HTTPHeaderField + "x-request-id": "abc",
+		goerr.HTTPHeaderField + "set-cookie":   goerr.Redacted,
+	}
+	for k, v := range wants {
+		if fields[k] != v {
+			t.Errorf("%s. Want: %v, Got: %v", k, v, fields[k])
+		}
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.HasPrefix(string(body), `{"error":"maintenance"}`) || len(body) != 123 {
+		t.Errorf("expected the whole body to be readable, Got: %q", body)
+	}
+
+	if !strings.Contains(goerr.Stack(err), "response_test.go") {
+		t.Errorf("expected the layer to point to the caller, Got: %s", goerr.Stack(err))
+	}
+}
+
+func TestFromHTTPResponseSuccess(t *testing.T) {
+	if goerr.FromHTTPResponse(nil) != nil {
+		t.Errorf("expected nil for a nil response")
+	}
+	if goerr.FromHTTPResponse(&http.Response{StatusCode: http.StatusNotModified}) != nil {
+		t.Errorf("expected nil for a successful response")
+	}
+}
+
+func TestFromHTTPResponseRedactHeaders(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusUnauthorized,
+		Status:     "401 Unauthorized",
+		Header:     http.Header{"Www-Authenticate": {"Bearer realm=internal"}},
+		Body:       http.NoBody,
+	}
+
+	err := goerr.FromHTTPResponse(resp, goerr.IncludeHeaders("WWW-Authenticate"), goerr.RedactHeaders("www-authenticate"))
+
+	if got := err.Error(); got != "401 Unauthorized" {
+		t.Errorf("Want: 401 Unauthorized, Got: %s", got)
+	}
+	if got := goerr.Fields(err)[goerr.HTTPHeaderField+"www-authenticate"]; got != goerr.Redacted {
+		t.Errorf("Want: %s, Got: %v", goerr.Redacted, got)
+	}
+}
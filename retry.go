@@ -0,0 +1,39 @@
+package goerr
+
+import "time"
+
+// WithRetryAfter returns err with a hint, set on its outermost layer, that
+// the failed operation may be retried after d, e.g. when a rate limit or an
+// upstream outage ends. The HTTP middleware of goerrhttp sends it as the
+// Retry-After header of 429 and 503 responses. If err is not a goerr, it is
+// wrapped in a new goerr first.
+//
+//	return goerr.WithRetryAfter(goerr.New(nil, http.StatusTooManyRequests, "rate limited"), 30*time.Second)
+func WithRetryAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+
+	e := layer(err)
+	e.retry = d
+	return e
+}
+
+// RetryAfter returns the delay last given in the call chain of err with
+// WithRetryAfter, and whether there is one.
+//
+//	for {
+//		err := call()
+//		d, ok := goerr.RetryAfter(err)
+//		if !ok {
+//			return err
+//		}
+//		time.Sleep(d)
+//	}
+func RetryAfter(err error) (time.Duration, bool) {
+	e := find(err, func(e *Error) bool { return e.retry != 0 })
+	if e == nil {
+		return 0, false
+	}
+	return e.retry, true
+}
@@ -0,0 +1,33 @@
+package goerr_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want time.Duration
+		ok   bool
+	}{
+		{"nil", nil, 0, false},
+		{"none", goerr.New(errors.New("plain"), 503, "unavailable"), 0, false},
+		{"set", goerr.WithRetryAfter(errors.New("plain"), time.Second), time.Second, true},
+		{"nested", goerr.New(goerr.WithRetryAfter(goerr.New(nil, 429, "rate limited"), time.Minute), "sync failed"), time.Minute, true},
+	}
+
+	for _, tt := range tests {
+		got, ok := goerr.RetryAfter(tt.err)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s. Want: %v %t, Got: %v %t", tt.name, tt.want, tt.ok, got, ok)
+		}
+	}
+	if goerr.WithRetryAfter(nil, time.Second) != nil {
+		t.Errorf("expected nil for a nil error")
+	}
+}
@@ -26,3 +26,9 @@ func Repository() error {
 	err := errors.New("error from database")
 	return goerr.New(nil, err.Error())
 }
+
+type Store struct{}
+
+func (s *Store) Get() error {
+	return goerr.New(nil, "record not found")
+}
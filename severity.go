@@ -0,0 +1,54 @@
+package goerr
+
+// SeverityLevel tells how serious an error is, so that logging middleware can
+// choose the log level without inspecting messages.
+type SeverityLevel int
+
+const (
+	// SeverityWarning marks expected failures such as validation errors.
+	SeverityWarning SeverityLevel = iota + 1
+	// SeverityError is the severity of an error that has none set explicitly.
+	SeverityError
+	// SeverityCritical marks failures that need immediate attention.
+	SeverityCritical
+)
+
+// String returns the lower case name of the severity level.
+func (s SeverityLevel) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	}
+	return "unknown"
+}
+
+// WithSeverity returns err with the given severity set on its outermost layer.
+// If err is not a goerr, it is wrapped in a new goerr first.
+func WithSeverity(err error, level SeverityLevel) error {
+	if err == nil {
+		return nil
+	}
+
+	e := layer(err)
+	e.severity = level
+	return e
+}
+
+// Severity returns the severity of err. Like Code, it stops at the first goerr
+// in the chain that has a severity set. Errors without any severity are
+// reported as SeverityError.
+func Severity(err error) SeverityLevel {
+	if err == nil {
+		return 0
+	}
+
+	e := find(err, func(e *Error) bool { return e.severity != 0 })
+	if e == nil {
+		return SeverityError
+	}
+	return e.severity
+}
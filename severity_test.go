@@ -0,0 +1,63 @@
+package goerr_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestSeverity(t *testing.T) {
+	validate := func() error {
+		return goerr.WithSeverity(goerr.New(nil, http.StatusBadRequest, "invalid order"), goerr.SeverityWarning)
+	}
+	controller := func() error {
+		return goerr.New(validate(), "controller error")
+	}
+
+	err := controller()
+
+	if got := goerr.Severity(err); got != goerr.SeverityWarning {
+		t.Errorf("Want: %s, Got: %s", goerr.SeverityWarning, got)
+	}
+
+	stack := goerr.Stack(err)
+	if !strings.Contains(stack, "invalid order (400) {warning}") {
+		t.Errorf("stack do not contain the severity. %s", stack)
+	}
+}
+
+func TestSeverityDefault(t *testing.T) {
+	if got := goerr.Severity(errors.New("plain")); got != goerr.SeverityError {
+		t.Errorf("Want: %s, Got: %s", goerr.SeverityError, got)
+	}
+	if got := goerr.Severity(goerr.New(nil, "no severity")); got != goerr.SeverityError {
+		t.Errorf("Want: %s, Got: %s", goerr.SeverityError, got)
+	}
+	if got := goerr.Severity(nil); got != 0 {
+		t.Errorf("Want: 0, Got: %d", got)
+	}
+}
+
+func TestWithSeverityDoesNotMutate(t *testing.T) {
+	base := goerr.New(nil, "base")
+	_ = goerr.WithSeverity(base, goerr.SeverityCritical)
+
+	if got := goerr.Severity(base); got != goerr.SeverityError {
+		t.Errorf("original error was mutated. Got: %s", got)
+	}
+}
+
+func TestWithSeverityNonGoErr(t *testing.T) {
+	plain := errors.New("plain")
+	err := goerr.WithSeverity(plain, goerr.SeverityCritical)
+
+	if got := goerr.Severity(err); got != goerr.SeverityCritical {
+		t.Errorf("Want: %s, Got: %s", goerr.SeverityCritical, got)
+	}
+	if !errors.Is(err, plain) {
+		t.Errorf("expected the original error to be wrapped")
+	}
+}
@@ -0,0 +1,95 @@
+package goerr
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// SourceContext is the number of source lines shown before and after the
+// line of each frame by StackVerbose.
+var SourceContext = 2
+
+var debugMode atomic.Bool
+
+// SetDebug turns debug mode on or off. In debug mode Stack renders the same
+// output as StackVerbose. It is meant for local development and staging.
+func SetDebug(on bool) {
+	debugMode.Store(on)
+}
+
+// StackVerbose is like Stack, but also includes the source code around the
+// line of every layer when the source files are available.
+func StackVerbose(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	writeStack(&b, err, true, nil)
+	return b.String()
+}
+
+// writeSource writes the source lines around the frame of e, indented by
+// depth tabs. Nothing is written if the source file can not be read.
+func writeSource(b *strings.Builder, e *Error, depth int) {
+	frame := e.frames[0]
+	first, lines, err := frame.sourceLines(SourceContext)
+	if err != nil {
+		return
+	}
+
+	for i, line := range lines {
+		writeIndent(b, depth)
+
+		marker := byte(' ')
+		if first+i == frame.LineNumber {
+			marker = '>'
+		}
+		b.WriteByte(marker)
+
+		num := strconv.Itoa(first + i)
+		for j := len(num); j < 5; j++ {
+			b.WriteByte(' ')
+		}
+		b.WriteString(num)
+		b.WriteString(" | ")
+		b.WriteString(line)
+	}
+}
+
+// sourceLines returns up to context lines before and after the line of the
+// frame, along with the line number of the first returned line.
+func (frame *StackFrame) sourceLines(context int) (int, []string, error) {
+	if frame.LineNumber <= 0 {
+		return 0, nil, fmt.Errorf("no line number for %s", frame.Name)
+	}
+
+	file, err := os.Open(frame.File)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer file.Close()
+
+	first := frame.LineNumber - context
+	if first < 1 {
+		first = 1
+	}
+	last := frame.LineNumber + context
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for current := 1; current <= last && scanner.Scan(); current++ {
+		if current >= first {
+			lines = append(lines, scanner.Text())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, nil, err
+	}
+
+	return first, lines, nil
+}
@@ -0,0 +1,53 @@
+package goerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+func TestStackVerbose(t *testing.T) {
+	err := samplesrc.Controller()
+
+	got := goerr.StackVerbose(err)
+	t.Log(got)
+
+	wants := []string{
+		"\n\t>   12 | \t\treturn goerr.New(err, \"controller failed\")",
+		"\n\t    10 | \terr := Service()",
+		"\n\t\t>   20 | \t\treturn goerr.New(err, \"service failed\")",
+		"\n\t\t\t>   27 | \treturn goerr.New(nil, err.Error())",
+	}
+	for _, want := range wants {
+		if !strings.Contains(got, want) {
+			t.Errorf("verbose stack do not contain %q", want)
+		}
+	}
+}
+
+func TestStackDebugMode(t *testing.T) {
+	err := samplesrc.Repository()
+
+	if got := goerr.Stack(err); strings.Contains(got, "|") {
+		t.Errorf("stack contains source outside debug mode: %s", got)
+	}
+
+	goerr.SetDebug(true)
+	defer goerr.SetDebug(false)
+
+	if got := goerr.Stack(err); !strings.Contains(got, ">   27 | ") {
+		t.Errorf("stack do not contain source in debug mode: %s", got)
+	}
+}
+
+func TestStackVerboseNonGoErr(t *testing.T) {
+	want := "some sample error"
+	got := goerr.StackVerbose(errors.New(want))
+
+	if want != got {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+}
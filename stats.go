@@ -0,0 +1,118 @@
+package goerr
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// MaxStatsSites is the number of wrap sites reported by Stats.
+var MaxStatsSites = 10
+
+// Statistics describe the layers created since statistics were turned on,
+// see SetStatsCollection.
+type Statistics struct {
+	// Created is the number of layers created.
+	Created int64
+	// Frames is the number of stack frames captured for them.
+	Frames int64
+	// AverageDepth is the average length of the chains of the layers when
+	// they were created, counting the layers themselves.
+	AverageDepth float64
+	// Sites are the places creating the most layers, most frequent first.
+	Sites []WrapSite
+}
+
+// A WrapSite is a place in the code creating layers.
+type WrapSite struct {
+	File     string
+	Line     int
+	Function string
+	// Count is the number of layers created at the site.
+	Count int64
+}
+
+// siteKey identifies a wrap site.
+type siteKey struct {
+	file string
+	line int
+}
+
+var (
+	statsOn atomic.Bool
+	stats   struct {
+		sync.Mutex
+		created, frames, depths int64
+		sites                   map[siteKey]*WrapSite
+	}
+)
+
+// SetStatsCollection turns the collection of the statistics returned by
+// Stats on or off. Turning it on resets the statistics. Collecting them
+// walks the chain of every new layer, so it is off by default and meant to
+// be turned on for a while to find code that wraps too much.
+func SetStatsCollection(on bool) {
+	if on {
+		stats.Lock()
+		stats.created, stats.frames, stats.depths = 0, 0, 0
+		stats.sites = map[siteKey]*WrapSite{}
+		stats.Unlock()
+	}
+	statsOn.Store(on)
+}
+
+// Stats returns the statistics collected since they were turned on with
+// SetStatsCollection, with the MaxStatsSites most frequent wrap sites.
+func Stats() Statistics {
+	stats.Lock()
+	defer stats.Unlock()
+
+	s := Statistics{Created: stats.created, Frames: stats.frames}
+	if stats.created > 0 {
+		s.AverageDepth = float64(stats.depths) / float64(stats.created)
+	}
+	for _, site := range stats.sites {
+		s.Sites = append(s.Sites, *site)
+	}
+	sort.Slice(s.Sites, func(i, j int) bool {
+		a, b := s.Sites[i], s.Sites[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	if len(s.Sites) > MaxStatsSites {
+		s.Sites = s.Sites[:MaxStatsSites]
+	}
+	return s
+}
+
+// count adds the new layer e to the statistics, if they are collected.
+func count(e *Error) {
+	if !statsOn.Load() {
+		return
+	}
+
+	depth := 0
+	walk(e, func(int, error) bool {
+		depth++
+		return true
+	})
+
+	stats.Lock()
+	defer stats.Unlock()
+	stats.created++
+	stats.frames += int64(len(e.stack))
+	stats.depths += int64(depth)
+
+	key := siteKey{file: e.frames[0].File, line: e.frames[0].LineNumber}
+	site := stats.sites[key]
+	if site == nil {
+		site = &WrapSite{File: key.file, Line: key.line, Function: e.Func()}
+		stats.sites[key] = site
+	}
+	site.Count++
+}
@@ -0,0 +1,56 @@
+package goerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func wrapTwice(err error) error {
+	err = goerr.New(err, "inner")
+	return goerr.New(err, "outer")
+}
+
+func TestStats(t *testing.T) {
+	_ = goerr.New(nil, "not counted")
+
+	goerr.SetStatsCollection(true)
+	defer goerr.SetStatsCollection(false)
+
+	for i := 0; i < 3; i++ {
+		_ = wrapTwice(errors.New("db down"))
+	}
+	_ = goerr.New(nil, "single")
+
+	s := goerr.Stats()
+	if s.Created != 7 {
+		t.Errorf("created. Want: 7, Got: %d", s.Created)
+	}
+	if s.Frames < s.Created {
+		t.Errorf("expected at least one frame per layer, Got: %d", s.Frames)
+	}
+	// inner layers have a depth of 2 with the wrapped error, outer ones 3
+	if want := (3*2 + 3*3 + 1) / 7.0; s.AverageDepth != want {
+		t.Errorf("average depth. Want: %f, Got: %f", want, s.AverageDepth)
+	}
+
+	if len(s.Sites) != 3 {
+		t.Fatalf("nr. of sites. Want: 3, Got: %d", len(s.Sites))
+	}
+	for _, site := range s.Sites[:2] {
+		if site.Count != 3 || !strings.HasSuffix(site.File, "stats_test.go") || !strings.HasSuffix(site.Function, "wrapTwice") {
+			t.Errorf("Want: 3 layers created by wrapTwice, Got: %+v", site)
+		}
+	}
+	if s.Sites[2].Count != 1 || !strings.HasSuffix(s.Sites[2].Function, "TestStats") {
+		t.Errorf("Want: 1 layer created by TestStats, Got: %+v", s.Sites[2])
+	}
+
+	goerr.SetStatsCollection(false)
+	_ = goerr.New(nil, "not counted")
+	if got := goerr.Stats().Created; got != 7 {
+		t.Errorf("expected no collection when off. Want: 7, Got: %d", got)
+	}
+}
@@ -0,0 +1,67 @@
+package goerr
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// streamChunk is the number of bytes WriteStack buffers before writing them.
+const streamChunk = 4 << 10
+
+// WriteStack writes the stack of err, as returned by Stack, to w. The stack
+// is streamed in chunks of a few kilobytes instead of being built in memory
+// first, which matters for very large stacks, e.g. with MaxStackBytes set to
+// 0. It returns the first error returned by w.
+func WriteStack(w io.Writer, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var werr error
+	drain := func(s string) {
+		if werr == nil {
+			_, werr = io.WriteString(w, s)
+		}
+	}
+
+	var b strings.Builder
+	writeStack(&b, err, debugMode.Load(), drain)
+	drain(b.String())
+	return werr
+}
+
+// WriteJSON writes err to w as rendered by its MarshalJSON method, encoding
+// one layer at a time instead of the whole document in memory. Unlike
+// json.Encoder it writes no trailing newline. It returns the first error
+// returned by w or by the encoding of a field or details value.
+func WriteJSON(w io.Writer, err error) error {
+	head, merr := json.Marshal(newJSONHead(err))
+	if merr != nil {
+		return merr
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.Write(head[:len(head)-1])
+	bw.WriteString(`,"layers":[`)
+
+	first := true
+	walkPublic(err, func(err error) bool {
+		var b []byte
+		if b, merr = json.Marshal(newJSONLayer(err)); merr != nil {
+			return false
+		}
+		if !first {
+			bw.WriteByte(',')
+		}
+		first = false
+		bw.Write(b)
+		return true
+	})
+	if merr != nil {
+		return merr
+	}
+	bw.WriteString("]}")
+	return bw.Flush()
+}
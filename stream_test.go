@@ -0,0 +1,97 @@
+package goerr_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+// countingWriter counts the writes to it and fails after limit writes.
+type countingWriter struct {
+	buf    bytes.Buffer
+	writes int
+	limit  int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.limit > 0 && w.writes > w.limit {
+		return 0, errors.New("pipe closed")
+	}
+	return w.buf.Write(p)
+}
+
+func (w *countingWriter) String() string {
+	return w.buf.String()
+}
+
+func TestWriteStack(t *testing.T) {
+	err := goerr.New(samplesrc.Service(), 409, "controller failed")
+
+	var w countingWriter
+	if werr := goerr.WriteStack(&w, err); werr != nil {
+		t.Fatal(werr)
+	}
+	if w.String() != goerr.Stack(err) {
+		t.Errorf("Want: %s, Got: %s", goerr.Stack(err), w.String())
+	}
+	if goerr.WriteStack(&w, nil) != nil {
+		t.Errorf("Want: nil")
+	}
+}
+
+func TestWriteStackLarge(t *testing.T) {
+	defer func(max int) { goerr.MaxStackBytes = max }(goerr.MaxStackBytes)
+	goerr.MaxStackBytes = 0
+
+	err := errors.New("db down")
+	for i := 0; i < 90; i++ {
+		err = goerr.New(err, fmt.Sprintf("attempt %d failed with a rather long message to make the stack large", i))
+	}
+
+	var w countingWriter
+	if werr := goerr.WriteStack(&w, err); werr != nil {
+		t.Fatal(werr)
+	}
+	if w.String() != goerr.Stack(err) {
+		t.Errorf("expected the same output as Stack")
+	}
+	if w.writes < 2 {
+		t.Errorf("expected the stack to be written in chunks, Got: %d writes", w.writes)
+	}
+
+	if werr := goerr.WriteStack(&countingWriter{limit: 1}, err); werr == nil || werr.Error() != "pipe closed" {
+		t.Errorf("Want: pipe closed, Got: %v", werr)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var warnings goerr.Warnings
+	warnings.Addf("slow query")
+	errs := []error{
+		goerr.New(samplesrc.Service(), 409, "controller failed"),
+		goerr.WithWarnings(goerr.WithDetails(goerr.WithField(goerr.New(nil, "failed"), "order_id", 42), []string{"a"}), &warnings),
+		goerr.Mask(goerr.New(errors.New("db down"), 503, "query failed"), "try again later"),
+	}
+
+	for _, err := range errs {
+		want, _ := json.Marshal(err)
+		var w bytes.Buffer
+		if werr := goerr.WriteJSON(&w, err); werr != nil {
+			t.Fatal(werr)
+		}
+		if w.String() != string(want) {
+			t.Errorf("Want: %s, Got: %s", want, w.String())
+		}
+	}
+
+	err := goerr.WithField(goerr.New(nil, "failed"), "ch", make(chan int))
+	if werr := goerr.WriteJSON(&bytes.Buffer{}, err); werr == nil {
+		t.Errorf("expected an encoding error")
+	}
+}
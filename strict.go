@@ -0,0 +1,103 @@
+package goerr
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+var strictMode atomic.Bool
+
+// Strict turns strict mode on or off. In strict mode, New, Mask, Wrap and
+// the other constructors panic with a descriptive error on misuse that is
+// otherwise silently tolerated:
+//
+//   - a message argument of an unsupported type, e.g. a code after the
+//     format, or an error in place of the format,
+//   - a format whose verbs do not match its arguments,
+//   - a nil error wrapped without a message, which yields a layer saying
+//     nothing but "error",
+//   - an HTTP code outside 100-599, given positionally, with Coded or with
+//     SetCode.
+//
+// It is meant for development and tests, where a panic points at the
+// offending call, and should stay off in production:
+//
+//	func TestMain(m *testing.M) {
+//		goerr.Strict(true)
+//		os.Exit(m.Run())
+//	}
+func Strict(on bool) {
+	strictMode.Store(on)
+}
+
+// misuse panics with an error describing a misuse caught in strict mode.
+func misuse(format string, args ...any) {
+	panic(fmt.Errorf("goerr: strict mode: "+format, args...))
+}
+
+// checkArgs panics if the positional message arguments are not an optional
+// int code followed by an optional format and its arguments.
+func checkArgs(args []any) {
+	i := 0
+	if len(args) > 0 {
+		if _, ok := args[0].(int); ok {
+			i = 1
+		}
+	}
+	if i < len(args) {
+		if _, ok := args[i].(string); !ok {
+			misuse("message argument %d is a %T, want a format string", i+1, args[i])
+		}
+	}
+}
+
+// checkFormat panics if the verbs of format do not consume exactly args,
+// counted like fmt does, before formatting, so arguments whose values look
+// like the markers of fmt, like an echoed upstream message, do not matter.
+// Formats with explicit argument indexes are not checked.
+func checkFormat(format string, args []any) {
+	verbs := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		for i++; i < len(format) && strings.IndexByte("+-# 0", format[i]) >= 0; i++ {
+		}
+		i = skipArgNum(format, i, &verbs)
+		if i < len(format) && format[i] == '.' {
+			i = skipArgNum(format, i+1, &verbs)
+		}
+		switch {
+		case i >= len(format):
+			misuse("format %q ends without a verb", format)
+		case format[i] == '[':
+			return
+		case format[i] != '%':
+			verbs++
+		}
+	}
+	if verbs != len(args) {
+		misuse("format %q has %d verbs for %d arguments", format, verbs, len(args))
+	}
+}
+
+// skipArgNum returns the index in format after the width or precision at i,
+// counting a '*' in verbs, as it consumes an argument.
+func skipArgNum(format string, i int, verbs *int) int {
+	if i < len(format) && format[i] == '*' {
+		*verbs++
+		return i + 1
+	}
+	for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+		i++
+	}
+	return i
+}
+
+// checkCode panics if code is neither 0 nor a valid HTTP status code.
+func checkCode(code int) {
+	if code != 0 && (code < 100 || code > 599) {
+		misuse("code %d is not a valid HTTP status code", code)
+	}
+}
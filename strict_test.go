@@ -0,0 +1,77 @@
+package goerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+// misuse returns the message of the error f panics with, or "" if it does
+// not panic.
+func misuse(f func()) (msg string) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg = r.(error).Error()
+		}
+	}()
+	f()
+	return ""
+}
+
+func TestStrict(t *testing.T) {
+	goerr.Strict(true)
+	defer goerr.Strict(false)
+
+	cause := errors.New("db down")
+	format := "order failed" // not a constant, which vet would reject
+	tests := []struct {
+		name string
+		f    func()
+		want string
+	}{
+		{"code after format", func() { goerr.New(cause, "query failed", 503) }, "has 0 verbs for 1 arguments"},
+		{"error as format", func() { goerr.New(nil, cause) }, "message argument 1 is a *errors.errorString"},
+		{"error after code", func() { goerr.New(nil, 503, cause) }, "message argument 2 is a *errors.errorString"},
+		{"missing argument", func() { goerr.New(cause, "order %s of %s failed", "42") }, "has 2 verbs for 1 arguments"},
+		{"bad Msg", func() { goerr.New(cause, goerr.Msg(format, 42)) }, "has 0 verbs for 1 arguments"},
+		{"no verb", func() { goerr.New(cause, "100%", 42) }, "ends without a verb"},
+		{"nil without message", func() { goerr.New(nil) }, "without a cause nor a message"},
+		{"nil with code only", func() { goerr.New(nil, 503) }, "without a cause nor a message"},
+		{"nil with blank message", func() { goerr.New(nil, " ") }, "without a cause nor a message"},
+		{"invalid code", func() { goerr.New(cause, 42, "query failed") }, "code 42 is not a valid HTTP status code"},
+		{"invalid Coded", func() { goerr.New(cause, goerr.Coded(1000)) }, "code 1000"},
+		{"invalid SetCode", func() { goerr.SetCode(cause, goerr.DomainHTTP, -1) }, "code -1"},
+	}
+	for _, tt := range tests {
+		got := misuse(tt.f)
+		if !strings.HasPrefix(got, "goerr: strict mode: ") || !strings.Contains(got, tt.want) {
+			t.Errorf("%s: Want: panic containing %q, Got: %q", tt.name, tt.want, got)
+		}
+	}
+
+	valid := []func(){
+		func() { goerr.New(cause) },
+		func() { goerr.New(cause, 503) },
+		func() { goerr.New(nil, "query failed") },
+		func() { goerr.New(nil, 503, "query %s failed", "orders") },
+		func() { goerr.New(nil, goerr.Msg("query failed"), goerr.Coded(503)) },
+		func() { goerr.New(cause, "100%") },
+		func() { goerr.New(cause, "upstream said %q", "%!(EXTRA int=1) (MISSING)") },
+		func() { goerr.New(cause, "%d%% of %*d done, %-8.*f", 50, 4, 10, 2, 1.5) },
+		func() { goerr.New(cause, "%[1]s and %[1]s", "twice") },
+		func() { goerr.SetCode(cause, goerr.Domain("grpc"), 14) },
+	}
+	for i, f := range valid {
+		if got := misuse(f); got != "" {
+			t.Errorf("valid call %d: Want: no panic, Got: %q", i, got)
+		}
+	}
+}
+
+func TestStrictOff(t *testing.T) {
+	if got := misuse(func() { goerr.New(nil, 42, "query failed", 503) }); got != "" {
+		t.Errorf("Want: misuse tolerated outside strict mode, Got: %q", got)
+	}
+}
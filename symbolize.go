@@ -0,0 +1,145 @@
+package goerr
+
+import (
+	"crypto/sha256"
+	"debug/buildinfo"
+	"debug/elf"
+	"debug/gosym"
+	"debug/macho"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// anchor is the function whose entry unresolved program counters are
+// recorded relative to, so that Symbolize can find them in the binary even
+// if it was loaded at another address.
+//
+//go:noinline
+func anchor() {}
+
+// anchorName is the name of anchor in the symbol table.
+const anchorName = "github.com/angel-one/goerr.anchor"
+
+var (
+	anchorPC = reflect.ValueOf(anchor).Pointer()
+
+	moduleOnce sync.Once
+	moduleHash string
+)
+
+// module returns the hash identifying the running binary, see
+// StackFrame.Module.
+func module() string {
+	moduleOnce.Do(func() {
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			moduleHash = hashBuildInfo(bi)
+		}
+	})
+	return moduleHash
+}
+
+// hashBuildInfo returns the hash of the build information of a binary.
+func hashBuildInfo(bi *debug.BuildInfo) string {
+	sum := sha256.Sum256([]byte(bi.String()))
+	return hex.EncodeToString(sum[:6])
+}
+
+// writeOffset writes the location part of a stack line for a frame whose
+// function could not be resolved.
+func writeOffset(b *strings.Builder, frame *StackFrame) {
+	b.WriteString(" [offset ")
+	if frame.Offset < 0 {
+		b.WriteString("-0x")
+		b.WriteString(strconv.FormatUint(uint64(-frame.Offset), 16))
+	} else {
+		b.WriteString("0x")
+		b.WriteString(strconv.FormatUint(uint64(frame.Offset), 16))
+	}
+	if frame.Module != "" {
+		b.WriteString(" (module ")
+		b.WriteString(frame.Module)
+		b.WriteByte(')')
+	}
+	b.WriteByte(']')
+}
+
+// Symbolize resolves the frames whose function could not be resolved when
+// they were captured, which then only have an Offset and a Module, with the
+// symbol table of the binary at binaryPath, e.g. a copy of the binary that
+// reported them. The other frames are returned unchanged. It returns an
+// error if the binary cannot be read, is neither ELF nor Mach-O, or is not
+// the one the frames were captured in.
+//
+//	frames, err := goerr.Symbolize(e.StackFrames(), "/srv/releases/v1.4.2/api")
+func Symbolize(frames []StackFrame, binaryPath string) ([]StackFrame, error) {
+	table, err := symbolTable(binaryPath)
+	if err != nil {
+		return nil, err
+	}
+	hash := ""
+	if bi, err := buildinfo.ReadFile(binaryPath); err == nil {
+		hash = hashBuildInfo(bi)
+	}
+	fn := table.LookupFunc(anchorName)
+	if fn == nil {
+		return nil, fmt.Errorf("goerr: %s has no goerr symbols", binaryPath)
+	}
+
+	resolved := make([]StackFrame, len(frames))
+	for i, frame := range frames {
+		resolved[i] = frame
+		if frame.Name != "" || frame.Offset == 0 {
+			continue
+		}
+		if frame.Module != "" && hash != "" && frame.Module != hash {
+			return nil, fmt.Errorf("goerr: frame %d was captured in module %s, not in %s of module %s", i, frame.Module, binaryPath, hash)
+		}
+
+		// pc - 1 for the same reason as in NewStackFrame
+		pc := uint64(int64(fn.Entry) + frame.Offset)
+		file, line, f := table.PCToLine(pc - 1)
+		if f == nil {
+			continue
+		}
+		resolved[i].File, resolved[i].LineNumber = file, line
+		resolved[i].Package, resolved[i].Name = splitFuncName(f.Name)
+	}
+	return resolved, nil
+}
+
+// symbolTable reads the table of functions and lines of the binary at path.
+func symbolTable(path string) (*gosym.Table, error) {
+	var pcln []byte
+	var text uint64
+
+	if f, err := elf.Open(path); err == nil {
+		defer f.Close()
+		s, t := f.Section(".gopclntab"), f.Section(".text")
+		if s == nil || t == nil {
+			return nil, fmt.Errorf("goerr: %s has no Go line table", path)
+		}
+		if pcln, err = s.Data(); err != nil {
+			return nil, err
+		}
+		text = t.Addr
+	} else if f, err := macho.Open(path); err == nil {
+		defer f.Close()
+		s, t := f.Section("__gopclntab"), f.Section("__text")
+		if s == nil || t == nil {
+			return nil, fmt.Errorf("goerr: %s has no Go line table", path)
+		}
+		if pcln, err = s.Data(); err != nil {
+			return nil, err
+		}
+		text = t.Addr
+	} else {
+		return nil, fmt.Errorf("goerr: %s is neither an ELF nor a Mach-O binary", path)
+	}
+
+	return gosym.NewTable(nil, gosym.NewLineTable(pcln, text))
+}
@@ -0,0 +1,53 @@
+package goerr
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSymbolize(t *testing.T) {
+	e := New(nil, "failed").(*Error)
+	frame := e.StackFrames()[0]
+	stripped := StackFrame{
+		ProgramCounter: frame.ProgramCounter,
+		Offset:         int64(frame.ProgramCounter - anchorPC),
+		Module:         module(),
+	}
+
+	path, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	frames, err := Symbolize([]StackFrame{stripped, frame}, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := frames[0]; got.File != frame.File || got.LineNumber != frame.LineNumber || got.Name != frame.Name || got.Package != frame.Package {
+		t.Errorf("Want: %+v, Got: %+v", frame, got)
+	}
+	if frames[1] != frame {
+		t.Errorf("expected a resolved frame to be unchanged. Want: %+v, Got: %+v", frame, frames[1])
+	}
+
+	stripped.Module = "000000000000"
+	if _, err := Symbolize([]StackFrame{stripped}, path); err == nil {
+		t.Errorf("expected an error for another module")
+	}
+	if _, err := Symbolize(nil, "symbolize.go"); err == nil {
+		t.Errorf("expected an error for a file that is not a binary")
+	}
+}
+
+func TestUnresolvedFrame(t *testing.T) {
+	frame := NewStackFrame(1)
+	if frame.Offset == 0 || frame.Module != module() {
+		t.Errorf("expected the offset and module of an unresolved frame, Got: %+v", frame)
+	}
+
+	e := New(nil, "failed").(*Error)
+	e.frames = []StackFrame{frame}
+	if stack := Stack(e); !strings.HasPrefix(stack, "failed [offset -0x") || !strings.HasSuffix(stack, ")]") {
+		t.Errorf("unexpected stack: %s", stack)
+	}
+}
@@ -0,0 +1,66 @@
+package goerr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Args are the named arguments of a message template, see NewT.
+type Args map[string]any
+
+// NewT returns nested wrapped in a new layer with a message rendered from
+// template, in which every {name} placeholder is replaced by the argument of
+// that name. The arguments are also set as fields of the layer, so one call
+// gives both a readable message and machine readable data.
+//
+// The registered redactors apply to the arguments before they are put in
+// the message, so a secret argument does not leak through it. Placeholders
+// without an argument are kept as they are.
+//
+//	err = goerr.NewT(err, "order {order_id} failed for {user}", goerr.Args{"order_id": id, "user": u})
+func NewT(nested error, template string, args Args) error {
+	e := newTemplated(nil, nested, template, args, 1)
+	notify(e)
+	return e
+}
+
+// newTemplated creates the layer of NewT for the factory f, or the package
+// if f is nil. skip is the same as for newError. Hooks are not notified.
+func newTemplated(f *Factory, nested error, template string, args Args, skip int) *Error {
+	e := newError(nested, []any{"%s", expand(f, template, args)}, skip+1)
+	e.factory = f
+	if len(args) > 0 {
+		e.withFields(args)
+	}
+	return e
+}
+
+// expand replaces the {name} placeholders of template by the arguments of
+// that name, redacted with the redactors of f.
+func expand(f *Factory, template string, args Args) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		name := template[start+1 : end]
+		v, ok := args[name]
+		if !ok {
+			b.WriteString(template[:start+1])
+			template = template[start+1:]
+			continue
+		}
+		b.WriteString(template[:start])
+		b.WriteString(fmt.Sprint(redactField(f, name, v)))
+		template = template[end+1:]
+	}
+	b.WriteString(template)
+	return b.String()
+}
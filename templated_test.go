@@ -0,0 +1,44 @@
+package goerr_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestNewT(t *testing.T) {
+	nested := errors.New("insufficient funds")
+	err := goerr.NewT(nested, "order {order_id} failed for {user} ({missing}) {", goerr.Args{"order_id": 42, "user": "alice"})
+
+	if want := "order 42 failed for alice ({missing}) {"; err.Error() != want {
+		t.Errorf("Want: %s, Got: %s", want, err.Error())
+	}
+	if want := map[string]any{"order_id": 42, "user": "alice"}; !reflect.DeepEqual(goerr.Fields(err), want) {
+		t.Errorf("Want: %v, Got: %v", want, goerr.Fields(err))
+	}
+	if !errors.Is(err, nested) {
+		t.Errorf("expected the nested error to be wrapped")
+	}
+	if !strings.HasSuffix(err.(*goerr.Error).Func(), "TestNewT") {
+		t.Errorf("Want: the caller of NewT, Got: %s", err.(*goerr.Error).Func())
+	}
+	if got := goerr.NewT(nil, "100% {done}", nil).Error(); got != "100% {done}" {
+		t.Errorf("Want: 100%% {done}, Got: %s", got)
+	}
+}
+
+func TestNewTRedactsArgs(t *testing.T) {
+	t.Cleanup(func() { goerr.Configure(goerr.Options{}) })
+	goerr.AddRedactor(goerr.KeyRedactor("otp"))
+
+	err := goerr.NewT(nil, "otp {otp} rejected", goerr.Args{"otp": 123456})
+	if want := "otp " + goerr.Redacted + " rejected"; err.Error() != want {
+		t.Errorf("Want: %s, Got: %s", want, err.Error())
+	}
+	if got := goerr.Fields(err)["otp"]; got != goerr.Redacted {
+		t.Errorf("Want: %s, Got: %v", goerr.Redacted, got)
+	}
+}
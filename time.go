@@ -0,0 +1,47 @@
+package goerr
+
+import (
+	"strings"
+	"time"
+)
+
+// occurred returns the time at which the outermost goerr layer of err was
+// created, or the zero time if err has no goerr layer.
+func occurred(err error) time.Time {
+	e := find(err, func(e *Error) bool { return !e.created.IsZero() })
+	if e == nil {
+		return time.Time{}
+	}
+	return e.created
+}
+
+// FirstOccurred returns the time at which the innermost goerr layer of err
+// was created, that is when the error was first raised or wrapped, or the
+// zero time if err has no goerr layer. The difference to the time an error is
+// handled shows how long it was passed around, e.g. in a retry loop.
+func FirstOccurred(err error) time.Time {
+	var first time.Time
+	walk(err, func(_ int, err error) bool {
+		if e, ok := err.(*Error); ok && !e.created.IsZero() {
+			first = e.created
+		}
+		return true
+	})
+	return first
+}
+
+// writeElapsed writes the time elapsed between the creation of the next goerr
+// layer inside tail and e, where tail is e or the last of the layers
+// repeating e. Nothing is written for the innermost layer.
+func writeElapsed(b *strings.Builder, e, tail *Error) {
+	if tail.err == nil || e.created.IsZero() {
+		return
+	}
+	inner := occurred(tail.err)
+	if inner.IsZero() {
+		return
+	}
+
+	b.WriteString(" +")
+	b.WriteString(e.created.Sub(inner).String())
+}
@@ -0,0 +1,48 @@
+package goerr_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/angel-one/goerr"
+	"github.com/angel-one/goerr/samplesrc"
+)
+
+func TestFirstOccurred(t *testing.T) {
+	if got := goerr.FirstOccurred(errors.New("plain")); !got.IsZero() {
+		t.Errorf("Want: zero time, Got: %v", got)
+	}
+
+	before := time.Now()
+	inner := goerr.New(nil, "timeout")
+	time.Sleep(10 * time.Millisecond)
+	err := goerr.New(goerr.New(inner, "retry failed"), "handler failed")
+
+	got := goerr.FirstOccurred(err)
+	if got.Before(before) || !got.Before(before.Add(10*time.Millisecond)) {
+		t.Errorf("Want: time of the inner layer, Got: %v", got)
+	}
+	if got := goerr.FirstOccurred(goerr.WithField(err, "k", "v")); got != goerr.FirstOccurred(err) {
+		t.Errorf("expected enrichment to keep the time, Got: %v", got)
+	}
+}
+
+func TestStackVerboseElapsed(t *testing.T) {
+	inner := goerr.New(nil, "timeout")
+	time.Sleep(10 * time.Millisecond)
+	err := goerr.New(inner, "retry failed")
+
+	got := goerr.StackVerbose(err)
+	if !regexp.MustCompile(`retry failed \[[^\n]*\] \+[1-9]\d*(\.\d+)?ms\n`).MatchString(got) {
+		t.Errorf("expected the elapsed time on the outer layer, Got: %s", got)
+	}
+	if regexp.MustCompile(`timeout \[[^\n]*\] \+`).MatchString(got) {
+		t.Errorf("unexpected elapsed time on the inner layer, Got: %s", got)
+	}
+
+	if got := goerr.StackVerbose(samplesrc.Controller()); !regexp.MustCompile(`controller failed \[[^\n]*\] \+\d`).MatchString(got) {
+		t.Errorf("expected the elapsed time, Got: %s", got)
+	}
+}
@@ -0,0 +1,81 @@
+package goerr
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Fields in which NewCtx records the trace context.
+const (
+	TraceIDField = "trace_id"
+	SpanIDField  = "span_id"
+)
+
+// A TraceExtractor returns the IDs of the trace and of the span current in
+// ctx, or empty strings if there is none.
+type TraceExtractor func(ctx context.Context) (traceID, spanID string)
+
+var traceExtractor atomic.Pointer[TraceExtractor]
+
+// SetTraceExtractor sets the function NewCtx reads the trace context with,
+// so that goerr does not depend on a tracing library. With OpenTelemetry:
+//
+//	goerr.SetTraceExtractor(func(ctx context.Context) (string, string) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		if !sc.IsValid() {
+//			return "", ""
+//		}
+//		return sc.TraceID().String(), sc.SpanID().String()
+//	})
+//
+// Passing nil removes the extractor.
+func SetTraceExtractor(f TraceExtractor) {
+	if f == nil {
+		traceExtractor.Store(nil)
+		return
+	}
+	traceExtractor.Store(&f)
+}
+
+// NewCtx is like New, but records the IDs of the trace and span current in
+// ctx, as returned by the TraceExtractor, in the TraceIDField and SpanIDField
+// fields of the layer. Stack and the JSON marshaler render them like other
+// fields, so log lines can be looked up from a trace and the other way
+// around.
+//
+// NewCtx also records the deadline of ctx, if it has one. If the chain
+// below the layer has context.DeadlineExceeded, Stack renders the deadline
+// and how long after it the layer was created, see Deadline.
+//
+//	return goerr.NewCtx(ctx, err, http.StatusBadGateway, "payment provider failed")
+func NewCtx(ctx context.Context, nested error, message ...any) error {
+	e := newCtxError(ctx, nested, message, 1)
+	notify(e)
+	return e
+}
+
+// newCtxError creates the layer of NewCtx. skip is the same as for newError.
+// Hooks are not notified.
+func newCtxError(ctx context.Context, nested error, message []any, skip int) *Error {
+	e := newError(nested, message, skip+1)
+	if ctx == nil {
+		return e
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		e.deadline = deadline
+	}
+	if f := traceExtractor.Load(); f != nil {
+		traceID, spanID := (*f)(ctx)
+		fields := make(map[string]any, 2)
+		if traceID != "" {
+			fields[TraceIDField] = traceID
+		}
+		if spanID != "" {
+			fields[SpanIDField] = spanID
+		}
+		if len(fields) > 0 {
+			e.withFields(fields)
+		}
+	}
+	return e
+}
@@ -0,0 +1,61 @@
+package goerr_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+type spanKey struct{}
+
+func TestNewCtx(t *testing.T) {
+	goerr.SetTraceExtractor(func(ctx context.Context) (string, string) {
+		ids, _ := ctx.Value(spanKey{}).([2]string)
+		return ids[0], ids[1]
+	})
+	defer goerr.SetTraceExtractor(nil)
+
+	ctx := context.WithValue(context.Background(), spanKey{}, [2]string{"4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"})
+	err := goerr.NewCtx(ctx, nil, 502, "payment provider failed", goerr.F("provider", "acme"))
+
+	want := map[string]any{
+		goerr.TraceIDField: "4bf92f3577b34da6a3ce929d0e0e4736",
+		goerr.SpanIDField:  "00f067aa0ba902b7",
+		"provider":         "acme",
+	}
+	got := goerr.Fields(err)
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s. Want: %v, Got: %v", k, v, got[k])
+		}
+	}
+	if goerr.Code(err) != 502 {
+		t.Errorf("code. Want: 502, Got: %d", goerr.Code(err))
+	}
+
+	if stack := goerr.Stack(err); !strings.Contains(stack, "trace_id=4bf92f3577b34da6a3ce929d0e0e4736") {
+		t.Errorf("stack does not contain the trace ID: %s", stack)
+	}
+	b, _ := json.Marshal(err)
+	if !strings.Contains(string(b), `"span_id":"00f067aa0ba902b7"`) {
+		t.Errorf("json does not contain the span ID: %s", b)
+	}
+
+	if got := goerr.Fields(goerr.NewCtx(context.Background(), nil, "no span")); len(got) != 0 {
+		t.Errorf("expected no fields without a span, Got: %v", got)
+	}
+}
+
+func TestNewCtxWithoutExtractor(t *testing.T) {
+	err := goerr.NewCtx(context.Background(), nil, "failed")
+
+	if got := goerr.Fields(err); len(got) != 0 {
+		t.Errorf("expected no fields, Got: %v", got)
+	}
+	if want, got := "failed", err.Error(); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+}
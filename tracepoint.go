@@ -0,0 +1,64 @@
+package goerr
+
+import (
+	"strconv"
+	"strings"
+)
+
+// traceMarker starts the stack line of a layer created by Trace.
+const traceMarker = "↳"
+
+// Trace returns err wrapped in a new layer which only records where it was
+// passed through, without a message of its own: its message is the one of
+// err, and Stack renders it as "↳ at file:line (function)" instead of
+// repeating the message. It keeps stacks precise in functions that pass
+// errors on without anything to add, where a message would be contrived.
+// It returns nil if err is nil. A layer created by Trace and then given a
+// code, a severity or fields is rendered like any other layer.
+//
+//	if err := s.repo.Save(ctx, order); err != nil {
+//		return goerr.Trace(err)
+//	}
+func Trace(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	e := newTrace(err, 1)
+	notify(e)
+	return e
+}
+
+// newTrace creates the layer of Trace. skip is the same as for newError.
+// Hooks are not notified.
+func newTrace(err error, skip int) *Error {
+	e := newError(err, nil, skip+1)
+	e.trace = true
+	return e
+}
+
+// traceOnly reports whether e is rendered as a trace, see Trace.
+func (e *Error) traceOnly() bool {
+	return e.trace && e.code == 0 && e.severity == 0 && len(e.fields) == 0
+}
+
+// writeTrace writes the stack line of a layer created by Trace.
+func (e *Error) writeTrace(b *strings.Builder) {
+	var num [20]byte
+
+	b.WriteString(traceMarker)
+	frame := &e.frames[0]
+	if frame.File == "" {
+		if frame.Offset != 0 {
+			writeOffset(b, frame)
+		}
+		return
+	}
+	b.WriteString(" at ")
+	b.WriteString(formatPath(e.factory, frame))
+	b.WriteByte(':')
+	b.Write(strconv.AppendInt(num[:0], int64(frame.LineNumber), 10))
+	b.WriteString(" (")
+	b.WriteString(formatFuncName(e.factory, e.Func()))
+	b.WriteByte(')')
+}
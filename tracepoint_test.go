@@ -0,0 +1,53 @@
+package goerr_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestTrace(t *testing.T) {
+	root := errors.New("no rows")
+	inner := goerr.New(root, 404, "order not found")
+	err := goerr.Trace(inner)
+
+	if want, got := "order not found", err.Error(); got != want {
+		t.Errorf("Want: %s, Got: %s", want, got)
+	}
+	if goerr.Code(err) != 404 || !errors.Is(err, root) {
+		t.Errorf("expected the code and cause to be kept")
+	}
+
+	e := err.(*goerr.Error)
+	lines := strings.Split(goerr.Stack(err), "\n")
+	want := "↳ at " + e.File() + ":" + strconv.Itoa(e.Line()) + " (goerr_test.TestTrace)"
+	if lines[1] != want {
+		t.Errorf("Want: %s, Got: %s", want, lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "\torder not found (404) [") {
+		t.Errorf("expected the wrapped layer below, Got: %s", lines[2])
+	}
+
+	frames, perr := goerr.ParseStack(goerr.Stack(err))
+	if perr != nil {
+		t.Fatal(perr)
+	}
+	if len(frames) != 3 || frames[0].Message != "" || frames[0].Line != e.Line() || frames[0].Function != "goerr_test.TestTrace" || frames[1].Message != "order not found" {
+		t.Errorf("unexpected frames: %+v", frames)
+	}
+
+	if goerr.Trace(nil) != nil {
+		t.Errorf("expected nil for nil")
+	}
+}
+
+func TestTraceEnriched(t *testing.T) {
+	err := goerr.WithField(goerr.Trace(errors.New("timeout")), "attempt", 3)
+
+	if line := strings.Split(goerr.Stack(err), "\n")[1]; !strings.HasPrefix(line, "timeout attempt=3 [") {
+		t.Errorf("expected an enriched trace to be rendered like a layer, Got: %s", line)
+	}
+}
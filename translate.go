@@ -0,0 +1,127 @@
+package goerr
+
+import (
+	"errors"
+	"regexp"
+	"sync"
+)
+
+// A Translation maps errors of an upstream dependency, like a driver or an
+// SDK, to a canonical kind, code and public message. See RegisterTranslation.
+type Translation struct {
+	// Match reports whether the translation applies to an error, see
+	// MatchIs, MatchType and MatchMessage.
+	Match func(err error) bool
+	// Kind and Code are set on the translated error, if not empty.
+	Kind Kind
+	Code int
+	// Message, if not empty, is the public message of the translated error,
+	// masking the upstream error for public renderers (see Mask).
+	Message string
+}
+
+var (
+	translationsMu sync.RWMutex
+	translations   []Translation
+)
+
+// RegisterTranslation adds t to the translations applied by Translate. The
+// first registered translation matching an error applies.
+//
+//	goerr.RegisterTranslation(goerr.Translation{
+//		Match:   goerr.MatchType[*smithy.OperationError](),
+//		Kind:    goerr.KindUnavailable,
+//		Message: "storage is unavailable",
+//	})
+func RegisterTranslation(t Translation) {
+	translationsMu.Lock()
+	defer translationsMu.Unlock()
+	translations = append(translations, t)
+}
+
+// MatchIs matches errors that wrap target, as reported by errors.Is.
+func MatchIs(target error) func(err error) bool {
+	return func(err error) bool {
+		return is(err, target)
+	}
+}
+
+// MatchType matches errors that wrap an error of type T, as reported by
+// errors.As.
+func MatchType[T error]() func(err error) bool {
+	return func(err error) bool {
+		var target T
+		return errors.As(err, &target)
+	}
+}
+
+// MatchMessage matches errors of which a layer has a message matching the
+// regular expression pattern. It panics if pattern does not compile.
+func MatchMessage(pattern string) func(err error) bool {
+	re := regexp.MustCompile(pattern)
+	return func(err error) bool {
+		matched := false
+		walk(err, func(_ int, err error) bool {
+			matched = re.MatchString(err.Error())
+			return !matched
+		})
+		return matched
+	}
+}
+
+// Translate applies the first registered translation matching err. Its kind
+// and code are set on the outermost layer of err, which is wrapped in a new
+// goerr first if it is not a goerr. If the translation has a message, err is
+// instead wrapped in a new layer with the message, kind and code, which
+// masks err for public renderers. Translate returns err unchanged if no
+// translation matches it.
+//
+//	row, err := client.GetItem(ctx, input)
+//	if err != nil {
+//		return goerr.Translate(err)
+//	}
+func Translate(err error) error {
+	return translate(nil, err)
+}
+
+// translate implements Translate, giving the masking layer it creates, if
+// any, the settings of f, or the global ones if f is nil.
+func translate(f *Factory, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	t, ok := translationOf(err)
+	if !ok {
+		return err
+	}
+
+	if t.Message != "" {
+		e := newError(err, []any{t.Message}, 2)
+		e.masks = true
+		e.kind, e.code = t.Kind, t.Code
+		return f.finish(e)
+	}
+
+	e := layer(err)
+	if t.Kind != "" {
+		e.kind = t.Kind
+	}
+	if t.Code != 0 {
+		e.code = t.Code
+	}
+	return e
+}
+
+// translationOf returns the first registered translation matching err.
+func translationOf(err error) (Translation, bool) {
+	translationsMu.RLock()
+	defer translationsMu.RUnlock()
+
+	for _, t := range translations {
+		if t.Match(err) {
+			return t, true
+		}
+	}
+	return Translation{}, false
+}
@@ -0,0 +1,98 @@
+package goerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+var errThrottled = errors.New("upstream: throttled")
+
+type quotaError struct {
+	Limit int
+}
+
+func (e *quotaError) Error() string { return "quota exceeded" }
+
+func init() {
+	goerr.RegisterTranslation(goerr.Translation{
+		Match: goerr.MatchIs(errThrottled),
+		Kind:  goerr.KindTooManyRequests,
+	})
+	goerr.RegisterTranslation(goerr.Translation{
+		Match:   goerr.MatchType[*quotaError](),
+		Code:    http.StatusTooManyRequests,
+		Message: "try again tomorrow",
+	})
+	goerr.RegisterTranslation(goerr.Translation{
+		Match:   goerr.MatchMessage(`^pq: relation "\w+" does not exist$`),
+		Kind:    goerr.KindInternal,
+		Message: "internal error",
+	})
+}
+
+func TestTranslateIs(t *testing.T) {
+	err := goerr.Translate(goerr.New(errThrottled, "call payments failed"))
+
+	if goerr.KindOf(err) != goerr.KindTooManyRequests || goerr.Code(err) != http.StatusTooManyRequests {
+		t.Errorf("Want: %s (429), Got: %s (%d)", goerr.KindTooManyRequests, goerr.KindOf(err), goerr.Code(err))
+	}
+	if err.Error() != "call payments failed" {
+		t.Errorf("expected the outermost layer to be enriched, Got: %s", err.Error())
+	}
+	if n := strings.Count(goerr.Stack(err), "\n"); n != 2 {
+		t.Errorf("expected no new layer, Got: %s", goerr.Stack(err))
+	}
+}
+
+func TestTranslateType(t *testing.T) {
+	err := goerr.Translate(&quotaError{Limit: 100})
+
+	if goerr.Code(err) != http.StatusTooManyRequests {
+		t.Errorf("code. Want: 429, Got: %d", goerr.Code(err))
+	}
+	if got := goerr.PublicMessage(err); got != "try again tomorrow" {
+		t.Errorf("Want: try again tomorrow, Got: %s", got)
+	}
+	b, _ := json.Marshal(err)
+	if strings.Contains(string(b), "quota exceeded") {
+		t.Errorf("expected the upstream error to be masked, Got: %s", b)
+	}
+	if !strings.Contains(goerr.Stack(err), "quota exceeded") {
+		t.Errorf("expected the upstream error in the stack, Got: %s", goerr.Stack(err))
+	}
+	if got := err.(*goerr.Error).Func(); !strings.HasSuffix(got, "TestTranslateType") {
+		t.Errorf("Want: the caller of Translate, Got: %s", got)
+	}
+}
+
+func TestTranslateMessage(t *testing.T) {
+	err := goerr.Translate(goerr.New(errors.New(`pq: relation "orders" does not exist`), "query failed"))
+	if goerr.KindOf(err) != goerr.KindInternal || goerr.PublicMessage(err) != "internal error" {
+		t.Errorf("Want: internal error, Got: %s (%s)", goerr.PublicMessage(err), goerr.KindOf(err))
+	}
+}
+
+func TestTranslateNoMatch(t *testing.T) {
+	plain := errors.New("boom")
+	if err := goerr.Translate(plain); err != plain {
+		t.Errorf("Want: the error unchanged, Got: %v", err)
+	}
+	if goerr.Translate(nil) != nil {
+		t.Errorf("Want: nil")
+	}
+}
+
+func TestMatchIsNil(t *testing.T) {
+	match := goerr.MatchIs(nil)
+	if match(goerr.New(errors.New("boom"), "failed")) {
+		t.Errorf("expected an error not to match a nil target")
+	}
+	if !match(nil) {
+		t.Errorf("expected nil to match a nil target, like errors.Is")
+	}
+}
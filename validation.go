@@ -0,0 +1,66 @@
+package goerr
+
+// A Violation is a field of a request that failed validation.
+type Violation struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// Validation collects the violations found while validating a request, see
+// NewValidation. It is not safe for concurrent use.
+type Validation struct {
+	violations []Violation
+	factory    *Factory
+}
+
+// NewValidation returns an empty Validation.
+//
+//	v := goerr.NewValidation()
+//	if !strings.Contains(req.Email, "@") {
+//		v.Field("email", "must be valid")
+//	}
+//	if req.Age < 18 {
+//		v.Field("age", "must be at least 18")
+//	}
+//	return v.Err()
+func NewValidation() *Validation {
+	return &Validation{}
+}
+
+// Field adds a violation of field, and returns v.
+func (v *Validation) Field(field, reason string) *Validation {
+	v.violations = append(v.violations, Violation{Field: field, Reason: reason})
+	return v
+}
+
+// Len returns the number of violations.
+func (v *Validation) Len() int {
+	return len(v.violations)
+}
+
+// Err returns nil if there are no violations. Otherwise it returns a new
+// layer, with the settings of the factory v was created by if any, with the code of KindUnprocessable (422), the message "validation
+// failed" and the violations, in the order they were added, as details.
+func (v *Validation) Err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+
+	e := newError(nil, []any{KindUnprocessable.Code(), "validation failed"}, 1)
+	e.kind = KindUnprocessable
+	e.details = append([]Violation(nil), v.violations...)
+	return v.factory.finish(e)
+}
+
+// Violations returns the violations of the error returned by Validation.Err
+// in the chain of err, or nil if there is none.
+func Violations(err error) []Violation {
+	e := find(err, func(e *Error) bool {
+		_, ok := e.details.([]Violation)
+		return ok
+	})
+	if e == nil {
+		return nil
+	}
+	return append([]Violation(nil), e.details.([]Violation)...)
+}
@@ -0,0 +1,47 @@
+package goerr_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestValidation(t *testing.T) {
+	v := goerr.NewValidation()
+	if v.Err() != nil {
+		t.Errorf("Want: nil")
+	}
+
+	v.Field("email", "must be valid").Field("age", "must be at least 18")
+	err := v.Err()
+
+	if goerr.Code(err) != http.StatusUnprocessableEntity || goerr.KindOf(err) != goerr.KindUnprocessable {
+		t.Errorf("Want: unprocessable (422), Got: %s (%d)", goerr.KindOf(err), goerr.Code(err))
+	}
+	if v.Len() != 2 || err.Error() != "validation failed" {
+		t.Errorf("Want: validation failed with 2 violations, Got: %s with %d", err.Error(), v.Len())
+	}
+	if !strings.HasSuffix(err.(*goerr.Error).Func(), "TestValidation") {
+		t.Errorf("Want: the caller of Err, Got: %s", err.(*goerr.Error).Func())
+	}
+
+	want := []goerr.Violation{{Field: "email", Reason: "must be valid"}, {Field: "age", Reason: "must be at least 18"}}
+	v.Field("name", "is required")
+	wrapped := goerr.New(err, "create user failed")
+	if got := goerr.Violations(wrapped); !reflect.DeepEqual(got, want) {
+		t.Errorf("Want: %v, Got: %v", want, got)
+	}
+	if goerr.Violations(goerr.New(nil, "failed")) != nil {
+		t.Errorf("Want: no violations")
+	}
+
+	b, _ := json.Marshal(err)
+	wantJSON := `{"message":"validation failed","code":422,"details":[{"field":"email","reason":"must be valid"},{"field":"age","reason":"must be at least 18"}],"layers":[`
+	if !strings.HasPrefix(string(b), wantJSON) {
+		t.Errorf("Want: %s..., Got: %s", wantJSON, b)
+	}
+}
@@ -0,0 +1,47 @@
+package goerr
+
+// A Key identifies a typed value attached to an error with With. Values are
+// stored as fields named after the key, so they are rendered and redacted
+// like any other field.
+type Key[T any] struct {
+	name string
+}
+
+// NewKey returns a key for values of type T stored in the field name.
+//
+//	var OrderID = goerr.NewKey[int64]("order_id")
+func NewKey[T any](name string) Key[T] {
+	return Key[T]{name: name}
+}
+
+// Name returns the name of the field the key stores its values in.
+func (k Key[T]) Name() string {
+	return k.name
+}
+
+// With returns err with value stored under key on its outermost layer. If err
+// is not a goerr, it is wrapped in a new goerr first.
+func With[T any](err error, key Key[T], value T) error {
+	if err == nil {
+		return nil
+	}
+
+	return layer(err).withFields(map[string]any{key.name: value})
+}
+
+// Value returns the value stored under key by the outermost layer of err that
+// has one. It reports false if there is none, or if the field of the key was
+// set to a value of another type with WithField.
+func Value[T any](err error, key Key[T]) (T, bool) {
+	e := find(err, func(e *Error) bool {
+		_, ok := e.fields[key.name]
+		return ok
+	})
+	if e == nil {
+		var zero T
+		return zero, false
+	}
+
+	v, ok := e.fields[key.name].(T)
+	return v, ok
+}
@@ -0,0 +1,44 @@
+package goerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+type OrderID int64
+
+var (
+	orderIDKey = goerr.NewKey[OrderID]("order_id")
+	retriesKey = goerr.NewKey[int]("retries")
+)
+
+func TestTypedValues(t *testing.T) {
+	repository := func() error {
+		return goerr.With(goerr.New(errors.New("duplicate key"), 409, "order insert failed"), orderIDKey, 42)
+	}
+	err := goerr.New(repository(), "service error")
+
+	id, ok := goerr.Value(err, orderIDKey)
+	if !ok || id != 42 {
+		t.Errorf("Want: %d, Got: %d (%v)", 42, id, ok)
+	}
+
+	if _, ok := goerr.Value(err, retriesKey); ok {
+		t.Errorf("expected no value for %s", retriesKey.Name())
+	}
+
+	if stack := goerr.Stack(err); !strings.Contains(stack, "order insert failed (409) order_id=42 [") {
+		t.Errorf("stack do not contain the value. %s", stack)
+	}
+}
+
+func TestTypedValueTypeMismatch(t *testing.T) {
+	err := goerr.WithField(goerr.New(nil, "failed"), "retries", "three")
+
+	if v, ok := goerr.Value(err, retriesKey); ok {
+		t.Errorf("expected no value of another type, Got: %v", v)
+	}
+}
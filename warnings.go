@@ -0,0 +1,102 @@
+package goerr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Warnings accumulates non-fatal problems, like validation warnings or items
+// skipped by a partial batch, to be attached to the final error with
+// WithWarnings or returned along with a successful result. It is safe for
+// concurrent use; the zero value is ready to use.
+//
+//	type ImportResult struct {
+//		Imported int            `json:"imported"`
+//		Warnings *goerr.Warnings `json:"warnings,omitempty"`
+//	}
+type Warnings struct {
+	mu   sync.Mutex
+	list []error
+}
+
+// Add appends warning, unless it is nil.
+func (w *Warnings) Add(warning error) {
+	if warning == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.list = append(w.list, warning)
+}
+
+// Addf appends a warning with the formatted message.
+func (w *Warnings) Addf(format string, args ...any) {
+	w.Add(fmt.Errorf(format, args...))
+}
+
+// Len returns the number of warnings.
+func (w *Warnings) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.list)
+}
+
+// List returns the warnings in the order they were added.
+func (w *Warnings) List() []error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]error(nil), w.list...)
+}
+
+// MarshalJSON renders the messages of the warnings, with the registered
+// redactors applied.
+func (w *Warnings) MarshalJSON() ([]byte, error) {
+	return json.Marshal(warningMessages(nil, w.List()))
+}
+
+// WithWarnings returns err with the warnings added so far to w attached to
+// its outermost layer. Stack renders them below the line of the layer and
+// the JSON marshaler under the "warnings" key. If err is not a goerr, it is
+// wrapped in a new goerr first.
+func WithWarnings(err error, w *Warnings) error {
+	if err == nil {
+		return nil
+	}
+
+	e := layer(err)
+	e.warnings = append(e.warnings[:len(e.warnings):len(e.warnings)], w.List()...)
+	return e
+}
+
+// ListWarnings returns the warnings attached to all layers of err, outermost
+// first.
+func ListWarnings(err error) []error {
+	var list []error
+	walk(err, func(_ int, err error) bool {
+		if e, ok := err.(*Error); ok {
+			list = append(list, e.warnings...)
+		}
+		return true
+	})
+	return list
+}
+
+// warningMessages returns the redacted messages of warnings.
+func warningMessages(f *Factory, warnings []error) []string {
+	msgs := make([]string, len(warnings))
+	for i, w := range warnings {
+		msgs[i] = redactMessage(f, w.Error())
+	}
+	return msgs
+}
+
+// writeWarnings writes the warnings of e, indented by depth tabs.
+func writeWarnings(b *strings.Builder, e *Error, depth int) {
+	for _, msg := range warningMessages(e.factory, e.warnings) {
+		writeIndent(b, depth)
+		b.WriteString("warning: ")
+		b.WriteString(msg)
+	}
+}
@@ -0,0 +1,88 @@
+package goerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestWarnings(t *testing.T) {
+	var w goerr.Warnings
+	w.Add(nil)
+	w.Add(errors.New("row 3: unknown currency, defaulted to INR"))
+	w.Addf("row %d: duplicate order", 7)
+
+	if w.Len() != 2 {
+		t.Errorf("Want: 2, Got: %d", w.Len())
+	}
+
+	result := struct {
+		Imported int             `json:"imported"`
+		Warnings *goerr.Warnings `json:"warnings"`
+	}{8, &w}
+	b, _ := json.Marshal(result)
+	want := `{"imported":8,"warnings":["row 3: unknown currency, defaulted to INR","row 7: duplicate order"]}`
+	if string(b) != want {
+		t.Errorf("Want: %s, Got: %s", want, b)
+	}
+}
+
+func TestWarningsConcurrent(t *testing.T) {
+	var w goerr.Warnings
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			w.Addf("item %d skipped", i)
+		}(i)
+	}
+	wg.Wait()
+	if w.Len() != 20 {
+		t.Errorf("Want: 20, Got: %d", w.Len())
+	}
+}
+
+func TestWithWarnings(t *testing.T) {
+	var w goerr.Warnings
+	w.Addf("row 3: unknown currency")
+	inner := goerr.WithWarnings(goerr.New(nil, "row 9 invalid"), &w)
+
+	var outer goerr.Warnings
+	outer.Addf("import took 12s")
+	err := goerr.WithWarnings(goerr.New(inner, "import failed"), &outer)
+	w.Addf("added later")
+
+	list := goerr.ListWarnings(err)
+	if len(list) != 2 || list[0].Error() != "import took 12s" || list[1].Error() != "row 3: unknown currency" {
+		t.Errorf("Want: both warnings, outermost first, Got: %v", list)
+	}
+	if goerr.ListWarnings(goerr.New(nil, "failed")) != nil {
+		t.Errorf("Want: no warnings")
+	}
+
+	lines := strings.Split(goerr.Stack(err), "\n")[1:]
+	want := []string{
+		"import failed [",
+		"\twarning: import took 12s",
+		"\trow 9 invalid [",
+		"\t\twarning: row 3: unknown currency",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("nr. of lines. Want: %d, Got: %d\n%s", len(want), len(lines), goerr.Stack(err))
+	}
+	for i := range want {
+		if !strings.HasPrefix(lines[i], want[i]) {
+			t.Errorf("line %d. Want prefix: %q, Got: %q", i, want[i], lines[i])
+		}
+	}
+
+	b, _ := json.Marshal(err)
+	if !strings.Contains(string(b), `"warnings":["import took 12s","row 3: unknown currency"]`) {
+		t.Errorf("expected the warnings in the JSON output, Got: %s", b)
+	}
+}
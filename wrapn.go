@@ -0,0 +1,32 @@
+package goerr
+
+// Wrap2 returns v and err wrapped in a new layer with message, or v and nil
+// if err is nil, so that a function passing on the results of a call can
+// return them in one statement. v is returned as it is in both cases. The
+// layer is attributed to the caller of Wrap2, like with New.
+//
+//	order, err := repo.Get(ctx, id)
+//	return goerr.Wrap2(order, err, "get order failed")
+func Wrap2[T any](v T, err error, message string) (T, error) {
+	if err == nil {
+		return v, nil
+	}
+
+	e := newError(err, []any{message}, 1)
+	notify(e)
+	return v, e
+}
+
+// Wrap3 is Wrap2 for calls returning two values and an error.
+//
+//	items, next, err := repo.List(ctx, cursor)
+//	return goerr.Wrap3(items, next, err, "list orders failed")
+func Wrap3[T, U any](v T, u U, err error, message string) (T, U, error) {
+	if err == nil {
+		return v, u, nil
+	}
+
+	e := newError(err, []any{message}, 1)
+	notify(e)
+	return v, u, e
+}
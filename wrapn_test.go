@@ -0,0 +1,50 @@
+package goerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/angel-one/goerr"
+)
+
+func TestWrap2(t *testing.T) {
+	get := func(fail bool) (int, error) {
+		if fail {
+			return 0, errors.New("no rows")
+		}
+		return 42, nil
+	}
+
+	v, err := get(false)
+	if v, err := goerr.Wrap2(v, err, "get failed"); v != 42 || err != nil {
+		t.Errorf("Want: 42 <nil>, Got: %d %v", v, err)
+	}
+
+	v, err = get(true)
+	v, err = goerr.Wrap2(v, err, "get failed")
+	if v != 0 || err == nil || err.Error() != "get failed" {
+		t.Fatalf("Want: 0 get failed, Got: %d %v", v, err)
+	}
+	if got := goerr.ListErrors(err); len(got) != 2 || got[1] != "no rows" {
+		t.Errorf("expected the error to be wrapped, Got: %v", got)
+	}
+	if !strings.HasSuffix(err.(*goerr.Error).Func(), "TestWrap2") {
+		t.Errorf("Want: the caller of Wrap2, Got: %s", err.(*goerr.Error).Func())
+	}
+}
+
+func TestWrap3(t *testing.T) {
+	items, next, err := goerr.Wrap3([]string{"a"}, "cursor", nil, "list failed")
+	if len(items) != 1 || next != "cursor" || err != nil {
+		t.Errorf("Want: [a] cursor <nil>, Got: %v %s %v", items, next, err)
+	}
+
+	_, _, err = goerr.Wrap3([]string(nil), "", errors.New("timeout"), "list failed")
+	if err == nil || err.Error() != "list failed" {
+		t.Fatalf("Want: list failed, Got: %v", err)
+	}
+	if !strings.HasSuffix(err.(*goerr.Error).Func(), "TestWrap3") {
+		t.Errorf("Want: the caller of Wrap3, Got: %s", err.(*goerr.Error).Func())
+	}
+}